@@ -22,7 +22,7 @@ func main() {
 	}
 
 	cfg := config.LoadConfig()
-	app := app.NewApp(cfg.DBConnString, cfg.JWTManager)
+	app := app.NewApp(cfg)
 	defer app.DB.Close()
 
 	mux := router.SetupRouter(app, cfg.JWTManager, "1") // Pass dbQueries to your router
@@ -9,12 +9,17 @@ import (
 type API struct {
 	AuthH             *handler.AuthHandler
 	AuthM             *handler.AuthMiddleware
+	CoachingH         *handler.CoachingHandler
 	EquipmentH        *handler.EquipmentHandler
 	ExerciseCategoryH *handler.ExerciseCategoryHandler
 	ExerciseH         *handler.ExerciseHandler
+	LogH              *handler.LogHandler
 	TrainingTypeH     *handler.TrainingTypeHandler
 	MuscleGroupH      *handler.MuscleGroupHandler
 	PlaylistH         *handler.PlaylistHandler
+	RateM             *handler.RateLimitMiddleware
+	RoleH             *handler.RoleHandler
+	SessionH          *handler.SessionHandler
 	UserH             *handler.UserHandler
 }
 
@@ -22,12 +27,17 @@ func NewAPI(app *app.App, jwtMgr auth.JWT) *API {
 	return &API{
 		AuthH:             handler.NewAuthHandler(app.UserSvc),
 		AuthM:             handler.NewAuthMiddleware(jwtMgr, app.UserSvc),
+		CoachingH:         handler.NewCoachingHandler(app.CoachingSvc, app.PlaylistSvc),
 		EquipmentH:        handler.NewEquipmentHandler(app.EquipmentSvc),
 		ExerciseCategoryH: handler.NewExerciseCategoryHandler(app.ExerciseCategorySvc),
 		ExerciseH:         handler.NewExerciseHandler(app.ExerciseSvc),
+		LogH:              handler.NewLogHandler(app.LogSvc),
 		TrainingTypeH:     handler.NewTrainingTypeHandler(app.TrainingTypeSvc),
 		MuscleGroupH:      handler.NewMuscleGroupHandler(app.MuscleGroupSvc),
 		PlaylistH:         handler.NewPlaylistHandler(app.PlaylistSvc),
+		RoleH:             handler.NewRoleHandler(app.UserSvc),
+		RateM:             handler.NewRateLimitMiddleware(app.RateLimitStore, app.AuthRateLimit, app.AuthIdentityRateLimit),
+		SessionH:          handler.NewSessionHandler(app.SessionSvc),
 		UserH:             handler.NewUserHandler(app.UserSvc),
 	}
 }
@@ -2,13 +2,24 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
 	"github.com/cheezecakee/fitrkr/internal/db/user"
+	"github.com/cheezecakee/fitrkr/internal/utils/auth"
+)
+
+const (
+	sessionCookie      = "session"
+	refreshTokenCookie = "refresh_token"
+	refreshTokenPath   = "/api/v1/auth"
 )
 
 type AuthHandler struct {
@@ -21,13 +32,15 @@ func NewAuthHandler(svc user.UserService) *AuthHandler {
 
 // Login logs in a user
 // @Summary Log in a user
+// @Description Returns a short-lived access token and a refresh token. Both are also set as HttpOnly cookies.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body user.LoginRequest true "Login payload"
-// @Success 200 {object} map[string]string "JWT token"
+// @Success 200 {object} user.TokenPair "Access and refresh tokens"
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 401 {object} errors.ErrorResponse
+// @Failure 429 {object} errors.ErrorResponse "Too many attempts, see the Retry-After header"
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -41,27 +54,37 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.svc.Login(ctx, req.Email, req.Password)
+	client := user.ClientInfo{UserAgent: r.UserAgent(), IPAddress: clientIP(r)}
+	tokens, err := h.svc.Login(ctx, req.Email, req.Password, client)
 	if err != nil {
-		ClientError(w, http.StatusUnauthorized)
+		if err == user.ErrInvalidCredentials {
+			ClientError(w, http.StatusUnauthorized)
+			return
+		}
+		var locked *user.AccountLockedError
+		if errors.As(err, &locked) {
+			TooManyRequests(w, locked.RetryAfter)
+			return
+		}
+		ServerError(w, err)
 		return
 	}
 
 	log.Println("User logged in successfully!")
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session",
-		Value:    token,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   false, // Set to true in production with HTTPS
-		SameSite: http.SameSiteLaxMode,
-		Expires:  time.Now().Add(24 * time.Hour), // Adjust as needed
-	})
-
-	w.Header().Set("Content-Type", "application/json")
-	// json.NewEncoder(w).Encode(map[string]string{"token": token})
+	setTokenCookies(w, tokens)
+	Response(w, http.StatusOK, tokens)
 }
 
+// Logout godoc
+// @Summary Log out
+// @Description Revokes the access token and its session on the server, including its refresh tokens, and clears the auth cookies
+// @Tags auth
+// @Accept json
+// @Param request body user.RefreshTokenRequest false "Refresh token to revoke"
+// @Success 204 "Logged out"
+// @Failure 401 {object} errors.ErrorResponse
+// @Router /api/v1/auth/logout [post]
+// @Security BearerAuth
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
 	if !ok {
@@ -69,34 +92,386 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	claims, ok := r.Context().Value(ClaimsKey).(auth.UserClaims)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.svc.Logout(r.Context(), claims); err != nil {
+		ServerError(w, err)
+		return
+	}
+
+	// Tokens issued without a session are revoked individually
+	refreshToken, err := readRefreshToken(r)
+	if err != nil {
+		ClientError(w, http.StatusBadRequest)
+		return
+	}
+	if refreshToken != "" {
+		if err := h.svc.RevokeToken(r.Context(), refreshToken); err != nil {
+			ServerError(w, err)
+			return
+		}
+	}
+
+	clearTokenCookies(w)
 	log.Println("User logged out successfully!", userID)
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
-	// Placeholder: Requires refresh token table/repo/service
-	var req struct {
-		RefreshToken string `json:"refresh_token"`
+// LogoutEverywhere godoc
+// @Summary Log out everywhere
+// @Description Revokes every session of the authenticated user, on all devices, and clears the auth cookies
+// @Tags auth
+// @Success 204 "Logged out everywhere"
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/logout-all [post]
+// @Security BearerAuth
+func (h *AuthHandler) LogoutEverywhere(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+
+	if err := h.svc.LogoutEverywhere(r.Context(), userID); err != nil {
+		ServerError(w, err)
+		return
+	}
+
+	clearTokenCookies(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSessions godoc
+// @Summary List active sessions
+// @Description Get the authenticated user's logged in devices, most recently used first. The session of the requesting token is marked as current.
+// @Tags auth
+// @Produce json
+// @Success 200 {array} user.AuthSession "Active sessions"
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/sessions [get]
+// @Security BearerAuth
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	claims, _ := r.Context().Value(ClaimsKey).(auth.UserClaims)
+	sessions, err := h.svc.ListSessions(r.Context(), userID, claims.SessionID)
+	if err != nil {
+		ServerError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, sessions)
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description Log out one of the authenticated user's devices
+// @Tags auth
+// @Param id path string true "Session ID"
+// @Success 204 "Session revoked"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/sessions/{id} [delete]
+// @Security BearerAuth
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	if err := h.svc.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		if err == user.ErrAuthSessionNotFound {
+			ErrorResponse(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		ServerError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RefreshToken godoc
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and a new refresh token. The presented refresh token is revoked; presenting it again revokes every token of the login.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body user.RefreshTokenRequest false "Refresh token, read from the refresh_token cookie when omitted"
+// @Success 200 {object} user.TokenPair "New access and refresh tokens"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	refreshToken, err := readRefreshToken(r)
+	if err != nil {
 		ClientError(w, http.StatusBadRequest)
 		return
 	}
+	if refreshToken == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Refresh token is required")
+		return
+	}
 
-	// TODO: Validate refresh token via UserSvc, generate new JWT
-	NotFound(w) // Temporary
+	tokens, err := h.svc.RefreshToken(r.Context(), refreshToken)
+	if err != nil {
+		switch err {
+		case user.ErrInvalidRefreshToken:
+			clearTokenCookies(w)
+			ErrorResponse(w, http.StatusUnauthorized, "Invalid or expired refresh token")
+		case user.ErrRefreshTokenReused:
+			clearTokenCookies(w)
+			ErrorResponse(w, http.StatusUnauthorized, "Refresh token already used, please log in again")
+		default:
+			ServerError(w, err)
+		}
+		return
+	}
+
+	setTokenCookies(w, tokens)
+	Response(w, http.StatusOK, tokens)
 }
 
+// RevokeToken godoc
+// @Summary Revoke a refresh token
+// @Description Revoke a refresh token. Unknown or already revoked tokens are accepted.
+// @Tags auth
+// @Accept json
+// @Param request body user.RefreshTokenRequest false "Refresh token, read from the refresh_token cookie when omitted"
+// @Success 204 "Token revoked"
+// @Failure 400 {object} errors.ErrorResponse
+// @Router /api/v1/auth/revoke [post]
 func (h *AuthHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
-	// Placeholder: Requires refresh token table/repo/service
-	var req struct {
-		RefreshToken string `json:"refresh_token"`
+	refreshToken, err := readRefreshToken(r)
+	if err != nil {
+		ClientError(w, http.StatusBadRequest)
+		return
+	}
+	if refreshToken == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Refresh token is required")
+		return
 	}
+
+	if err := h.svc.RevokeToken(r.Context(), refreshToken); err != nil {
+		ServerError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Emails a single-use reset link if the address belongs to an account. The response is the same for unknown addresses.
+// @Tags auth
+// @Accept json
+// @Param request body user.ForgotPasswordRequest true "Account email"
+// @Success 202 "Reset email sent if the account exists"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 429 {object} errors.ErrorResponse "Too many attempts, see the Retry-After header"
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req user.ForgotPasswordRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		ClientError(w, http.StatusBadRequest)
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Email == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Email is required")
+		return
+	}
+
+	if err := h.svc.ForgotPassword(r.Context(), req.Email); err != nil {
+		ServerError(w, err)
 		return
 	}
 
-	// TODO: Revoke refresh token via UserSvc
-	NotFound(w) // Temporary
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Sets a new password with the emailed token. The token can be used once, and every session of the account is logged out.
+// @Tags auth
+// @Accept json
+// @Param request body user.ResetPasswordRequest true "Reset token and new password"
+// @Success 204 "Password changed"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req user.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Token == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Reset token is required")
+		return
+	}
+
+	if err := h.svc.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		switch err {
+		case user.ErrInvalidResetToken:
+			ErrorResponse(w, http.StatusBadRequest, "Invalid or expired reset token")
+		case user.ErrWeakPassword:
+			ErrorResponse(w, http.StatusBadRequest, "Password must be at least 8 characters")
+		default:
+			ServerError(w, err)
+		}
+		return
+	}
+
+	clearTokenCookies(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email
+// @Description Confirms the account email with the emailed token. The token can be used once.
+// @Tags auth
+// @Accept json
+// @Param request body user.VerifyEmailRequest true "Verification token"
+// @Success 204 "Email verified"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req user.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Token == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Verification token is required")
+		return
+	}
+
+	if err := h.svc.VerifyEmail(r.Context(), req.Token); err != nil {
+		if err == user.ErrInvalidVerificationToken {
+			ErrorResponse(w, http.StatusBadRequest, "Invalid or expired verification token")
+			return
+		}
+		ServerError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Emails a new verification link to the authenticated user. Earlier links stop working.
+// @Tags auth
+// @Success 202 "Verification email sent"
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/auth/verify-email/resend [post]
+// @Security BearerAuth
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.svc.ResendVerification(r.Context(), userID); err != nil {
+		switch err {
+		case user.ErrEmailAlreadyVerified:
+			ErrorResponse(w, http.StatusConflict, "Email is already verified")
+		case user.ErrUserNotFound:
+			ClientError(w, http.StatusUnauthorized)
+		default:
+			ServerError(w, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// readRefreshToken takes the refresh token from the body, falling back to the cookie
+func readRefreshToken(r *http.Request) (string, error) {
+	var req user.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if req.RefreshToken != "" {
+		return req.RefreshToken, nil
+	}
+
+	if cookie, err := r.Cookie(refreshTokenCookie); err == nil {
+		return cookie.Value, nil
+	}
+	return "", nil
+}
+
+// clientIP returns the address of the connecting client
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func setTokenCookies(w http.ResponseWriter, tokens user.TokenPair) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    tokens.AccessToken,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   false, // Set to true in production with HTTPS
+		SameSite: http.SameSiteLaxMode,
+		Expires:  tokens.AccessTokenExpiresAt,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    tokens.RefreshToken,
+		Path:     refreshTokenPath, // Only sent to the auth endpoints
+		HttpOnly: true,
+		Secure:   false, // Set to true in production with HTTPS
+		SameSite: http.SameSiteStrictMode,
+		Expires:  tokens.RefreshTokenExpiresAt,
+	})
+}
+
+func clearTokenCookies(w http.ResponseWriter) {
+	for _, cookie := range []struct{ name, path string }{
+		{sessionCookie, "/"},
+		{refreshTokenCookie, refreshTokenPath},
+	} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     cookie.name,
+			Value:    "",
+			Path:     cookie.path,
+			HttpOnly: true,
+			Expires:  time.Unix(0, 0),
+			MaxAge:   -1,
+		})
+	}
 }
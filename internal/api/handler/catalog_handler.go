@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cheezecakee/fitrkr/internal/db/exercise"
+)
+
+// writeCatalogError answers a failed write to one of the catalog lists,
+// hiding unexpected errors behind fallback
+func writeCatalogError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, exercise.ErrInvalidCatalogName), errors.Is(err, exercise.ErrMergeIntoSelf):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, exercise.ErrCatalogNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, exercise.ErrCatalogNameTaken), errors.Is(err, exercise.ErrCatalogInUse), errors.Is(err, exercise.ErrReservedCatalog):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// parseReassignTo reads the optional reassign_to query parameter of the
+// catalog delete endpoints
+func parseReassignTo(r *http.Request) (*int, error) {
+	value := r.URL.Query().Get("reassign_to")
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
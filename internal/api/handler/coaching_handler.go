@@ -0,0 +1,395 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/coaching"
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
+)
+
+// CoachingHandler handles HTTP requests for coach/client relationships
+type CoachingHandler struct {
+	coachingSvc coaching.CoachingService
+	playlistSvc playlist.PlaylistService
+}
+
+// NewCoachingHandler creates a new CoachingHandler
+func NewCoachingHandler(coachingSvc coaching.CoachingService, playlistSvc playlist.PlaylistService) *CoachingHandler {
+	return &CoachingHandler{
+		coachingSvc: coachingSvc,
+		playlistSvc: playlistSvc,
+	}
+}
+
+// InviteClient godoc
+// @Summary Invite a client
+// @Description Invite a user, by email or username, to be coached. Requires the client:coach permission. The client has to accept before the coach gets access.
+// @Tags coaching
+// @Accept json
+// @Produce json
+// @Param request body coaching.InviteClientRequest true "Client to invite"
+// @Success 201 {object} coaching.Relationship "Pending invitation"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Not a coach"
+// @Failure 404 {object} errors.ErrorResponse "Client not found"
+// @Failure 409 {object} errors.ErrorResponse "Invitation already exists"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/coaching/invitations [post]
+// @Security BearerAuth
+func (h *CoachingHandler) InviteClient(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	var req coaching.InviteClientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Basic validation
+	if req.Email == "" && req.Username == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Email or username is required")
+		return
+	}
+
+	relationship, err := h.coachingSvc.InviteClient(r.Context(), userID, req)
+	if err != nil {
+		h.coachingError(w, err)
+		return
+	}
+
+	Response(w, http.StatusCreated, relationship)
+}
+
+// ListClients godoc
+// @Summary List clients
+// @Description Get the coach's clients and pending invitations, newest first, with cursor pagination
+// @Tags coaching
+// @Produce json
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} pagination.Page[coaching.Relationship] "Page of clients"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/coaching/clients [get]
+// @Security BearerAuth
+func (h *CoachingHandler) ListClients(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	page, err := parsePageRequest(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	clients, err := h.coachingSvc.ListClients(r.Context(), userID, page)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ServerError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, clients)
+}
+
+// GetClientProfile godoc
+// @Summary Get a client's profile
+// @Description Get the profile and latest stats of an accepted client
+// @Tags coaching
+// @Produce json
+// @Param clientId path string true "Client user ID"
+// @Success 200 {object} coaching.ClientProfile "Client profile"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Not the client's coach"
+// @Failure 404 {object} errors.ErrorResponse "Client not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/coaching/clients/{clientId} [get]
+// @Security BearerAuth
+func (h *CoachingHandler) GetClientProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	clientID, err := uuid.Parse(chi.URLParam(r, "clientId"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid client ID")
+		return
+	}
+
+	profile, err := h.coachingSvc.GetClientProfile(r.Context(), userID, clientID)
+	if err != nil {
+		h.coachingError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, profile)
+}
+
+// GetClientPlaylists godoc
+// @Summary Get a client's playlists
+// @Description Get every playlist of an accepted client, including private ones
+// @Tags coaching
+// @Produce json
+// @Param clientId path string true "Client user ID"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} pagination.Page[playlist.PlaylistWithDetails] "Client playlists"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Not the client's coach"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/coaching/clients/{clientId}/playlists [get]
+// @Security BearerAuth
+func (h *CoachingHandler) GetClientPlaylists(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	clientID, err := uuid.Parse(chi.URLParam(r, "clientId"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid client ID")
+		return
+	}
+
+	page, err := parsePageRequest(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	playlists, err := h.playlistSvc.GetClientPlaylists(r.Context(), userID, clientID, page)
+	if err != nil {
+		if err == playlist.ErrUnauthorizedAccess {
+			ErrorResponse(w, http.StatusForbidden, "Access denied")
+			return
+		}
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ServerError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, playlists)
+}
+
+// AssignPlaylist godoc
+// @Summary Create a playlist for a client
+// @Description Create a playlist in the account of an accepted client. The client owns it and the coach can keep editing it.
+// @Tags coaching
+// @Accept json
+// @Produce json
+// @Param clientId path string true "Client user ID"
+// @Param request body playlist.CreatePlaylistRequest true "Playlist creation request"
+// @Success 201 {object} playlist.Playlist "Created playlist"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Not the client's coach"
+// @Failure 409 {object} errors.ErrorResponse "Playlist already exists"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/coaching/clients/{clientId}/playlists [post]
+// @Security BearerAuth
+func (h *CoachingHandler) AssignPlaylist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	clientID, err := uuid.Parse(chi.URLParam(r, "clientId"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid client ID")
+		return
+	}
+
+	var req playlist.CreatePlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Basic validation
+	if req.Title == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Title is required")
+		return
+	}
+	req.ClientID = &clientID
+
+	createdPlaylist, err := h.playlistSvc.CreatePlaylist(r.Context(), userID, req)
+	if err != nil {
+		switch err {
+		case playlist.ErrUnauthorizedAccess:
+			ErrorResponse(w, http.StatusForbidden, "Access denied")
+		case playlist.ErrPlaylistExists:
+			ErrorResponse(w, http.StatusConflict, "Playlist with this title already exists")
+		default:
+			ServerError(w, err)
+		}
+		return
+	}
+
+	Response(w, http.StatusCreated, createdPlaylist)
+}
+
+// ListCoaches godoc
+// @Summary List coaches
+// @Description Get the user's coaches and the invitations waiting for an answer, newest first, with cursor pagination
+// @Tags coaching
+// @Produce json
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} pagination.Page[coaching.Relationship] "Page of coaches"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/coaching/coaches [get]
+// @Security BearerAuth
+func (h *CoachingHandler) ListCoaches(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	page, err := parsePageRequest(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	coaches, err := h.coachingSvc.ListCoaches(r.Context(), userID, page)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ServerError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, coaches)
+}
+
+// AcceptInvitation godoc
+// @Summary Accept a coaching invitation
+// @Description Give the inviting coach access to your playlists and stats
+// @Tags coaching
+// @Produce json
+// @Param id path int true "Relationship ID"
+// @Success 200 {object} coaching.Relationship "Accepted relationship"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Invitation not found"
+// @Failure 409 {object} errors.ErrorResponse "Invitation is no longer pending"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/coaching/relationships/{id}/accept [post]
+// @Security BearerAuth
+func (h *CoachingHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	h.updateRelationship(w, r, h.coachingSvc.AcceptInvitation)
+}
+
+// DeclineInvitation godoc
+// @Summary Decline a coaching invitation
+// @Tags coaching
+// @Produce json
+// @Param id path int true "Relationship ID"
+// @Success 200 {object} coaching.Relationship "Declined relationship"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Invitation not found"
+// @Failure 409 {object} errors.ErrorResponse "Invitation is no longer pending"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/coaching/relationships/{id}/decline [post]
+// @Security BearerAuth
+func (h *CoachingHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	h.updateRelationship(w, r, h.coachingSvc.DeclineInvitation)
+}
+
+// EndRelationship godoc
+// @Summary End a coaching relationship
+// @Description Revoke a coach's access, drop a client or withdraw an invitation. Either side can do this at any time.
+// @Tags coaching
+// @Produce json
+// @Param id path int true "Relationship ID"
+// @Success 200 {object} coaching.Relationship "Revoked relationship"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Relationship not found"
+// @Failure 409 {object} errors.ErrorResponse "Relationship already ended"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/coaching/relationships/{id} [delete]
+// @Security BearerAuth
+func (h *CoachingHandler) EndRelationship(w http.ResponseWriter, r *http.Request) {
+	h.updateRelationship(w, r, h.coachingSvc.EndRelationship)
+}
+
+// updateRelationship runs a status change on the relationship in the URL
+func (h *CoachingHandler) updateRelationship(w http.ResponseWriter, r *http.Request, update func(ctx context.Context, userID uuid.UUID, id int) (coaching.Relationship, error)) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	relationshipID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid relationship ID")
+		return
+	}
+
+	relationship, err := update(r.Context(), userID, relationshipID)
+	if err != nil {
+		h.coachingError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, relationship)
+}
+
+// coachingError maps coaching service errors shared by the coaching endpoints
+func (h *CoachingHandler) coachingError(w http.ResponseWriter, err error) {
+	switch err {
+	case coaching.ErrNotCoach:
+		ErrorResponse(w, http.StatusForbidden, "Only coaches can invite clients")
+	case coaching.ErrNotClientsCoach:
+		ErrorResponse(w, http.StatusForbidden, "Access denied")
+	case coaching.ErrClientNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Client not found")
+	case coaching.ErrCannotCoachSelf:
+		ErrorResponse(w, http.StatusBadRequest, "You cannot invite yourself")
+	case coaching.ErrRelationshipExists:
+		ErrorResponse(w, http.StatusConflict, "An invitation or relationship with this client already exists")
+	case coaching.ErrRelationshipNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Coaching relationship not found")
+	case coaching.ErrInvalidTransition:
+		ErrorResponse(w, http.StatusConflict, "Relationship cannot change to the requested status")
+	default:
+		ServerError(w, err)
+	}
+}
@@ -2,12 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/cheezecakee/fitrkr/internal/db/exercise"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 // EquipmentHandler handles HTTP requests for equipment-related operations
@@ -25,25 +27,22 @@ func NewEquipmentHandler(service exercise.EquipmentService) *EquipmentHandler {
 // @Tags equipment
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {array} exercise.Equipment
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Success 200 {object} pagination.Page[exercise.Equipment]
+// @Failure 400 {object} map[string]string
 // @Router /api/v1/admin/equipment [get]
 func (h *EquipmentHandler) List(w http.ResponseWriter, r *http.Request) {
-	offsetStr := r.URL.Query().Get("offset")
-	limitStr := r.URL.Query().Get("limit")
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
-	}
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10 // Default limit
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	ctx := r.Context()
-	equipment, err := h.service.List(ctx, offset, limit)
+	equipment, err := h.service.List(ctx, page)
 	if err != nil {
-		if err.Error() == "limit must be greater than 0" {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		} else {
 			http.Error(w, "Failed to list equipment", http.StatusInternalServerError)
@@ -96,4 +95,150 @@ func (h *EquipmentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		"data":  equipment,
 		"error": nil,
 	})
-} 
\ No newline at end of file
+} 
+
+// Create creates equipment
+// @Summary Create equipment
+// @Description Requires the catalog:manage permission.
+// @Tags equipment
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body exercise.CatalogEntryRequest true "Equipment"
+// @Success 201 {object} exercise.Equipment
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/equipment [post]
+func (h *EquipmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req exercise.CatalogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	equipment := &exercise.Equipment{Name: req.Name}
+	if err := h.service.Create(r.Context(), equipment); err != nil {
+		writeCatalogError(w, err, "Failed to create equipment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  equipment,
+		"error": nil,
+	})
+}
+
+// Update renames equipment
+// @Summary Rename equipment
+// @Description Requires the catalog:manage permission.
+// @Tags equipment
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Equipment ID"
+// @Param request body exercise.CatalogEntryRequest true "New name"
+// @Success 200 {object} exercise.Equipment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/equipment/{id} [put]
+func (h *EquipmentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req exercise.CatalogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	equipment, err := h.service.Update(r.Context(), id, req.Name)
+	if err != nil {
+		writeCatalogError(w, err, "Failed to update equipment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  equipment,
+		"error": nil,
+	})
+}
+
+// Delete deletes equipment
+// @Summary Delete equipment
+// @Description Requires the catalog:manage permission. Refused while exercises use it, unless reassign_to names the equipment to move them to.
+// @Tags equipment
+// @Security BearerAuth
+// @Param id path int true "Equipment ID"
+// @Param reassign_to query int false "Equipment ID to move its exercises to"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/equipment/{id} [delete]
+func (h *EquipmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+	reassignTo, err := parseReassignTo(r)
+	if err != nil {
+		http.Error(w, "Invalid reassign_to", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id, reassignTo); err != nil {
+		writeCatalogError(w, err, "Failed to delete equipment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Merge folds a duplicate equipment into another one
+// @Summary Merge equipment into another
+// @Description Requires the catalog:manage permission. Moves every exercise over to the target and deletes the duplicate.
+// @Tags equipment
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Duplicate equipment ID"
+// @Param request body exercise.MergeCatalogRequest true "Target"
+// @Success 200 {object} exercise.Equipment
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/equipment/{id}/merge [post]
+func (h *EquipmentHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req exercise.MergeCatalogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	equipment, err := h.service.Merge(r.Context(), id, req.IntoID)
+	if err != nil {
+		writeCatalogError(w, err, "Failed to merge equipment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  equipment,
+		"error": nil,
+	})
+}
@@ -2,12 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/cheezecakee/fitrkr/internal/db/exercise"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 // ExerciseCategoryHandler handles HTTP requests for exercise category-related operations
@@ -25,25 +27,22 @@ func NewExerciseCategoryHandler(service exercise.CategoryService) *ExerciseCateg
 // @Tags exercise-categories
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {array} exercise.Category
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Success 200 {object} pagination.Page[exercise.Category]
+// @Failure 400 {object} map[string]string
 // @Router /api/v1/admin/exercise-categories [get]
 func (h *ExerciseCategoryHandler) List(w http.ResponseWriter, r *http.Request) {
-	offsetStr := r.URL.Query().Get("offset")
-	limitStr := r.URL.Query().Get("limit")
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
-	}
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10 // Default limit
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	ctx := r.Context()
-	categories, err := h.service.List(ctx, offset, limit)
+	categories, err := h.service.List(ctx, page)
 	if err != nil {
-		if err.Error() == "limit must be greater than 0" {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		} else {
 			http.Error(w, "Failed to list exercise categories", http.StatusInternalServerError)
@@ -97,3 +96,149 @@ func (h *ExerciseCategoryHandler) GetByID(w http.ResponseWriter, r *http.Request
 		"error": nil,
 	})
 }
+
+// Create creates an exercise category
+// @Summary Create an exercise category
+// @Description Requires the catalog:manage permission.
+// @Tags exercise-categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body exercise.CatalogEntryRequest true "Exercise category"
+// @Success 201 {object} exercise.Category
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/exercise-categories [post]
+func (h *ExerciseCategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req exercise.CatalogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	category := &exercise.Category{Name: req.Name}
+	if err := h.service.Create(r.Context(), category); err != nil {
+		writeCatalogError(w, err, "Failed to create exercise category")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  category,
+		"error": nil,
+	})
+}
+
+// Update renames an exercise category
+// @Summary Rename an exercise category
+// @Description Requires the catalog:manage permission.
+// @Tags exercise-categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Exercise category ID"
+// @Param request body exercise.CatalogEntryRequest true "New name"
+// @Success 200 {object} exercise.Category
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/exercise-categories/{id} [put]
+func (h *ExerciseCategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid exercise category ID", http.StatusBadRequest)
+		return
+	}
+
+	var req exercise.CatalogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.service.Update(r.Context(), id, req.Name)
+	if err != nil {
+		writeCatalogError(w, err, "Failed to update exercise category")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  category,
+		"error": nil,
+	})
+}
+
+// Delete deletes an exercise category
+// @Summary Delete an exercise category
+// @Description Requires the catalog:manage permission. Refused while exercises use it, unless reassign_to names the exercise category to move them to.
+// @Tags exercise-categories
+// @Security BearerAuth
+// @Param id path int true "Exercise category ID"
+// @Param reassign_to query int false "Exercise category ID to move its exercises to"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/exercise-categories/{id} [delete]
+func (h *ExerciseCategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid exercise category ID", http.StatusBadRequest)
+		return
+	}
+	reassignTo, err := parseReassignTo(r)
+	if err != nil {
+		http.Error(w, "Invalid reassign_to", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id, reassignTo); err != nil {
+		writeCatalogError(w, err, "Failed to delete exercise category")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Merge folds a duplicate exercise category into another one
+// @Summary Merge an exercise category into another
+// @Description Requires the catalog:manage permission. Moves every exercise over to the target and deletes the duplicate.
+// @Tags exercise-categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Duplicate exercise category ID"
+// @Param request body exercise.MergeCatalogRequest true "Target"
+// @Success 200 {object} exercise.Category
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/exercise-categories/{id}/merge [post]
+func (h *ExerciseCategoryHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid exercise category ID", http.StatusBadRequest)
+		return
+	}
+
+	var req exercise.MergeCatalogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.service.Merge(r.Context(), id, req.IntoID)
+	if err != nil {
+		writeCatalogError(w, err, "Failed to merge exercise category")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  category,
+		"error": nil,
+	})
+}
@@ -2,12 +2,16 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/cheezecakee/fitrkr/internal/db/exercise"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 // ExerciseHandler handles HTTP requests for exercise-related operations
@@ -98,32 +102,63 @@ func (h *ExerciseHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// List lists all exercises
-// @Summary List all exercises
+// List lists exercises matching the given filters
+// @Summary List exercises
+// @Description List filters take comma separated IDs and match exercises with any of them; different filters must all match. equipment_ids is the available equipment, so exercises needing anything else are left out. With muscle_match=all an exercise has to work every listed muscle group. The response carries the total number of matches and, per filter value, how many exercises picking it would match.
 // @Tags exercises
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {array} exercise.Exercise
+// @Param category_ids query string false "Category IDs, e.g. 1,2"
+// @Param equipment_ids query string false "Available equipment IDs"
+// @Param muscle_group_ids query string false "Muscle group IDs"
+// @Param muscle_match query string false "Match any or all muscle groups" Enums(any, all)
+// @Param training_type_ids query string false "Training type IDs"
+// @Param q query string false "Text query"
+// @Param sort query string false "Sort order" Enums(name, newest, relevance)
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Success 200 {object} exercise.ExerciseList
+// @Failure 400 {object} map[string]string
 // @Router /api/v1/admin/exercises [get]
 func (h *ExerciseHandler) List(w http.ResponseWriter, r *http.Request) {
-	offsetStr := r.URL.Query().Get("offset")
-	limitStr := r.URL.Query().Get("limit")
+	query := r.URL.Query()
+	filter := exercise.ExerciseFilter{
+		MuscleMatch: exercise.MuscleMatch(query.Get("muscle_match")),
+		Query:       query.Get("q"),
+		Sort:        exercise.ExerciseSort(query.Get("sort")),
+	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	var err error
+	for param, ids := range map[string]*[]int{
+		"category_ids":      &filter.CategoryIDs,
+		"equipment_ids":     &filter.EquipmentIDs,
+		"muscle_group_ids":  &filter.MuscleGroupIDs,
+		"training_type_ids": &filter.TrainingTypeIDs,
+	} {
+		if *ids, err = parseIntList(query.Get(param)); err != nil {
+			http.Error(w, "Invalid "+param, http.StatusBadRequest)
+			return
+		}
 	}
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10 // Default limit
+
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	ctx := r.Context()
-	exercises, err := h.service.List(ctx, offset, limit)
+	exercises, err := h.service.List(ctx, filter, page)
 	if err != nil {
-		if err.Error() == "limit must be greater than 0" {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
-		} else {
+			return
+		}
+		switch err.Error() {
+		case "relevance sort requires a search query",
+			"sort must be 'name', 'newest' or 'relevance'", "muscle_match must be 'any' or 'all'":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
 			http.Error(w, "Failed to list exercises", http.StatusInternalServerError)
 		}
 		return
@@ -320,13 +355,16 @@ func (h *ExerciseHandler) GetByTrainingType(w http.ResponseWriter, r *http.Reque
 	})
 }
 
-// Search exercises (enhanced version that might include relationships in future)
+// Search exercises by name, description, category and equipment
 // @Summary Search exercises
+// @Description Full-text search with the name weighted above description, category and equipment. Names close to the query also match, so typos like "dedlift" still find exercises. Results are ranked best first.
 // @Tags exercises
 // @Security BearerAuth
 // @Produce json
 // @Param q query string true "Search query"
-// @Success 200 {array} exercise.Exercise
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Success 200 {object} pagination.Page[exercise.SearchResult]
 // @Failure 400 {object} map[string]string
 // @Router /api/v1/admin/exercises/search [get]
 func (h *ExerciseHandler) Search(w http.ResponseWriter, r *http.Request) {
@@ -336,10 +374,20 @@ func (h *ExerciseHandler) Search(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	ctx := r.Context()
-	exercises, err := h.service.Search(ctx, query)
+	exercises, err := h.service.Search(ctx, query, page)
 	if err != nil {
-		http.Error(w, "Failed to search exercises", http.StatusInternalServerError)
+		if errors.Is(err, pagination.ErrInvalidCursor) || err.Error() == "search query is required" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to search exercises", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -350,3 +398,140 @@ func (h *ExerciseHandler) Search(w http.ResponseWriter, r *http.Request) {
 		"error": nil,
 	})
 }
+
+// maxCatalogBytes caps the size of an uploaded catalog
+const maxCatalogBytes = 10 << 20
+
+// catalogFormat picks json or csv from the format query parameter, falling
+// back to the content type
+func catalogFormat(r *http.Request, contentType string) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "json", "csv":
+		return format, nil
+	case "":
+		if strings.HasPrefix(contentType, "text/csv") {
+			return "csv", nil
+		}
+		return "json", nil
+	default:
+		return "", errors.New("format must be 'json' or 'csv'")
+	}
+}
+
+// Import imports an exercise catalog
+// @Summary Import an exercise catalog
+// @Description Takes a JSON array in the export format, or a CSV file with a header row of name, description, category, equipment, muscle_groups and training_types, the last two holding names separated by ';'. Categories, equipment, muscle groups and training types go by name. Every row is checked and reported; unless dry_run is set or a row has errors, the whole catalog is then written in one transaction. Requires the exercise:write and catalog:manage permissions.
+// @Tags exercises
+// @Security BearerAuth
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param format query string false "Catalog format, defaults from the content type" Enums(json, csv)
+// @Param dry_run query bool false "Only report what the import would do"
+// @Param create_missing query bool false "Create unknown categories, equipment, muscle groups and training types"
+// @Param on_conflict query string false "What to do with exercises that already exist" Enums(skip, update)
+// @Param catalog body []exercise.CatalogExercise true "Catalog"
+// @Success 200 {object} exercise.ImportReport
+// @Failure 400 {object} map[string]string
+// @Failure 422 {object} exercise.ImportReport "Some rows have errors, nothing was written"
+// @Router /api/v1/admin/exercises/import [post]
+func (h *ExerciseHandler) Import(w http.ResponseWriter, r *http.Request) {
+	format, err := catalogFormat(r, r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	opts := exercise.ImportOptions{OnConflict: exercise.ImportConflict(query.Get("on_conflict"))}
+	for param, flag := range map[string]*bool{
+		"dry_run":        &opts.DryRun,
+		"create_missing": &opts.CreateMissing,
+	} {
+		if value := query.Get(param); value != "" {
+			if *flag, err = strconv.ParseBool(value); err != nil {
+				http.Error(w, "Invalid "+param, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxCatalogBytes)
+	var rows []exercise.CatalogExercise
+	if format == "csv" {
+		rows, err = exercise.ReadCatalogCSV(body)
+	} else {
+		err = json.NewDecoder(body).Decode(&rows)
+	}
+	if err != nil {
+		if errors.Is(err, exercise.ErrInvalidImport) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Invalid catalog", http.StatusBadRequest)
+		}
+		return
+	}
+
+	ctx := r.Context()
+	report, err := h.service.Import(ctx, rows, opts)
+	if err != nil {
+		if errors.Is(err, exercise.ErrInvalidImport) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to import exercises", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if report.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  report,
+		"error": nil,
+	})
+}
+
+// Export exports the exercise catalog
+// @Summary Export the exercise catalog
+// @Description Downloads every exercise in the format Import takes. Requires the exercise:write permission.
+// @Tags exercises
+// @Security BearerAuth
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "Catalog format" Enums(json, csv) default(json)
+// @Success 200 {array} exercise.CatalogExercise
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/admin/exercises/export [get]
+func (h *ExerciseHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format, err := catalogFormat(r, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	exercises, err := h.service.Export(ctx)
+	if err != nil {
+		http.Error(w, "Failed to export exercises", http.StatusInternalServerError)
+		return
+	}
+
+	// The file is imported as is, so it is not wrapped in the data envelope
+	w.Header().Set("Content-Disposition", `attachment; filename="exercises.`+format+`"`)
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.WriteHeader(http.StatusOK)
+		if err := exercise.WriteCatalogCSV(w, exercises); err != nil {
+			log.Printf("Failed to write exercise catalog: %v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(exercises)
+}
@@ -3,10 +3,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"runtime/debug"
+	"strconv"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 type ContextKey string
@@ -14,6 +18,7 @@ type ContextKey string
 const (
 	UserIDKey ContextKey = "userID"
 	UserKey   ContextKey = "user"
+	ClaimsKey ContextKey = "claims"
 )
 
 func ServerError(w http.ResponseWriter, err error) {
@@ -47,3 +52,17 @@ func Response(w http.ResponseWriter, status int, message any) {
 		log.Printf("Failed to encode JSON response: %v", err)
 	}
 }
+
+// parsePageRequest reads the cursor and limit query parameters of a list
+// endpoint. A missing limit leaves the default page size to the service.
+func parsePageRequest(r *http.Request) (pagination.Request, error) {
+	page := pagination.Request{Cursor: r.URL.Query().Get("cursor")}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return pagination.Request{}, errors.New("limit must be a positive number")
+		}
+		page.Limit = n
+	}
+	return page, nil
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/log"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
+)
+
+// LogHandler handles HTTP requests for the activity log feed
+type LogHandler struct {
+	logSvc log.LogService
+}
+
+// NewLogHandler creates a new log handler
+func NewLogHandler(logSvc log.LogService) *LogHandler {
+	return &LogHandler{
+		logSvc: logSvc,
+	}
+}
+
+// ListLogs godoc
+// @Summary Get activity feed
+// @Description Get the authenticated user's activity log, newest first, with cursor pagination. Pass next_cursor from the previous page as cursor to continue.
+// @Tags logs
+// @Produce json
+// @Param type query string false "Log type" Enums(PR_Achieved, Workout_Completed)
+// @Param priority query string false "Priority tier" Enums(Legendary, Rare, Uncommon, Common)
+// @Param playlist_id query int false "Playlist ID"
+// @Param pr query bool false "Only PR logs (true) or only non-PR logs (false)"
+// @Param from query string false "Start of date range, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End of date range, exclusive (RFC3339 or YYYY-MM-DD)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} pagination.Page[log.Log] "Page of logs"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/logs [get]
+// @Security BearerAuth
+func (h *LogHandler) ListLogs(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	var req log.ListLogsRequest
+
+	if value := query.Get("type"); value != "" {
+		logType := log.Type(value)
+		req.Type = &logType
+	}
+	if value := query.Get("priority"); value != "" {
+		priority := log.Priority(value)
+		req.Priority = &priority
+	}
+	if value := query.Get("playlist_id"); value != "" {
+		playlistID, err := strconv.Atoi(value)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+			return
+		}
+		req.PlaylistID = &playlistID
+	}
+	if value := query.Get("pr"); value != "" {
+		pr, err := strconv.ParseBool(value)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "Invalid pr filter")
+			return
+		}
+		req.PR = &pr
+	}
+	if value := query.Get("from"); value != "" {
+		from, err := parseDateParam(value)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "Invalid from date")
+			return
+		}
+		req.From = &from
+	}
+	if value := query.Get("to"); value != "" {
+		to, err := parseDateParam(value)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "Invalid to date")
+			return
+		}
+		req.To = &to
+	}
+	page, err := parsePageRequest(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	logs, err := h.logSvc.ListLogs(r.Context(), userID, req, page)
+	if err != nil {
+		if errors.Is(err, log.ErrInvalidFilter) || errors.Is(err, pagination.ErrInvalidCursor) {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ServerError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, logs)
+}
+
+// parseDateParam accepts RFC3339 timestamps or plain dates
+func parseDateParam(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
@@ -1,14 +1,27 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
 	"log"
+	"math"
+	"net"
 	"net/http"
+	"net/netip"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/cheezecakee/fitrkr/internal/db/user"
 	"github.com/cheezecakee/fitrkr/internal/utils/auth"
+	"github.com/cheezecakee/fitrkr/internal/utils/ratelimit"
 )
 
 type AuthMiddleware struct {
@@ -79,13 +92,32 @@ func (m *AuthMiddleware) IsAuthenticated() func(http.Handler) http.Handler {
 					return token
 				}())
 
-			userID, err := m.JWTManager.ValidateJWT(token)
+			claims, err := m.JWTManager.ParseJWT(token)
 			if err != nil {
 				log.Printf("JWT validation failed: %v", err)
 				http.Error(w, "unauthorized: invalid token", http.StatusUnauthorized)
 				return
 			}
 
+			userID, err := uuid.Parse(claims.Subject)
+			if err != nil {
+				log.Printf("JWT subject is not a user ID: %v", err)
+				http.Error(w, "unauthorized: invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			// Logged out tokens and tokens of revoked sessions
+			revoked, err := m.UserSvc.IsAccessTokenRevoked(r.Context(), claims)
+			if err != nil {
+				ServerError(w, err)
+				return
+			}
+			if revoked {
+				log.Printf("Revoked token used for user ID: %s", userID)
+				http.Error(w, "unauthorized: token revoked", http.StatusUnauthorized)
+				return
+			}
+
 			log.Printf("JWT validation successful for user ID: %s", userID)
 
 			user, err := m.UserSvc.GetUserByID(r.Context(), userID)
@@ -99,12 +131,15 @@ func (m *AuthMiddleware) IsAuthenticated() func(http.Handler) http.Handler {
 
 			ctx := context.WithValue(r.Context(), UserKey, &user)
 			ctx = context.WithValue(ctx, UserIDKey, user.ID)
+			ctx = context.WithValue(ctx, ClaimsKey, claims)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// RequireAdmin allows users with the admin role. Prefer RequirePermission so
+// access can be granted without full admin.
 func (m *AuthMiddleware) RequireAdmin() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -125,7 +160,7 @@ func (m *AuthMiddleware) RequireAdmin() func(http.Handler) http.Handler {
 				log.Printf("RequireAdmin: User has no roles assigned")
 			}
 
-			if slices.Contains(currentUser.Roles, "admin") {
+			if slices.Contains(currentUser.Roles, user.RoleAdmin) {
 				log.Println("RequireAdmin: Admin access granted")
 				next.ServeHTTP(w, r)
 				return
@@ -164,6 +199,33 @@ func (m *AuthMiddleware) RequireAnyRole(roles ...string) func(http.Handler) http
 	}
 }
 
+// RequirePermission allows users whose roles grant every listed permission
+func (m *AuthMiddleware) RequirePermission(permissions ...user.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			currentUser, ok := r.Context().Value(UserKey).(*user.User)
+			if !ok || currentUser == nil {
+				log.Printf("RequirePermission: Failed to get user from context")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			allowed, err := m.UserSvc.HasPermissions(r.Context(), *currentUser, permissions...)
+			if err != nil {
+				ServerError(w, err)
+				return
+			}
+			if !allowed {
+				log.Printf("RequirePermission: User %s with roles %v lacks permissions: %v", currentUser.Username, currentUser.Roles, permissions)
+				http.Error(w, "forbidden: insufficient permissions", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Allow multiple origins - add your actual Flutter app URLs
@@ -199,7 +261,7 @@ func CORS(next http.Handler) http.Handler {
 			w.Header().Set("Access-Control-Allow-Origin", "null")
 		}
 
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		w.Header().Set("Access-Control-Max-Age", "86400") // Cache preflight for 24 hours
@@ -224,3 +286,148 @@ func SecureHeaders(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RateLimitMiddleware throttles the authentication endpoints with token
+// buckets keyed by client IP and by submitted identity
+type RateLimitMiddleware struct {
+	Store         ratelimit.Store
+	IPLimit       ratelimit.Limit
+	IdentityLimit ratelimit.Limit
+}
+
+func NewRateLimitMiddleware(store ratelimit.Store, ipLimit, identityLimit ratelimit.Limit) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		Store:         store,
+		IPLimit:       ipLimit,
+		IdentityLimit: identityLimit,
+	}
+}
+
+// maxRateLimitedBody caps how much of the body is read to find identities
+const maxRateLimitedBody = 1 << 20
+
+// ByIP limits requests per client IP. Routes sharing a name share buckets.
+func (m *RateLimitMiddleware) ByIP(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !m.allow(w, r, name+":ip:"+clientIP(r), m.IPLimit) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ByField limits requests per value of the given JSON body fields, such as
+// the email of a login attempt. The body is left readable for the handler.
+func (m *RateLimitMiddleware) ByField(name string, fields ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxRateLimitedBody))
+			if err != nil {
+				ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// Malformed bodies are left for the handler to reject
+			var values map[string]any
+			_ = json.Unmarshal(body, &values)
+
+			for _, field := range fields {
+				value, _ := values[field].(string)
+				value = strings.ToLower(strings.TrimSpace(value))
+				if value == "" {
+					continue
+				}
+				if !m.allow(w, r, name+":"+field+":"+identityKey(value), m.IdentityLimit) {
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// identityKey hashes a submitted identity so bucket keys have a fixed size
+// whatever the client sends
+func identityKey(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// allow takes a token for key and writes a 429 response when there is none
+func (m *RateLimitMiddleware) allow(w http.ResponseWriter, r *http.Request, key string, limit ratelimit.Limit) bool {
+	result, err := m.Store.Take(r.Context(), key, limit)
+	if err != nil {
+		// Fail open, an unavailable store should not lock everyone out
+		log.Printf("Rate limit check failed for %s: %v", key, err)
+		return true
+	}
+	if !result.Allowed {
+		log.Printf("Rate limit exceeded for %s", key)
+		TooManyRequests(w, result.RetryAfter)
+		return false
+	}
+	return true
+}
+
+// RealIP sets RemoteAddr to the client address from X-Forwarded-For when
+// the request comes from a trusted proxy. The header is read from the right
+// and the first address that is not a trusted proxy is the client, so a
+// client cannot pick its own address by sending the header itself.
+func RealIP(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	trusted := func(addr netip.Addr) bool {
+		addr = addr.Unmap()
+		for _, prefix := range trustedProxies {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(trustedProxies) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, err := netip.ParseAddr(clientIP(r))
+			if err == nil && trusted(peer) {
+				if client, ok := forwardedClient(r, trusted); ok {
+					r.RemoteAddr = net.JoinHostPort(client.String(), "0")
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedClient returns the rightmost X-Forwarded-For address that is not
+// a trusted proxy, or the leftmost one if every hop is trusted
+func forwardedClient(r *http.Request, trusted func(netip.Addr) bool) (netip.Addr, bool) {
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	var client netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap()
+		if !trusted(client) {
+			break
+		}
+	}
+	return client, client.IsValid()
+}
+
+// TooManyRequests writes a 429 response telling the client when to retry
+func TooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	ErrorResponse(w, http.StatusTooManyRequests, "Too many attempts, try again later")
+}
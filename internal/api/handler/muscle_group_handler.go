@@ -2,9 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
 
 	"github.com/cheezecakee/fitrkr/internal/db/exercise"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 type MuscleGroupHandler struct {
@@ -20,19 +25,179 @@ func NewMuscleGroupHandler(service exercise.MuscleGroupService) *MuscleGroupHand
 // @Tags muscle-groups
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {array} exercise.MuscleGroup
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Success 200 {object} pagination.Page[exercise.MuscleGroup]
+// @Failure 400 {object} map[string]string
 // @Router /api/v1/admin/muscle-groups [get]
 func (h *MuscleGroupHandler) List(w http.ResponseWriter, r *http.Request) {
-	offset := 0
-	limit := 1000 // or any reasonable default
-	groups, err := h.service.List(r.Context(), offset, limit)
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	groups, err := h.service.List(ctx, page)
 	if err != nil {
-		http.Error(w, "Failed to list muscle groups", http.StatusInternalServerError)
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to list muscle groups", http.StatusInternalServerError)
+		}
 		return
 	}
+
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
 		"data":  groups,
 		"error": nil,
 	})
 }
+
+// Create creates a muscle group
+// @Summary Create a muscle group
+// @Description Requires the catalog:manage permission.
+// @Tags muscle-groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body exercise.CatalogEntryRequest true "Muscle group"
+// @Success 201 {object} exercise.MuscleGroup
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/muscle-groups [post]
+func (h *MuscleGroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req exercise.CatalogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group := &exercise.MuscleGroup{Name: req.Name}
+	if err := h.service.Create(r.Context(), group); err != nil {
+		writeCatalogError(w, err, "Failed to create muscle group")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  group,
+		"error": nil,
+	})
+}
+
+// Update renames a muscle group
+// @Summary Rename a muscle group
+// @Description Requires the catalog:manage permission.
+// @Tags muscle-groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Muscle group ID"
+// @Param request body exercise.CatalogEntryRequest true "New name"
+// @Success 200 {object} exercise.MuscleGroup
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/muscle-groups/{id} [put]
+func (h *MuscleGroupHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid muscle group ID", http.StatusBadRequest)
+		return
+	}
+
+	var req exercise.CatalogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.service.Update(r.Context(), id, req.Name)
+	if err != nil {
+		writeCatalogError(w, err, "Failed to update muscle group")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  group,
+		"error": nil,
+	})
+}
+
+// Delete deletes a muscle group
+// @Summary Delete a muscle group
+// @Description Requires the catalog:manage permission. Refused while exercises use it, unless reassign_to names the muscle group to move them to.
+// @Tags muscle-groups
+// @Security BearerAuth
+// @Param id path int true "Muscle group ID"
+// @Param reassign_to query int false "Muscle group ID to move its exercises to"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/muscle-groups/{id} [delete]
+func (h *MuscleGroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid muscle group ID", http.StatusBadRequest)
+		return
+	}
+	reassignTo, err := parseReassignTo(r)
+	if err != nil {
+		http.Error(w, "Invalid reassign_to", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id, reassignTo); err != nil {
+		writeCatalogError(w, err, "Failed to delete muscle group")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Merge folds a duplicate muscle group into another one
+// @Summary Merge a muscle group into another
+// @Description Requires the catalog:manage permission. Moves every exercise over to the target and deletes the duplicate.
+// @Tags muscle-groups
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Duplicate muscle group ID"
+// @Param request body exercise.MergeCatalogRequest true "Target"
+// @Success 200 {object} exercise.MuscleGroup
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/muscle-groups/{id}/merge [post]
+func (h *MuscleGroupHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid muscle group ID", http.StatusBadRequest)
+		return
+	}
+
+	var req exercise.MergeCatalogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.service.Merge(r.Context(), id, req.IntoID)
+	if err != nil {
+		writeCatalogError(w, err, "Failed to merge muscle group")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  group,
+		"error": nil,
+	})
+}
@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+)
+
+// ReorderBlocksRequest lists every block of a playlist with its new order
+type ReorderBlocksRequest struct {
+	Blocks []playlist.BlockOrder `json:"blocks" validate:"required"`
+}
+
+// ReorderExercisesRequest lists every exercise of a block with its new order
+type ReorderExercisesRequest struct {
+	Exercises []playlist.ExerciseOrder `json:"exercises" validate:"required"`
+}
+
+// ValidationErrorResponse lists the block rules a change would break
+type ValidationErrorResponse struct {
+	Error      string               `json:"error"`
+	Violations []playlist.Violation `json:"violations"`
+}
+
+// BlockRulesError responds 422 with the violations if err is a
+// *playlist.ValidationError, and reports whether it did
+func BlockRulesError(w http.ResponseWriter, err error) bool {
+	var validationErr *playlist.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+
+	Response(w, http.StatusUnprocessableEntity, ValidationErrorResponse{
+		Error:      "Block rules violated",
+		Violations: validationErr.Violations,
+	})
+	return true
+}
+
+// UpdateExerciseBlock godoc
+// @Summary Update exercise block
+// @Description Rename or retype a block, or change the rest after it
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param blockID path int true "Block ID"
+// @Param request body playlist.UpdateBlockRequest true "Fields to update"
+// @Success 200 {object} playlist.Block "Updated block"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist or block not found"
+// @Failure 422 {object} ValidationErrorResponse "Block rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/blocks/{blockID} [patch]
+// @Security BearerAuth
+func (h *PlaylistHandler) UpdateExerciseBlock(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	blockID, err := h.extractBlockID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid block ID")
+		return
+	}
+
+	var req playlist.UpdateBlockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	block, err := h.playlistSvc.UpdateBlock(r.Context(), playlistID, blockID, userID, req)
+	if err != nil {
+		h.blockError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, block)
+}
+
+// DeleteExerciseBlock godoc
+// @Summary Delete exercise block
+// @Description Delete a block. Its exercises are moved to the end of the block given by move_to, or deleted with it when move_to is omitted. The remaining blocks are renumbered.
+// @Tags playlists
+// @Param id path int true "Playlist ID"
+// @Param blockID path int true "Block ID"
+// @Param move_to query int false "Block to move the exercises to"
+// @Success 204 "Block deleted"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist or block not found"
+// @Failure 422 {object} ValidationErrorResponse "Block rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/blocks/{blockID} [delete]
+// @Security BearerAuth
+func (h *PlaylistHandler) DeleteExerciseBlock(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	blockID, err := h.extractBlockID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid block ID")
+		return
+	}
+
+	var moveTo *int
+	if value := r.URL.Query().Get("move_to"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "Invalid move_to block ID")
+			return
+		}
+		moveTo = &id
+	}
+
+	if err := h.playlistSvc.DeleteBlock(r.Context(), playlistID, blockID, userID, moveTo); err != nil {
+		h.blockError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderBlocks godoc
+// @Summary Reorder blocks
+// @Description Reorder the blocks of a playlist. Every block has to be listed once; orders are renumbered from 1 in the requested sequence.
+// @Tags playlists
+// @Accept json
+// @Param id path int true "Playlist ID"
+// @Param request body ReorderBlocksRequest true "New block order"
+// @Success 204 "Blocks reordered"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/blocks/order [put]
+// @Security BearerAuth
+func (h *PlaylistHandler) ReorderBlocks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	var req ReorderBlocksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.playlistSvc.UpdateBlockOrder(r.Context(), playlistID, userID, req.Blocks); err != nil {
+		h.blockError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderBlockExercises godoc
+// @Summary Reorder exercises in a block
+// @Description Reorder the exercises of a block. Every exercise has to be listed once; orders are renumbered from 1 in the requested sequence.
+// @Tags playlists
+// @Accept json
+// @Param id path int true "Playlist ID"
+// @Param blockID path int true "Block ID"
+// @Param request body ReorderExercisesRequest true "New exercise order"
+// @Success 204 "Exercises reordered"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist or block not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/blocks/{blockID}/exercises/order [put]
+// @Security BearerAuth
+func (h *PlaylistHandler) ReorderBlockExercises(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	blockID, err := h.extractBlockID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid block ID")
+		return
+	}
+
+	var req ReorderExercisesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.playlistSvc.UpdateExerciseOrder(r.Context(), playlistID, blockID, userID, req.Exercises); err != nil {
+		h.blockError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MoveExercise godoc
+// @Summary Move exercise
+// @Description Move an exercise to a position in a block of the same playlist. Exercises after that position shift down and both blocks stay numbered from 1.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist exercise ID"
+// @Param request body playlist.MoveExerciseRequest true "Target block and position"
+// @Success 200 {object} playlist.PlaylistExercise "Moved exercise"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Exercise or block not found"
+// @Failure 422 {object} ValidationErrorResponse "Block rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/exercises/{id}/move [post]
+// @Security BearerAuth
+func (h *PlaylistHandler) MoveExercise(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	exerciseID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	var req playlist.MoveExerciseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.BlockID == 0 {
+		ErrorResponse(w, http.StatusBadRequest, "Block ID is required")
+		return
+	}
+
+	moved, err := h.playlistSvc.MoveExercise(r.Context(), exerciseID, userID, req)
+	if err != nil {
+		h.blockError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, moved)
+}
+
+func (h *PlaylistHandler) extractBlockID(r *http.Request) (int, error) {
+	return strconv.Atoi(chi.URLParam(r, "blockID"))
+}
+
+func (h *PlaylistHandler) blockError(w http.ResponseWriter, err error) {
+	switch err {
+	case playlist.ErrPlaylistNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Playlist not found")
+	case playlist.ErrBlockNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Block not found")
+	case playlist.ErrExerciseNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Exercise not found in playlist")
+	case playlist.ErrUnauthorizedAccess:
+		ErrorResponse(w, http.StatusForbidden, "Access denied")
+	case playlist.ErrInvalidBlockType:
+		ErrorResponse(w, http.StatusBadRequest, "Invalid block type")
+	case playlist.ErrInvalidBlockName, playlist.ErrInvalidRest, playlist.ErrInvalidOrder:
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		if !BlockRulesError(w, err) {
+			ServerError(w, err)
+		}
+	}
+}
+
+// ValidatePlaylist godoc
+// @Summary Validate playlist blocks
+// @Description Check every block against the rules of its type: exercise counts for supersets, trisets and circuits, one exercise with decreasing weights for dropsets and cardio exercises only in cardio blocks. Violations with severity "incomplete" are allowed while building a block; "error" ones are rejected when saving.
+// @Tags playlists
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 200 {object} playlist.ValidationReport "Validation report"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/validate [get]
+// @Security BearerAuth
+func (h *PlaylistHandler) ValidatePlaylist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	report, err := h.playlistSvc.ValidatePlaylist(r.Context(), playlistID, userID)
+	if err != nil {
+		h.blockError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, report)
+}
@@ -2,13 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
 	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+	"github.com/cheezecakee/fitrkr/internal/db/user"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 // PlaylistHandler handles HTTP requests for playlist operations
@@ -33,6 +37,7 @@ func NewPlaylistHandler(playlistSvc playlist.PlaylistService) *PlaylistHandler {
 // @Success 201 {object} playlist.Playlist "Created playlist"
 // @Failure 400 {object} errors.ErrorResponse "Bad request"
 // @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Email verification required for public playlists"
 // @Failure 409 {object} errors.ErrorResponse "Playlist already exists"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/v1/playlists [post]
@@ -61,6 +66,10 @@ func (h *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request)
 		switch err {
 		case playlist.ErrPlaylistExists:
 			ErrorResponse(w, http.StatusConflict, "Playlist with this title already exists")
+		case playlist.ErrUnauthorizedAccess:
+			ErrorResponse(w, http.StatusForbidden, "Access denied")
+		case user.ErrEmailNotVerified:
+			ErrorResponse(w, http.StatusForbidden, "Verify your email to publish playlists")
 		default:
 			ServerError(w, err)
 		}
@@ -70,6 +79,61 @@ func (h *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request)
 	Response(w, http.StatusCreated, createdPlaylist)
 }
 
+// CreatePlaylistTree godoc
+// @Summary Create a playlist with its blocks and exercises
+// @Description Create a playlist with tags, blocks, exercises and their configs in one request. The whole document is validated first and written in a single transaction, so either everything is created or nothing is. Blocks and exercises keep the order they are given in.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param request body playlist.BulkPlaylistRequest true "Playlist document"
+// @Success 201 {object} playlist.Playlist "Created playlist with blocks and exercises"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Email verification required for public playlists"
+// @Failure 409 {object} errors.ErrorResponse "Playlist already exists"
+// @Failure 422 {object} ValidationErrorResponse "Block rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/bulk [post]
+// @Security BearerAuth
+func (h *PlaylistHandler) CreatePlaylistTree(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	var req playlist.BulkPlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	createdPlaylist, err := h.playlistSvc.CreatePlaylistTree(r.Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, playlist.ErrInvalidPlaylist),
+			errors.Is(err, playlist.ErrInvalidBlockName),
+			errors.Is(err, playlist.ErrInvalidBlockType),
+			errors.Is(err, playlist.ErrInvalidRest),
+			errors.Is(err, playlist.ErrInvalidConfig):
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, playlist.ErrPlaylistExists):
+			ErrorResponse(w, http.StatusConflict, "Playlist with this title already exists")
+		case errors.Is(err, playlist.ErrUnauthorizedAccess):
+			ErrorResponse(w, http.StatusForbidden, "Access denied")
+		case errors.Is(err, user.ErrEmailNotVerified):
+			ErrorResponse(w, http.StatusForbidden, "Verify your email to publish playlists")
+		default:
+			if !BlockRulesError(w, err) {
+				ServerError(w, err)
+			}
+		}
+		return
+	}
+
+	Response(w, http.StatusCreated, createdPlaylist)
+}
+
 // GetPlaylist godoc
 // @Summary Get a playlist by ID
 // @Description Get playlist details by ID
@@ -114,10 +178,13 @@ func (h *PlaylistHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
 
 // GetUserPlaylists godoc
 // @Summary Get user's playlists
-// @Description Get all playlists for the authenticated user
+// @Description Get the playlists of the authenticated user, most recently updated first
 // @Tags playlists
 // @Produce json
-// @Success 200 {array} playlist.PlaylistWithDetails "User's playlists"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} pagination.Page[playlist.PlaylistWithDetails] "User's playlists"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
 // @Failure 401 {object} errors.ErrorResponse "Unauthorized"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/v1/playlists [get]
@@ -129,8 +196,77 @@ func (h *PlaylistHandler) GetUserPlaylists(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	playlists, err := h.playlistSvc.GetUserPlaylists(r.Context(), userID)
+	page, err := parsePageRequest(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	playlists, err := h.playlistSvc.GetUserPlaylists(r.Context(), userID, page)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ServerError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, playlists)
+}
+
+// DiscoverPlaylists godoc
+// @Summary Discover public playlists
+// @Description Browse public playlists of all users. List filters take comma separated values and match playlists with any of them; different filters must all match. Equipment and muscle groups are those of the exercises inside.
+// @Tags playlists
+// @Produce json
+// @Param tag_ids query string false "Tag IDs, e.g. 1,4"
+// @Param block_types query string false "Block types, e.g. superset,circuit"
+// @Param equipment_ids query string false "Equipment IDs"
+// @Param muscle_group_ids query string false "Target muscle group IDs"
+// @Param sort query string false "Sort order" Enums(newest, most_copied)
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} pagination.Page[playlist.PublicPlaylist] "Public playlists"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/discover [get]
+// @Security BearerAuth
+func (h *PlaylistHandler) DiscoverPlaylists(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := playlist.DiscoverPlaylistsRequest{Sort: playlist.DiscoverSort(query.Get("sort"))}
+
+	var err error
+	if req.TagIDs, err = parseIntList(query.Get("tag_ids")); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid tag IDs")
+		return
+	}
+	if req.EquipmentIDs, err = parseIntList(query.Get("equipment_ids")); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid equipment IDs")
+		return
+	}
+	if req.MuscleGroupIDs, err = parseIntList(query.Get("muscle_group_ids")); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid muscle group IDs")
+		return
+	}
+	if value := query.Get("block_types"); value != "" {
+		for _, blockType := range strings.Split(value, ",") {
+			req.BlockTypes = append(req.BlockTypes, playlist.BlockType(strings.TrimSpace(blockType)))
+		}
+	}
+	page, err := parsePageRequest(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	playlists, err := h.playlistSvc.DiscoverPlaylists(r.Context(), req, page)
+	if err != nil {
+		if errors.Is(err, playlist.ErrInvalidFilter) || errors.Is(err, pagination.ErrInvalidCursor) {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		ServerError(w, err)
 		return
 	}
@@ -183,7 +319,7 @@ func (h *PlaylistHandler) GetPlaylistForSession(w http.ResponseWriter, r *http.R
 
 // UpdatePlaylist godoc
 // @Summary Update a playlist
-// @Description Update playlist details
+// @Description Update playlist details. Coaches can edit their clients' playlists but only the owner can make one public.
 // @Tags playlists
 // @Accept json
 // @Produce json
@@ -226,6 +362,8 @@ func (h *PlaylistHandler) UpdatePlaylist(w http.ResponseWriter, r *http.Request)
 			ErrorResponse(w, http.StatusForbidden, "Access denied")
 		case playlist.ErrPlaylistExists:
 			ErrorResponse(w, http.StatusConflict, "Playlist with this title already exists")
+		case user.ErrEmailNotVerified:
+			ErrorResponse(w, http.StatusForbidden, "Verify your email to publish playlists")
 		default:
 			ServerError(w, err)
 		}
@@ -277,6 +415,52 @@ func (h *PlaylistHandler) DeletePlaylist(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// CopyPlaylist godoc
+// @Summary Copy a playlist
+// @Description Copy a public or own playlist, or one of a coached client, with its blocks, exercises and configs into the user's account as a private playlist. Unlisted playlists are copied through their share link. Config notes are only copied from the user's own playlists. The title gets a "(copy)" suffix if it is already used.
+// @Tags playlists
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 201 {object} playlist.Playlist "Copied playlist"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist not found"
+// @Failure 409 {object} errors.ErrorResponse "Playlist already exists"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/copy [post]
+// @Security BearerAuth
+func (h *PlaylistHandler) CopyPlaylist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	copied, err := h.playlistSvc.CopyPlaylist(r.Context(), playlistID, userID)
+	if err != nil {
+		switch err {
+		case playlist.ErrPlaylistNotFound:
+			ErrorResponse(w, http.StatusNotFound, "Playlist not found")
+		case playlist.ErrUnauthorizedAccess:
+			ErrorResponse(w, http.StatusForbidden, "Access denied")
+		case playlist.ErrPlaylistExists:
+			ErrorResponse(w, http.StatusConflict, "Playlist with this title already exists")
+		default:
+			ServerError(w, err)
+		}
+		return
+	}
+
+	Response(w, http.StatusCreated, copied)
+}
+
 // AddExerciseToPlaylist godoc
 // @Summary Add exercise to playlist
 // @Description Add an exercise to a playlist with configuration
@@ -290,6 +474,7 @@ func (h *PlaylistHandler) DeletePlaylist(w http.ResponseWriter, r *http.Request)
 // @Failure 401 {object} errors.ErrorResponse "Unauthorized"
 // @Failure 403 {object} errors.ErrorResponse "Forbidden"
 // @Failure 404 {object} errors.ErrorResponse "Playlist or block not found"
+// @Failure 422 {object} ValidationErrorResponse "Block rules violated"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/v1/playlists/{id}/exercises [post]
 // @Security BearerAuth
@@ -328,7 +513,9 @@ func (h *PlaylistHandler) AddExerciseToPlaylist(w http.ResponseWriter, r *http.R
 		case playlist.ErrBlockNotFound:
 			ErrorResponse(w, http.StatusNotFound, "Block not found")
 		default:
-			ServerError(w, err)
+			if !BlockRulesError(w, err) {
+				ServerError(w, err)
+			}
 		}
 		return
 	}
@@ -379,6 +566,67 @@ func (h *PlaylistHandler) RemoveExerciseFromPlaylist(w http.ResponseWriter, r *h
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// UpdateExerciseConfig godoc
+// @Summary Update exercise config
+// @Description Partially update the sets, reps, weight, tempo, cardio targets or notes of a playlist exercise. Omitted fields are kept and nullable fields named in "clear" are removed. "updated_at" must be the value last read; if the config changed since, the update is rejected with 409.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist Exercise ID"
+// @Param request body playlist.UpdateConfigRequest true "Fields to update"
+// @Success 200 {object} playlist.Config "Updated config"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Exercise not found"
+// @Failure 409 {object} errors.ErrorResponse "Config was changed since it was read"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/exercises/{id}/config [patch]
+// @Security BearerAuth
+func (h *PlaylistHandler) UpdateExerciseConfig(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	exerciseID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	var req playlist.UpdateConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.UpdatedAt.IsZero() {
+		ErrorResponse(w, http.StatusBadRequest, "updated_at is required")
+		return
+	}
+
+	config, err := h.playlistSvc.UpdateConfig(r.Context(), exerciseID, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, playlist.ErrInvalidConfig):
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, playlist.ErrConfigConflict):
+			ErrorResponse(w, http.StatusConflict, err.Error())
+		case errors.Is(err, playlist.ErrPlaylistNotFound), errors.Is(err, playlist.ErrExerciseNotFound), errors.Is(err, playlist.ErrConfigNotFound):
+			ErrorResponse(w, http.StatusNotFound, "Exercise not found in playlist")
+		case errors.Is(err, playlist.ErrUnauthorizedAccess):
+			ErrorResponse(w, http.StatusForbidden, "Access denied")
+		default:
+			ServerError(w, err)
+		}
+		return
+	}
+
+	Response(w, http.StatusOK, config)
+}
+
 // CreateExerciseBlock godoc
 // @Summary Create exercise block
 // @Description Create a new exercise block in a playlist
@@ -443,10 +691,13 @@ func (h *PlaylistHandler) CreateExerciseBlock(w http.ResponseWriter, r *http.Req
 
 // GetTags godoc
 // @Summary Get all tags
-// @Description Get all available playlist tags
+// @Description Get the available playlist tags sorted by name
 // @Tags playlists
 // @Produce json
-// @Success 200 {array} playlist.Tag "Available tags"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} pagination.Page[playlist.Tag] "Available tags"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/v1/playlists/tags [get]
 // @Security BearerAuth
@@ -456,8 +707,18 @@ func (h *PlaylistHandler) GetTags(w http.ResponseWriter, r *http.Request) {
 		ClientError(w, http.StatusUnauthorized)
 		return
 	}
-	tags, err := h.playlistSvc.GetAllTags(r.Context(), userID)
+	page, err := parsePageRequest(r)
 	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tags, err := h.playlistSvc.GetAllTags(r.Context(), userID, page)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		ServerError(w, err)
 		return
 	}
@@ -470,6 +731,23 @@ func (h *PlaylistHandler) extractPlaylistID(r *http.Request) (int, error) {
 	return strconv.Atoi(playlistIDStr)
 }
 
+// parseIntList parses comma separated IDs such as "1,4,7"
+func parseIntList(value string) ([]int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var ids []int
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // CreateBlockRequest represents the request structure for creating exercise blocks
 type CreateBlockRequest struct {
 	Name      string `json:"name" validate:"required"`
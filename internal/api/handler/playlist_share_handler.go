@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+)
+
+// CreateShareToken godoc
+// @Summary Create a share link
+// @Description Create a revocable link to an unlisted or public playlist. Anyone with the token can view it at /api/v1/shared/{token} without logging in. Only the owner manages share links.
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param request body playlist.CreateShareTokenRequest false "Optional expiry"
+// @Success 201 {object} playlist.ShareToken "Created share link"
+// @Failure 400 {object} errors.ErrorResponse "Bad request or private playlist"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/shares [post]
+// @Security BearerAuth
+func (h *PlaylistHandler) CreateShareToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	// The body is optional, links without one never expire
+	var req playlist.CreateShareTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	shareToken, err := h.playlistSvc.CreateShareToken(r.Context(), playlistID, userID, req)
+	if err != nil {
+		h.shareError(w, err)
+		return
+	}
+
+	Response(w, http.StatusCreated, shareToken)
+}
+
+// ListShareTokens godoc
+// @Summary List share links
+// @Description List the share links of a playlist, including revoked and expired ones
+// @Tags playlists
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 200 {array} playlist.ShareToken "Share links"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/shares [get]
+// @Security BearerAuth
+func (h *PlaylistHandler) ListShareTokens(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	shareTokens, err := h.playlistSvc.ListShareTokens(r.Context(), playlistID, userID)
+	if err != nil {
+		h.shareError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, shareTokens)
+}
+
+// RevokeShareToken godoc
+// @Summary Revoke a share link
+// @Description Revoke a share link so it can no longer be used
+// @Tags playlists
+// @Param id path int true "Playlist ID"
+// @Param shareID path int true "Share link ID"
+// @Success 204 "Share link revoked"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist or share link not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/shares/{shareID} [delete]
+// @Security BearerAuth
+func (h *PlaylistHandler) RevokeShareToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	shareID, err := strconv.Atoi(chi.URLParam(r, "shareID"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid share link ID")
+		return
+	}
+
+	if err := h.playlistSvc.RevokeShareToken(r.Context(), playlistID, userID, shareID); err != nil {
+		h.shareError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSharedPlaylist godoc
+// @Summary View a shared playlist
+// @Description View the blocks, exercises and configs of a playlist through a share link. The owner and config notes are not included.
+// @Tags shared
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} playlist.SharedPlaylist "Shared playlist"
+// @Failure 404 {object} errors.ErrorResponse "Share link not found, expired or revoked"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/shared/{token} [get]
+func (h *PlaylistHandler) GetSharedPlaylist(w http.ResponseWriter, r *http.Request) {
+	shared, err := h.playlistSvc.GetSharedPlaylist(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		h.shareError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, shared)
+}
+
+// CopySharedPlaylist godoc
+// @Summary Copy a shared playlist
+// @Description Copy the playlist behind a share link with its blocks, exercises and configs into the user's account as a private playlist. Config notes are not copied. The title gets a "(copy)" suffix if it is already used.
+// @Tags shared
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 201 {object} playlist.Playlist "Copied playlist"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Share link not found, expired or revoked"
+// @Failure 409 {object} errors.ErrorResponse "Playlist already exists"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/shared/{token}/copy [post]
+// @Security BearerAuth
+func (h *PlaylistHandler) CopySharedPlaylist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	copied, err := h.playlistSvc.CopySharedPlaylist(r.Context(), chi.URLParam(r, "token"), userID)
+	if err != nil {
+		if err == playlist.ErrPlaylistExists {
+			ErrorResponse(w, http.StatusConflict, "Playlist with this title already exists")
+			return
+		}
+		h.shareError(w, err)
+		return
+	}
+
+	Response(w, http.StatusCreated, copied)
+}
+
+func (h *PlaylistHandler) shareError(w http.ResponseWriter, err error) {
+	switch err {
+	case playlist.ErrPlaylistNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Playlist not found")
+	case playlist.ErrShareTokenNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Share link not found")
+	case playlist.ErrUnauthorizedAccess:
+		ErrorResponse(w, http.StatusForbidden, "Access denied")
+	case playlist.ErrPrivatePlaylist, playlist.ErrInvalidExpiry:
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		ServerError(w, err)
+	}
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+	"github.com/cheezecakee/fitrkr/internal/db/user"
+)
+
+// ListPlaylistVersions godoc
+// @Summary List playlist versions
+// @Description List the versions of a playlist, newest first. A version is stored after every edit.
+// @Tags playlists
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 200 {array} playlist.PlaylistVersion "Versions"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/versions [get]
+// @Security BearerAuth
+func (h *PlaylistHandler) ListPlaylistVersions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	versions, err := h.playlistSvc.ListVersions(r.Context(), playlistID, userID)
+	if err != nil {
+		h.versionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, versions)
+}
+
+// GetPlaylistVersion godoc
+// @Summary Get a playlist version
+// @Description Get a version with the snapshot of the playlist tree
+// @Tags playlists
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} playlist.PlaylistVersion "Version"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist or version not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/versions/{version} [get]
+// @Security BearerAuth
+func (h *PlaylistHandler) GetPlaylistVersion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid version")
+		return
+	}
+
+	v, err := h.playlistSvc.GetVersion(r.Context(), playlistID, userID, version)
+	if err != nil {
+		h.versionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, v)
+}
+
+// DiffPlaylistVersions godoc
+// @Summary Compare playlist versions
+// @Description Show what changed between two versions: playlist details, added and removed blocks and exercises, moved exercises and changed config fields
+// @Tags playlists
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param from query int true "Older version"
+// @Param to query int true "Newer version"
+// @Success 200 {object} playlist.VersionDiff "Differences"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist or version not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/versions/diff [get]
+// @Security BearerAuth
+func (h *PlaylistHandler) DiffPlaylistVersions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid from version")
+		return
+	}
+
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid to version")
+		return
+	}
+
+	diff, err := h.playlistSvc.DiffVersions(r.Context(), playlistID, userID, from, to)
+	if err != nil {
+		h.versionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, diff)
+}
+
+// RestorePlaylistVersion godoc
+// @Summary Restore a playlist version
+// @Description Rebuild the playlist as it was in a version. The restore is recorded as a new version.
+// @Tags playlists
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} playlist.Playlist "Restored playlist with blocks and exercises"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist or version not found"
+// @Failure 409 {object} errors.ErrorResponse "Another playlist uses the restored title"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/playlists/{id}/versions/{version}/restore [post]
+// @Security BearerAuth
+func (h *PlaylistHandler) RestorePlaylistVersion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	playlistID, err := h.extractPlaylistID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid playlist ID")
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid version")
+		return
+	}
+
+	restored, err := h.playlistSvc.RestoreVersion(r.Context(), playlistID, userID, version)
+	if err != nil {
+		h.versionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, restored)
+}
+
+func (h *PlaylistHandler) versionError(w http.ResponseWriter, err error) {
+	switch err {
+	case playlist.ErrPlaylistNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Playlist not found")
+	case playlist.ErrVersionNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Version not found")
+	case playlist.ErrUnauthorizedAccess:
+		ErrorResponse(w, http.StatusForbidden, "Access denied")
+	case playlist.ErrPlaylistExists:
+		ErrorResponse(w, http.StatusConflict, "Playlist with this title already exists")
+	case user.ErrEmailNotVerified:
+		ErrorResponse(w, http.StatusForbidden, "Verify your email to publish playlists")
+	default:
+		ServerError(w, err)
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/user"
+)
+
+// RoleHandler handles HTTP requests for roles and user role assignment
+type RoleHandler struct {
+	svc user.UserService
+}
+
+// NewRoleHandler creates a new RoleHandler
+func NewRoleHandler(svc user.UserService) *RoleHandler {
+	return &RoleHandler{svc: svc}
+}
+
+// ListRoles godoc
+// @Summary List roles
+// @Description Get every role with the permissions it grants
+// @Tags roles
+// @Produce json
+// @Success 200 {array} user.Role "Roles"
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/roles [get]
+// @Security BearerAuth
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.svc.ListRoles(r.Context())
+	if err != nil {
+		ServerError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, roles)
+}
+
+// GrantRole godoc
+// @Summary Grant a role
+// @Description Add a role to a user. Granting a role the user already has changes nothing.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body user.GrantRoleRequest true "Role to grant"
+// @Success 200 {object} user.UserResponse "Updated user"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/users/{id}/roles [post]
+// @Security BearerAuth
+func (h *RoleHandler) GrantRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req user.GrantRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Role == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Role is required")
+		return
+	}
+
+	updatedUser, err := h.svc.GrantRole(r.Context(), userID, req.Role)
+	if err != nil {
+		h.roleError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, roleUserResponse(updatedUser))
+}
+
+// RevokeRole godoc
+// @Summary Revoke a role
+// @Description Remove a role from a user. The last admin cannot lose the admin role.
+// @Tags roles
+// @Produce json
+// @Param id path string true "User ID"
+// @Param role path string true "Role name"
+// @Success 200 {object} user.UserResponse "Updated user"
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 404 {object} errors.ErrorResponse
+// @Failure 409 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/admin/users/{id}/roles/{role} [delete]
+// @Security BearerAuth
+func (h *RoleHandler) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	updatedUser, err := h.svc.RevokeRole(r.Context(), userID, chi.URLParam(r, "role"))
+	if err != nil {
+		h.roleError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, roleUserResponse(updatedUser))
+}
+
+// roleError maps user service errors shared by the role endpoints
+func (h *RoleHandler) roleError(w http.ResponseWriter, err error) {
+	switch err {
+	case user.ErrRoleNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Role not found")
+	case user.ErrUserNotFound:
+		ErrorResponse(w, http.StatusNotFound, "User not found")
+	case user.ErrLastAdmin:
+		ErrorResponse(w, http.StatusConflict, "Cannot remove the last admin")
+	default:
+		ServerError(w, err)
+	}
+}
+
+func roleUserResponse(u user.User) user.UserResponse {
+	return user.UserResponse{
+		ID:        u.ID,
+		Username:  u.Username,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+		IsPremium: u.IsPremium,
+		Roles:     u.Roles,
+
+		EmailVerifiedAt: u.EmailVerifiedAt,
+	}
+}
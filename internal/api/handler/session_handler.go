@@ -0,0 +1,586 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+	"github.com/cheezecakee/fitrkr/internal/db/session"
+	"github.com/cheezecakee/fitrkr/internal/db/user"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
+)
+
+// SessionHandler handles HTTP requests for workout session operations
+type SessionHandler struct {
+	sessionSvc session.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(sessionSvc session.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionSvc: sessionSvc,
+	}
+}
+
+// StartSession godoc
+// @Summary Start a workout session
+// @Description Start a new workout session from a playlist. The playlist structure is snapshotted at start time.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param request body session.StartSessionRequest true "Start session request"
+// @Success 201 {object} session.Session "Started session"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Playlist not found"
+// @Failure 409 {object} errors.ErrorResponse "Unfinished session already exists"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions [post]
+// @Security BearerAuth
+func (h *SessionHandler) StartSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	var req session.StartSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Basic validation
+	if req.PlaylistID == 0 {
+		ErrorResponse(w, http.StatusBadRequest, "Playlist ID is required")
+		return
+	}
+
+	startedSession, err := h.sessionSvc.StartSession(r.Context(), userID, req)
+	if err != nil {
+		switch err {
+		case playlist.ErrPlaylistNotFound:
+			ErrorResponse(w, http.StatusNotFound, "Playlist not found")
+		case playlist.ErrUnauthorizedAccess:
+			ErrorResponse(w, http.StatusForbidden, "Access denied")
+		case session.ErrEmptyPlaylist:
+			ErrorResponse(w, http.StatusBadRequest, "Playlist has no exercises")
+		case session.ErrActiveSessionExists:
+			ErrorResponse(w, http.StatusConflict, "Finish your current session before starting a new one")
+		case user.ErrEmailNotVerified:
+			ErrorResponse(w, http.StatusForbidden, "Verify your email to log sessions")
+		default:
+			ServerError(w, err)
+		}
+		return
+	}
+
+	Response(w, http.StatusCreated, startedSession)
+}
+
+// GetUserSessions godoc
+// @Summary Get user's sessions
+// @Description Get the session history for the authenticated user, most recently started first, with cursor pagination
+// @Tags sessions
+// @Produce json
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} pagination.Page[session.Session] "Page of sessions"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions [get]
+// @Security BearerAuth
+func (h *SessionHandler) GetUserSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	page, err := parsePageRequest(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sessions, err := h.sessionSvc.GetUserSessions(r.Context(), userID, page)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ServerError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, sessions)
+}
+
+// GetActiveSession godoc
+// @Summary Get active session
+// @Description Get the authenticated user's unfinished session with exercises and sets
+// @Tags sessions
+// @Produce json
+// @Success 200 {object} session.Session "Active session"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "No active session"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/active [get]
+// @Security BearerAuth
+func (h *SessionHandler) GetActiveSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	activeSession, err := h.sessionSvc.GetActiveSession(r.Context(), userID)
+	if err != nil {
+		switch err {
+		case session.ErrSessionNotFound:
+			ErrorResponse(w, http.StatusNotFound, "No active session")
+		default:
+			ServerError(w, err)
+		}
+		return
+	}
+
+	Response(w, http.StatusOK, activeSession)
+}
+
+// GetSession godoc
+// @Summary Get a session by ID
+// @Description Get a session with its playlist snapshot, exercises and sets
+// @Tags sessions
+// @Produce json
+// @Param id path int true "Session ID"
+// @Success 200 {object} session.Session "Session details"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Session not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/{id} [get]
+// @Security BearerAuth
+func (h *SessionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := h.extractSessionID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	sessionData, err := h.sessionSvc.GetSession(r.Context(), sessionID, userID)
+	if err != nil {
+		h.sessionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, sessionData)
+}
+
+// PauseSession godoc
+// @Summary Pause a session
+// @Description Pause an in-progress session. Paused time is excluded from the session duration.
+// @Tags sessions
+// @Produce json
+// @Param id path int true "Session ID"
+// @Success 200 {object} session.Session "Paused session"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Session not found"
+// @Failure 409 {object} errors.ErrorResponse "Session is not in progress"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/{id}/pause [post]
+// @Security BearerAuth
+func (h *SessionHandler) PauseSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := h.extractSessionID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	pausedSession, err := h.sessionSvc.PauseSession(r.Context(), sessionID, userID)
+	if err != nil {
+		h.sessionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, pausedSession)
+}
+
+// ResumeSession godoc
+// @Summary Resume a session
+// @Description Resume a paused session
+// @Tags sessions
+// @Produce json
+// @Param id path int true "Session ID"
+// @Success 200 {object} session.Session "Resumed session"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Session not found"
+// @Failure 409 {object} errors.ErrorResponse "Session is not paused"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/{id}/resume [post]
+// @Security BearerAuth
+func (h *SessionHandler) ResumeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := h.extractSessionID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	resumedSession, err := h.sessionSvc.ResumeSession(r.Context(), sessionID, userID)
+	if err != nil {
+		h.sessionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, resumedSession)
+}
+
+// FinishSession godoc
+// @Summary Finish a session
+// @Description Complete a session and return the full session with exercises, sets and any personal records set, which are also written to the activity log
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Session ID"
+// @Param request body session.FinishSessionRequest false "Finish session request"
+// @Success 200 {object} session.Session "Finished session"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Session not found"
+// @Failure 409 {object} errors.ErrorResponse "Session already completed"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/{id}/finish [post]
+// @Security BearerAuth
+func (h *SessionHandler) FinishSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := h.extractSessionID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	// Body is optional
+	var req session.FinishSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	finishedSession, err := h.sessionSvc.FinishSession(r.Context(), sessionID, userID, req)
+	if err != nil {
+		h.sessionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, finishedSession)
+}
+
+// AddSet godoc
+// @Summary Log a set
+// @Description Append a set to an exercise of an unfinished session
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Session ID"
+// @Param exerciseId path int true "Session exercise ID"
+// @Param request body session.LogSetRequest true "Set payload"
+// @Success 201 {object} session.ExerciseSet "Created set"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Session or exercise not found"
+// @Failure 409 {object} errors.ErrorResponse "Session already completed"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/{id}/exercises/{exerciseId}/sets [post]
+// @Security BearerAuth
+func (h *SessionHandler) AddSet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := h.extractSessionID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	exerciseID, err := strconv.Atoi(chi.URLParam(r, "exerciseId"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	var req session.LogSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	createdSet, err := h.sessionSvc.AddSet(r.Context(), sessionID, exerciseID, userID, req)
+	if err != nil {
+		h.sessionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusCreated, createdSet)
+}
+
+// UpdateSet godoc
+// @Summary Edit a set
+// @Description Update the provided fields of a logged set
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Set ID"
+// @Param request body session.LogSetRequest true "Set payload"
+// @Success 200 {object} session.ExerciseSet "Updated set"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Set not found"
+// @Failure 409 {object} errors.ErrorResponse "Session already completed"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/sets/{id} [patch]
+// @Security BearerAuth
+func (h *SessionHandler) UpdateSet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	setID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid set ID")
+		return
+	}
+
+	var req session.LogSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	updatedSet, err := h.sessionSvc.UpdateSet(r.Context(), setID, userID, req)
+	if err != nil {
+		h.sessionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, updatedSet)
+}
+
+// SubmitSets godoc
+// @Summary Bulk submit sets
+// @Description Create or update many sets of an unfinished session in one transaction. Sets are matched by exercise and set number.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Session ID"
+// @Param request body session.SubmitSetsRequest true "Sets payload"
+// @Success 200 {array} session.ExerciseSet "Saved sets"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Session or exercise not found"
+// @Failure 409 {object} errors.ErrorResponse "Session already completed"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/{id}/sets [put]
+// @Security BearerAuth
+func (h *SessionHandler) SubmitSets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := h.extractSessionID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	var req session.SubmitSetsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	savedSets, err := h.sessionSvc.SubmitSets(r.Context(), sessionID, userID, req)
+	if err != nil {
+		h.sessionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, savedSets)
+}
+
+// AddCardioEntry godoc
+// @Summary Log a cardio interval
+// @Description Append a cardio entry (duration, distance, heart rate, pace, incline, laps) to a cardio exercise of an unfinished session
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Session ID"
+// @Param exerciseId path int true "Session exercise ID"
+// @Param request body session.LogCardioRequest true "Cardio entry payload"
+// @Success 201 {object} session.CardioEntry "Created cardio entry"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Session or exercise not found"
+// @Failure 409 {object} errors.ErrorResponse "Session already completed"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/{id}/exercises/{exerciseId}/cardio [post]
+// @Security BearerAuth
+func (h *SessionHandler) AddCardioEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := h.extractSessionID(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	exerciseID, err := strconv.Atoi(chi.URLParam(r, "exerciseId"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	var req session.LogCardioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	createdEntry, err := h.sessionSvc.AddCardioEntry(r.Context(), sessionID, exerciseID, userID, req)
+	if err != nil {
+		h.sessionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusCreated, createdEntry)
+}
+
+// UpdateCardioEntry godoc
+// @Summary Edit a cardio interval
+// @Description Update the provided fields of a logged cardio entry
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Cardio entry ID"
+// @Param request body session.LogCardioRequest true "Cardio entry payload"
+// @Success 200 {object} session.CardioEntry "Updated cardio entry"
+// @Failure 400 {object} errors.ErrorResponse "Bad request"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} errors.ErrorResponse "Forbidden"
+// @Failure 404 {object} errors.ErrorResponse "Cardio entry not found"
+// @Failure 409 {object} errors.ErrorResponse "Session already completed"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/sessions/cardio/{id} [patch]
+// @Security BearerAuth
+func (h *SessionHandler) UpdateCardioEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(UserIDKey).(uuid.UUID)
+	if !ok {
+		ClientError(w, http.StatusUnauthorized)
+		return
+	}
+
+	entryID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid cardio entry ID")
+		return
+	}
+
+	var req session.LogCardioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	updatedEntry, err := h.sessionSvc.UpdateCardioEntry(r.Context(), entryID, userID, req)
+	if err != nil {
+		h.sessionError(w, err)
+		return
+	}
+
+	Response(w, http.StatusOK, updatedEntry)
+}
+
+func (h *SessionHandler) extractSessionID(r *http.Request) (int, error) {
+	sessionIDStr := chi.URLParam(r, "id")
+	return strconv.Atoi(sessionIDStr)
+}
+
+// sessionError maps session service errors shared by the session endpoints
+func (h *SessionHandler) sessionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, session.ErrInvalidSet) || errors.Is(err, session.ErrInvalidCardioEntry) {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch err {
+	case session.ErrSessionNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Session not found")
+	case session.ErrUnauthorizedAccess:
+		ErrorResponse(w, http.StatusForbidden, "Access denied")
+	case session.ErrInvalidTransition:
+		ErrorResponse(w, http.StatusConflict, "Session cannot change to the requested state")
+	case session.ErrSessionCompleted:
+		ErrorResponse(w, http.StatusConflict, "Session is already completed")
+	case session.ErrSessionExerciseNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Exercise not found in session")
+	case session.ErrSetNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Set not found")
+	case session.ErrCardioEntryNotFound:
+		ErrorResponse(w, http.StatusNotFound, "Cardio entry not found")
+	case user.ErrEmailNotVerified:
+		ErrorResponse(w, http.StatusForbidden, "Verify your email to log sessions")
+	default:
+		ServerError(w, err)
+	}
+}
@@ -2,9 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
 
 	"github.com/cheezecakee/fitrkr/internal/db/exercise"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 type TrainingTypeHandler struct {
@@ -20,19 +25,180 @@ func NewTrainingTypeHandler(service exercise.TrainingTypeService) *TrainingTypeH
 // @Tags exercise-types
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {array} exercise.TrainingType
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Success 200 {object} pagination.Page[exercise.TrainingType]
+// @Failure 400 {object} map[string]string
 // @Router /api/v1/admin/exercise-types [get]
 func (h *TrainingTypeHandler) List(w http.ResponseWriter, r *http.Request) {
-	offset := 0
-	limit := 1000 // or any reasonable default
-	types, err := h.service.List(r.Context(), offset, limit)
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	types, err := h.service.List(ctx, page)
 	if err != nil {
-		http.Error(w, "Failed to list exercise types", http.StatusInternalServerError)
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to list exercise types", http.StatusInternalServerError)
+		}
 		return
 	}
+
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
 		"data":  types,
 		"error": nil,
 	})
 }
+
+// Create creates an exercise type
+// @Summary Create an exercise type
+// @Description Requires the catalog:manage permission.
+// @Tags exercise-types
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body exercise.CatalogEntryRequest true "Exercise type"
+// @Success 201 {object} exercise.TrainingType
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/exercise-types [post]
+func (h *TrainingTypeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req exercise.CatalogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	exerciseType := &exercise.TrainingType{Name: req.Name}
+	if err := h.service.Create(r.Context(), exerciseType); err != nil {
+		writeCatalogError(w, err, "Failed to create exercise type")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  exerciseType,
+		"error": nil,
+	})
+}
+
+// Update renames an exercise type
+// @Summary Rename an exercise type
+// @Description Requires the catalog:manage permission. The cardio type that playlist block rules check cannot be renamed.
+// @Tags exercise-types
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Exercise type ID"
+// @Param request body exercise.CatalogEntryRequest true "New name"
+// @Success 200 {object} exercise.TrainingType
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/exercise-types/{id} [put]
+func (h *TrainingTypeHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid exercise type ID", http.StatusBadRequest)
+		return
+	}
+
+	var req exercise.CatalogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	exerciseType, err := h.service.Update(r.Context(), id, req.Name)
+	if err != nil {
+		writeCatalogError(w, err, "Failed to update exercise type")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  exerciseType,
+		"error": nil,
+	})
+}
+
+// Delete deletes an exercise type
+// @Summary Delete an exercise type
+// @Description Requires the catalog:manage permission. Refused while exercises use it, unless reassign_to names the exercise type to move them to. The cardio type that playlist block rules check cannot be deleted.
+// @Tags exercise-types
+// @Security BearerAuth
+// @Param id path int true "Exercise type ID"
+// @Param reassign_to query int false "Exercise type ID to move its exercises to"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/exercise-types/{id} [delete]
+func (h *TrainingTypeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid exercise type ID", http.StatusBadRequest)
+		return
+	}
+	reassignTo, err := parseReassignTo(r)
+	if err != nil {
+		http.Error(w, "Invalid reassign_to", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id, reassignTo); err != nil {
+		writeCatalogError(w, err, "Failed to delete exercise type")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Merge folds a duplicate exercise type into another one
+// @Summary Merge an exercise type into another
+// @Description Requires the catalog:manage permission. Moves every exercise over to the target and deletes the duplicate. The cardio type that playlist block rules check cannot be merged away.
+// @Tags exercise-types
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Duplicate exercise type ID"
+// @Param request body exercise.MergeCatalogRequest true "Target"
+// @Success 200 {object} exercise.TrainingType
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/admin/exercise-types/{id}/merge [post]
+func (h *TrainingTypeHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid exercise type ID", http.StatusBadRequest)
+		return
+	}
+
+	var req exercise.MergeCatalogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	exerciseType, err := h.service.Merge(r.Context(), id, req.IntoID)
+	if err != nil {
+		writeCatalogError(w, err, "Failed to merge exercise type")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data":  exerciseType,
+		"error": nil,
+	})
+}
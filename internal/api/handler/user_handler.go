@@ -9,6 +9,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/cheezecakee/fitrkr/internal/db/user"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 	"github.com/cheezecakee/fitrkr/pkg/errors"
 )
 
@@ -29,6 +30,7 @@ func NewUserHandler(svc user.UserService) *UserHandler {
 // @Success 201 {object} user.UserResponse
 // @Failure 400 {object} errors.ErrorResponse
 // @Failure 409 {object} errors.ErrorResponse
+// @Failure 429 {object} errors.ErrorResponse "Too many attempts, see the Retry-After header"
 // @Router /api/v1/users [post]
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -75,6 +77,8 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		CreatedAt: newUser.CreatedAt,
 		UpdatedAt: newUser.UpdatedAt,
 		IsPremium: newUser.IsPremium,
+
+		EmailVerifiedAt: newUser.EmailVerifiedAt,
 	}
 
 	// Return the created user (excluding password)
@@ -144,6 +148,8 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		CreatedAt: updatedUser.CreatedAt,
 		UpdatedAt: updatedUser.UpdatedAt,
 		IsPremium: updatedUser.IsPremium,
+
+		EmailVerifiedAt: updatedUser.EmailVerifiedAt,
 	}
 
 	// Return updated user
@@ -188,6 +194,63 @@ func (h *UserHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt: userData.UpdatedAt,
 		IsPremium: userData.IsPremium,
 		Roles:     userData.Roles,
+
+		EmailVerifiedAt: userData.EmailVerifiedAt,
+	}
+
+	Response(w, http.StatusOK, response)
+}
+
+// ListUsers lists every user account
+// @Summary List users
+// @Description Page through all accounts, oldest first. Requires the user:moderate permission.
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} pagination.Page[user.UserResponse]
+// @Failure 400 {object} errors.ErrorResponse
+// @Failure 401 {object} errors.ErrorResponse
+// @Failure 403 {object} errors.ErrorResponse
+// @Failure 500 {object} errors.ErrorResponse
+// @Router /api/v1/users [get]
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	users, err := h.svc.List(r.Context(), page)
+	if err != nil {
+		if err == pagination.ErrInvalidCursor {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ServerError(w, err)
+		return
+	}
+
+	response := pagination.Page[user.UserResponse]{
+		Items:      make([]user.UserResponse, len(users.Items)),
+		NextCursor: users.NextCursor,
+		Total:      users.Total,
+	}
+	for i, userData := range users.Items {
+		response.Items[i] = user.UserResponse{
+			ID:        userData.ID,
+			Username:  userData.Username,
+			FirstName: userData.FirstName,
+			LastName:  userData.LastName,
+			Email:     userData.Email,
+			CreatedAt: userData.CreatedAt,
+			UpdatedAt: userData.UpdatedAt,
+			IsPremium: userData.IsPremium,
+			Roles:     userData.Roles,
+
+			EmailVerifiedAt: userData.EmailVerifiedAt,
+		}
 	}
 
 	Response(w, http.StatusOK, response)
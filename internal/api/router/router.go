@@ -16,6 +16,7 @@ import (
 func SetupRouter(app *app.App, jwtMgr auth.JWT, version string) http.Handler {
 	r := chi.NewRouter()
 
+	r.Use(handler.RealIP(app.TrustedProxies))
 	r.Use(handler.CORS)
 
 	api := api.NewAPI(app, jwtMgr)
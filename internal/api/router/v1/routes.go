@@ -9,16 +9,21 @@ import (
 
 	"github.com/cheezecakee/fitrkr/internal/api"
 	"github.com/cheezecakee/fitrkr/internal/api/handler"
+	"github.com/cheezecakee/fitrkr/internal/db/user"
 )
 
 func SetupRoutes(api *api.API) http.Handler {
 	r := chi.NewRouter()
 
 	versionedRoutes := map[string]http.Handler{
-		"/users":     SetupUserRoutes(api.UserH, api.AuthM),
-		"/auth":      SetupAuthRoutes(api.AuthH, api.AuthM),
+		"/users":     SetupUserRoutes(api.UserH, api.AuthM, api.RateM),
+		"/auth":      SetupAuthRoutes(api.AuthH, api.AuthM, api.RateM),
 		"/playlists": SetupPlaylistRoutes(api.PlaylistH, api.AuthM),
-		"/admin":     SetupAdminRoutes(api.ExerciseH, api.EquipmentH, api.ExerciseCategoryH, api.MuscleGroupH, api.TrainingTypeH, api.AuthM),
+		"/sessions":  SetupSessionRoutes(api.SessionH, api.AuthM),
+		"/shared":    SetupSharedRoutes(api.PlaylistH, api.AuthM),
+		"/coaching":  SetupCoachingRoutes(api.CoachingH, api.AuthM),
+		"/logs":      SetupLogRoutes(api.LogH, api.AuthM),
+		"/admin":     SetupAdminRoutes(api.ExerciseH, api.EquipmentH, api.ExerciseCategoryH, api.MuscleGroupH, api.TrainingTypeH, api.RoleH, api.AuthM),
 		"/swagger":   httpSwagger.WrapHandler,
 	}
 
@@ -30,11 +35,14 @@ func SetupRoutes(api *api.API) http.Handler {
 	return r
 }
 
-func SetupUserRoutes(h *handler.UserHandler, authM *handler.AuthMiddleware) http.Handler {
+func SetupUserRoutes(h *handler.UserHandler, authM *handler.AuthMiddleware, rateM *handler.RateLimitMiddleware) http.Handler {
 	r := chi.NewRouter()
 
 	// Public routes (No auth required)
-	r.Post("/", h.CreateUser)
+	r.With(
+		rateM.ByIP("register"),
+		rateM.ByField("register", "email", "username"),
+	).Post("/", h.CreateUser)
 
 	// Protected routes (Auth required)
 	r.Group(func(r chi.Router) {
@@ -42,21 +50,35 @@ func SetupUserRoutes(h *handler.UserHandler, authM *handler.AuthMiddleware) http
 		r.Get("/me", h.GetCurrentUser) // GET /users/me - Get current user info
 		r.Put("/", h.UpdateUser)       // PUT /users - Update user
 		r.Delete("/", h.DeleteUser)    // DELETE /users - Delete user
+
+		r.With(authM.RequirePermission(user.PermUserModerate)).Get("/", h.ListUsers) // GET /users - List all users
 	})
 
 	return r
 }
 
-func SetupAuthRoutes(h *handler.AuthHandler, authM *handler.AuthMiddleware) http.Handler {
+func SetupAuthRoutes(h *handler.AuthHandler, authM *handler.AuthMiddleware, rateM *handler.RateLimitMiddleware) http.Handler {
 	r := chi.NewRouter()
 
-	r.Post("/login", h.Login)
-	r.Post("/refresh", h.RefreshToken) // Currently not available
-	r.Post("/revoke", h.RevokeToken)   // Currently not available
+	r.With(rateM.ByIP("login"), rateM.ByField("login", "email")).Post("/login", h.Login)
+	r.Post("/refresh", h.RefreshToken) // POST /auth/refresh - Rotate refresh token
+	r.Post("/revoke", h.RevokeToken)   // POST /auth/revoke - Revoke refresh token
+
+	// Password reset
+	r.With(rateM.ByIP("password"), rateM.ByField("password", "email")).Post("/password/forgot", h.ForgotPassword) // POST /auth/password/forgot
+	r.With(rateM.ByIP("password")).Post("/password/reset", h.ResetPassword)                                       // POST /auth/password/reset
+
+	// Email verification
+	r.With(rateM.ByIP("verify-email")).Post("/verify-email", h.VerifyEmail) // POST /auth/verify-email
 
 	r.Group(func(r chi.Router) {
 		r.Use(authM.IsAuthenticated())
-		r.Post("/logout", h.Logout)
+		r.Post("/logout", h.Logout)                 // POST /auth/logout - Log out this device
+		r.Post("/logout-all", h.LogoutEverywhere)   // POST /auth/logout-all - Log out every device
+		r.Get("/sessions", h.ListSessions)          // GET /auth/sessions - Logged in devices
+		r.Delete("/sessions/{id}", h.RevokeSession) // DELETE /auth/sessions/{id} - Log out one device
+
+		r.Post("/verify-email/resend", h.ResendVerification) // POST /auth/verify-email/resend
 	})
 
 	return r
@@ -70,21 +92,42 @@ func SetupPlaylistRoutes(h *handler.PlaylistHandler, authM *handler.AuthMiddlewa
 		r.Use(authM.IsAuthenticated())
 
 		// Main playlist CRUD operations
-		r.Post("/", h.CreatePlaylist)       // POST /playlists
-		r.Get("/", h.GetUserPlaylists)      // GET /playlists
-		r.Get("/{id}", h.GetPlaylist)       // GET /playlists/{id}
-		r.Put("/{id}", h.UpdatePlaylist)    // PUT /playlists/{id}
-		r.Delete("/{id}", h.DeletePlaylist) // DELETE /playlists/{id}
+		r.Post("/", h.CreatePlaylist)           // POST /playlists
+		r.Post("/bulk", h.CreatePlaylistTree)   // POST /playlists/bulk
+		r.Get("/", h.GetUserPlaylists)          // GET /playlists
+		r.Get("/discover", h.DiscoverPlaylists) // GET /playlists/discover
+		r.Get("/{id}", h.GetPlaylist)           // GET /playlists/{id}
+		r.Put("/{id}", h.UpdatePlaylist)        // PUT /playlists/{id}
+		r.Delete("/{id}", h.DeletePlaylist)     // DELETE /playlists/{id}
+		r.Post("/{id}/copy", h.CopyPlaylist)    // POST /playlists/{id}/copy
 
 		// Session-specific playlist data
 		r.Get("/{id}/session", h.GetPlaylistForSession) // GET /playlists/{id}/session
+		r.Get("/{id}/validate", h.ValidatePlaylist)     // GET /playlists/{id}/validate
 
 		// Exercise management within playlists
 		r.Post("/{id}/exercises", h.AddExerciseToPlaylist)        // POST /playlists/{id}/exercises
 		r.Delete("/exercises/{id}", h.RemoveExerciseFromPlaylist) // DELETE /playlists/exercises/{id}
+		r.Post("/exercises/{id}/move", h.MoveExercise)            // POST /playlists/exercises/{id}/move
+		r.Patch("/exercises/{id}/config", h.UpdateExerciseConfig) // PATCH /playlists/exercises/{id}/config
 
 		// Block management within playlists
-		r.Post("/{id}/blocks", h.CreateExerciseBlock) // POST /playlists/{id}/blocks
+		r.Post("/{id}/blocks", h.CreateExerciseBlock)                            // POST /playlists/{id}/blocks
+		r.Put("/{id}/blocks/order", h.ReorderBlocks)                             // PUT /playlists/{id}/blocks/order
+		r.Patch("/{id}/blocks/{blockID}", h.UpdateExerciseBlock)                 // PATCH /playlists/{id}/blocks/{blockID}
+		r.Delete("/{id}/blocks/{blockID}", h.DeleteExerciseBlock)                // DELETE /playlists/{id}/blocks/{blockID}?move_to=
+		r.Put("/{id}/blocks/{blockID}/exercises/order", h.ReorderBlockExercises) // PUT /playlists/{id}/blocks/{blockID}/exercises/order
+
+		// Version history
+		r.Get("/{id}/versions", h.ListPlaylistVersions)                      // GET /playlists/{id}/versions
+		r.Get("/{id}/versions/diff", h.DiffPlaylistVersions)                 // GET /playlists/{id}/versions/diff?from=&to=
+		r.Get("/{id}/versions/{version}", h.GetPlaylistVersion)              // GET /playlists/{id}/versions/{version}
+		r.Post("/{id}/versions/{version}/restore", h.RestorePlaylistVersion) // POST /playlists/{id}/versions/{version}/restore
+
+		// Share links for unlisted and public playlists
+		r.Post("/{id}/shares", h.CreateShareToken)             // POST /playlists/{id}/shares
+		r.Get("/{id}/shares", h.ListShareTokens)               // GET /playlists/{id}/shares
+		r.Delete("/{id}/shares/{shareID}", h.RevokeShareToken) // DELETE /playlists/{id}/shares/{shareID}
 
 		// Reference data endpoints
 		r.Get("/tags", h.GetTags) // GET /playlists/tags
@@ -93,7 +136,85 @@ func SetupPlaylistRoutes(h *handler.PlaylistHandler, authM *handler.AuthMiddlewa
 	return r
 }
 
-func SetupAdminRoutes(exerciseH *handler.ExerciseHandler, equipmentH *handler.EquipmentHandler, categoryH *handler.ExerciseCategoryHandler, muscleGroupH *handler.MuscleGroupHandler, exerciseTypeH *handler.TrainingTypeHandler, authM *handler.AuthMiddleware) http.Handler {
+// SetupSharedRoutes serves playlists through share links. Viewing needs no
+// account, copying does.
+func SetupSharedRoutes(h *handler.PlaylistHandler, authM *handler.AuthMiddleware) http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/{token}", h.GetSharedPlaylist)                                      // GET /shared/{token}
+	r.With(authM.IsAuthenticated()).Post("/{token}/copy", h.CopySharedPlaylist) // POST /shared/{token}/copy
+
+	return r
+}
+
+func SetupSessionRoutes(h *handler.SessionHandler, authM *handler.AuthMiddleware) http.Handler {
+	r := chi.NewRouter()
+
+	// All session routes require authentication
+	r.Group(func(r chi.Router) {
+		r.Use(authM.IsAuthenticated())
+
+		r.Post("/", h.StartSession)          // POST /sessions
+		r.Get("/", h.GetUserSessions)        // GET /sessions
+		r.Get("/active", h.GetActiveSession) // GET /sessions/active
+		r.Get("/{id}", h.GetSession)         // GET /sessions/{id}
+
+		// Session lifecycle
+		r.Post("/{id}/pause", h.PauseSession)   // POST /sessions/{id}/pause
+		r.Post("/{id}/resume", h.ResumeSession) // POST /sessions/{id}/resume
+		r.Post("/{id}/finish", h.FinishSession) // POST /sessions/{id}/finish
+
+		// Set logging within sessions
+		r.Post("/{id}/exercises/{exerciseId}/sets", h.AddSet) // POST /sessions/{id}/exercises/{exerciseId}/sets
+		r.Put("/{id}/sets", h.SubmitSets)                     // PUT /sessions/{id}/sets
+		r.Patch("/sets/{id}", h.UpdateSet)                    // PATCH /sessions/sets/{id}
+
+		// Cardio logging
+		r.Post("/{id}/exercises/{exerciseId}/cardio", h.AddCardioEntry) // POST /sessions/{id}/exercises/{exerciseId}/cardio
+		r.Patch("/cardio/{id}", h.UpdateCardioEntry)                    // PATCH /sessions/cardio/{id}
+	})
+
+	return r
+}
+
+func SetupCoachingRoutes(h *handler.CoachingHandler, authM *handler.AuthMiddleware) http.Handler {
+	r := chi.NewRouter()
+
+	// All coaching routes require authentication
+	r.Group(func(r chi.Router) {
+		r.Use(authM.IsAuthenticated())
+
+		// Coach side
+		r.Post("/invitations", h.InviteClient)                       // POST /coaching/invitations
+		r.Get("/clients", h.ListClients)                             // GET /coaching/clients
+		r.Get("/clients/{clientId}", h.GetClientProfile)             // GET /coaching/clients/{clientId}
+		r.Get("/clients/{clientId}/playlists", h.GetClientPlaylists) // GET /coaching/clients/{clientId}/playlists
+		r.Post("/clients/{clientId}/playlists", h.AssignPlaylist)    // POST /coaching/clients/{clientId}/playlists
+
+		// Client side
+		r.Get("/coaches", h.ListCoaches)                           // GET /coaching/coaches
+		r.Post("/relationships/{id}/accept", h.AcceptInvitation)   // POST /coaching/relationships/{id}/accept
+		r.Post("/relationships/{id}/decline", h.DeclineInvitation) // POST /coaching/relationships/{id}/decline
+
+		// Either side
+		r.Delete("/relationships/{id}", h.EndRelationship) // DELETE /coaching/relationships/{id}
+	})
+
+	return r
+}
+
+func SetupLogRoutes(h *handler.LogHandler, authM *handler.AuthMiddleware) http.Handler {
+	r := chi.NewRouter()
+
+	r.Group(func(r chi.Router) {
+		r.Use(authM.IsAuthenticated())
+		r.Get("/", h.ListLogs) // GET /logs
+	})
+
+	return r
+}
+
+func SetupAdminRoutes(exerciseH *handler.ExerciseHandler, equipmentH *handler.EquipmentHandler, categoryH *handler.ExerciseCategoryHandler, muscleGroupH *handler.MuscleGroupHandler, exerciseTypeH *handler.TrainingTypeHandler, roleH *handler.RoleHandler, authM *handler.AuthMiddleware) http.Handler {
 	r := chi.NewRouter()
 
 	r.Group(func(r chi.Router) {
@@ -101,35 +222,84 @@ func SetupAdminRoutes(exerciseH *handler.ExerciseHandler, equipmentH *handler.Eq
 		r.Route("/exercises", func(r chi.Router) {
 			// Read-only routes for any authenticated user
 			r.Get("/", exerciseH.List)
+			r.Get("/search", exerciseH.Search)
 			r.Get("/{id}", exerciseH.GetByID)
 
-			// Admin-only routes
+			// Exercise editors only
 			r.Group(func(r chi.Router) {
-				r.Use(authM.RequireAdmin())
+				r.Use(authM.RequirePermission(user.PermExerciseWrite))
 				r.Post("/", exerciseH.Create)
 				r.Put("/{id}", exerciseH.Update)
 				r.Delete("/{id}", exerciseH.Delete)
+				r.Get("/export", exerciseH.Export)
 			})
+
+			// Importing may create catalog entries as well
+			r.With(authM.RequirePermission(user.PermExerciseWrite, user.PermCatalogManage)).Post("/import", exerciseH.Import)
 		})
 
 		r.Route("/equipment", func(r chi.Router) {
 			// Read-only routes for any authenticated user
 			r.Get("/", equipmentH.List)
 			r.Get("/{id}", equipmentH.GetByID)
+
+			// Catalog managers only
+			r.Group(func(r chi.Router) {
+				r.Use(authM.RequirePermission(user.PermCatalogManage))
+				r.Post("/", equipmentH.Create)
+				r.Put("/{id}", equipmentH.Update)
+				r.Delete("/{id}", equipmentH.Delete)
+				r.Post("/{id}/merge", equipmentH.Merge)
+			})
 		})
 
 		r.Route("/exercise-categories", func(r chi.Router) {
 			// Read-only routes for any authenticated user
 			r.Get("/", categoryH.List)
 			r.Get("/{id}", categoryH.GetByID)
+
+			// Catalog managers only
+			r.Group(func(r chi.Router) {
+				r.Use(authM.RequirePermission(user.PermCatalogManage))
+				r.Post("/", categoryH.Create)
+				r.Put("/{id}", categoryH.Update)
+				r.Delete("/{id}", categoryH.Delete)
+				r.Post("/{id}/merge", categoryH.Merge)
+			})
 		})
 
 		r.Route("/muscle-groups", func(r chi.Router) {
 			r.Get("/", muscleGroupH.List)
+
+			// Catalog managers only
+			r.Group(func(r chi.Router) {
+				r.Use(authM.RequirePermission(user.PermCatalogManage))
+				r.Post("/", muscleGroupH.Create)
+				r.Put("/{id}", muscleGroupH.Update)
+				r.Delete("/{id}", muscleGroupH.Delete)
+				r.Post("/{id}/merge", muscleGroupH.Merge)
+			})
 		})
 
 		r.Route("/exercise-types", func(r chi.Router) {
 			r.Get("/", exerciseTypeH.List)
+
+			// Catalog managers only
+			r.Group(func(r chi.Router) {
+				r.Use(authM.RequirePermission(user.PermCatalogManage))
+				r.Post("/", exerciseTypeH.Create)
+				r.Put("/{id}", exerciseTypeH.Update)
+				r.Delete("/{id}", exerciseTypeH.Delete)
+				r.Post("/{id}/merge", exerciseTypeH.Merge)
+			})
+		})
+
+		// Role management
+		r.Group(func(r chi.Router) {
+			r.Use(authM.RequirePermission(user.PermRoleManage))
+			r.Get("/roles", roleH.ListRoles)                       // GET /admin/roles
+			r.Post("/users/{id}/roles", roleH.GrantRole)           // POST /admin/users/{id}/roles
+			r.Delete("/users/{id}/roles/{role}", roleH.RevokeRole) // DELETE /admin/users/{id}/roles/{role}
 		})
 	})
 	return r
@@ -3,12 +3,17 @@ package app
 
 import (
 	"database/sql"
+	"net/netip"
 
 	"github.com/cheezecakee/fitrkr/internal/db"
+	"github.com/cheezecakee/fitrkr/internal/db/coaching"
 	"github.com/cheezecakee/fitrkr/internal/db/exercise"
+	"github.com/cheezecakee/fitrkr/internal/db/log"
 	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+	"github.com/cheezecakee/fitrkr/internal/db/session"
 	"github.com/cheezecakee/fitrkr/internal/db/user"
-	"github.com/cheezecakee/fitrkr/internal/utils/auth"
+	"github.com/cheezecakee/fitrkr/internal/utils/config"
+	"github.com/cheezecakee/fitrkr/internal/utils/ratelimit"
 )
 
 type App struct {
@@ -20,45 +25,146 @@ type App struct {
 	MuscleGroupSvc      exercise.MuscleGroupService
 	TrainingTypeSvc     exercise.TrainingTypeService
 
+	// Coaching services
+	CoachingSvc coaching.CoachingService
+
 	// Playlist services
 	PlaylistSvc playlist.PlaylistService
+
+	// Session services
+	SessionSvc session.SessionService
+
+	// Log services
+	LogSvc log.LogService
+
+	// Rate limiting of the auth endpoints
+	RateLimitStore        ratelimit.Store
+	AuthRateLimit         ratelimit.Limit
+	AuthIdentityRateLimit ratelimit.Limit
+	TrustedProxies        []netip.Prefix // client IPs are read from X-Forwarded-For behind these
 }
 
-func NewApp(DBConnstring string, jwtMgr auth.JWT) *App {
-	database := db.NewConnection(DBConnstring)
+func NewApp(cfg config.Config) *App {
+	database := db.NewConnection(cfg.DBConnString)
 
 	// Exercise domain repositories
 	userRepo := user.NewUserRepo(database)
+	refreshTokenRepo := user.NewRefreshTokenRepo(database)
+	authSessionRepo := user.NewAuthSessionRepo(database)
+	passwordResetRepo := user.NewPasswordResetRepo(database)
+	emailVerificationRepo := user.NewEmailVerificationRepo(database)
+	loginLockoutRepo := user.NewLoginLockoutRepo(database)
+	roleRepo := user.NewRoleRepo(database)
 	exerciseRepo := exercise.NewExerciseRepo(database)
 	exerciseCategoryRepo := exercise.NewCategoryRepo(database)
 	equipmentRepo := exercise.NewEquipmentRepo(database)
 	muscleGroupRepo := exercise.NewMuscleGroupRepo(database)
 	TrainingTypeRepo := exercise.NewTrainingTypeRepo(database)
 
+	// Coaching domain repositories
+	coachingRepo := coaching.NewCoachingRepo(database)
+
 	// Playlist domain repositories
 	playlistRepo := playlist.NewPlaylistRepo(database)
 	exerciseBlockRepo := playlist.NewBlockRepo(database)
 	playlistExerciseRepo := playlist.NewPlaylistExerciseRepo(database)
 	exerciseConfigRepo := playlist.NewConfigRepo(database)
+	shareTokenRepo := playlist.NewShareTokenRepo(database)
+	playlistVersionRepo := playlist.NewPlaylistVersionRepo(database)
+
+	// Session domain repositories
+	sessionRepo := session.NewSessionRepo(database)
+	sessionExerciseRepo := session.NewSessionExerciseRepo(database)
+	exerciseSetRepo := session.NewExerciseSetRepo(database)
+	cardioEntryRepo := session.NewCardioEntryRepo(database)
+	recordRepo := session.NewRecordRepo(database)
+
+	// Log domain repositories
+	logRepo := log.NewLogRepo(database)
 
 	// Initialize services
+	userSvc := user.NewUserService(
+		userRepo,
+		refreshTokenRepo,
+		authSessionRepo,
+		passwordResetRepo,
+		emailVerificationRepo,
+		loginLockoutRepo,
+		roleRepo,
+		cfg.JWTManager,
+		cfg.Mailer,
+		user.AuthConfig{
+			RefreshTokenTTL:  cfg.RefreshTokenTTL,
+			PasswordResetTTL: cfg.PasswordResetTTL,
+			PasswordResetURL: cfg.PasswordResetURL,
+
+			EmailVerificationTTL: cfg.EmailVerificationTTL,
+			EmailVerificationURL: cfg.EmailVerificationURL,
+			RequireVerifiedEmail: cfg.RequireVerifiedEmail,
+
+			Lockout: user.LockoutConfig{
+				Threshold:   cfg.LoginLockoutThreshold,
+				Duration:    cfg.LoginLockoutDuration,
+				MaxDuration: cfg.LoginLockoutMax,
+				Window:      cfg.LoginFailureWindow,
+			},
+		},
+	)
+
+	coachingSvc := coaching.NewCoachingService(coachingRepo, userSvc)
+
 	playlistSvc := playlist.NewPlaylistService(
 		playlistRepo,
 		exerciseBlockRepo,
 		playlistExerciseRepo,
 		exerciseConfigRepo,
+		shareTokenRepo,
+		playlistVersionRepo,
+		userSvc,
+		coachingSvc,
 	)
 
+	sessionSvc := session.NewSessionService(
+		sessionRepo,
+		sessionExerciseRepo,
+		exerciseSetRepo,
+		cardioEntryRepo,
+		recordRepo,
+		playlistSvc,
+		userSvc,
+	)
+
+	// Shared buckets are needed when running more than one instance
+	rateLimitStore := ratelimit.NewMemoryStore()
+	if cfg.RateLimitStore == "postgres" {
+		rateLimitStore = ratelimit.NewPostgresStore(database)
+	}
+
 	return &App{
 		DB:                  database,
-		UserSvc:             user.NewUserService(userRepo, jwtMgr),
+		UserSvc:             userSvc,
 		ExerciseSvc:         exercise.NewExerciseService(exerciseRepo),
 		ExerciseCategorySvc: exercise.NewCategoryService(exerciseCategoryRepo),
 		EquipmentSvc:        exercise.NewEquipmentService(equipmentRepo),
 		MuscleGroupSvc:      exercise.NewMuscleGroupService(muscleGroupRepo),
 		TrainingTypeSvc:     exercise.NewTrainingTypeService(TrainingTypeRepo),
 
+		// Coaching service
+		CoachingSvc: coachingSvc,
+
 		// Playlist service
 		PlaylistSvc: playlistSvc,
+
+		// Session service
+		SessionSvc: sessionSvc,
+
+		// Log service
+		LogSvc: log.NewLogService(logRepo),
+
+		// Rate limiting
+		RateLimitStore:        rateLimitStore,
+		AuthRateLimit:         cfg.AuthRateLimit,
+		AuthIdentityRateLimit: cfg.AuthIdentityRateLimit,
+		TrustedProxies:        cfg.TrustedProxies,
 	}
 }
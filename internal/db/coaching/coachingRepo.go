@@ -0,0 +1,256 @@
+package coaching
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type CoachingRepo interface {
+	Create(ctx context.Context, coachID, clientID uuid.UUID) (Relationship, error)
+	GetByID(ctx context.Context, id int) (Relationship, error)
+	// ListByCoach and ListByClient return the page of open relationships
+	// after the given one, newest first
+	ListByCoach(ctx context.Context, coachID uuid.UUID, after *Relationship, limit int) ([]Relationship, error)
+	CountByCoach(ctx context.Context, coachID uuid.UUID) (int, error)
+	ListByClient(ctx context.Context, clientID uuid.UUID, after *Relationship, limit int) ([]Relationship, error)
+	CountByClient(ctx context.Context, clientID uuid.UUID) (int, error)
+
+	// Moves a relationship to status if it is currently in one of from.
+	// Returns an empty relationship when it is not.
+	UpdateStatus(ctx context.Context, id int, from []Status, status Status) (Relationship, error)
+
+	IsCoachOf(ctx context.Context, coachID, clientID uuid.UUID) (bool, error)
+	GetClientProfile(ctx context.Context, clientID uuid.UUID) (ClientProfile, error)
+}
+
+type coachingRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewCoachingRepo(db *sql.DB) CoachingRepo {
+	return &coachingRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const relationshipColumns = `cc.id, cc.coach_id, cc.client_id, cc.status, cc.responded_at, cc.ended_at, cc.created_at, cc.updated_at, coach.username, client.username`
+
+const relationshipJoins = `
+	FROM coach_clients cc
+	JOIN users coach ON coach.id = cc.coach_id
+	JOIN users client ON client.id = cc.client_id`
+
+func scanRelationship(row interface{ Scan(dest ...any) error }) (Relationship, error) {
+	var relationship Relationship
+	err := row.Scan(
+		&relationship.ID,
+		&relationship.CoachID,
+		&relationship.ClientID,
+		&relationship.Status,
+		&relationship.RespondedAt,
+		&relationship.EndedAt,
+		&relationship.CreatedAt,
+		&relationship.UpdatedAt,
+		&relationship.CoachUsername,
+		&relationship.ClientUsername,
+	)
+	return relationship, err
+}
+
+const createRelationship = `
+	INSERT INTO coach_clients (coach_id, client_id)
+	VALUES ($1, $2)
+	RETURNING id`
+
+func (r *coachingRepo) Create(ctx context.Context, coachID, clientID uuid.UUID) (Relationship, error) {
+	var id int
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, createRelationship, coachID, clientID).Scan(&id)
+	})
+	if err != nil {
+		log.Printf("Create coaching relationship failed for coach %s and client %s: %v", coachID, clientID, err)
+		return Relationship{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+const getRelationshipByID = `SELECT ` + relationshipColumns + relationshipJoins + ` WHERE cc.id = $1`
+
+func (r *coachingRepo) GetByID(ctx context.Context, id int) (Relationship, error) {
+	relationship, err := scanRelationship(r.tx.DB().QueryRowContext(ctx, getRelationshipByID, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Relationship{}, nil
+		}
+		log.Printf("Get coaching relationship failed for ID %d: %v", id, err)
+		return Relationship{}, err
+	}
+	return relationship, nil
+}
+
+const listByCoach = `SELECT ` + relationshipColumns + relationshipJoins + `
+	WHERE cc.coach_id = $1 AND cc.status IN ('pending', 'accepted')
+		AND ($2::timestamp IS NULL OR (cc.created_at, cc.id) < ($2, $3))
+	ORDER BY cc.created_at DESC, cc.id DESC
+	LIMIT $4`
+
+func (r *coachingRepo) ListByCoach(ctx context.Context, coachID uuid.UUID, after *Relationship, limit int) ([]Relationship, error) {
+	return r.list(ctx, listByCoach, coachID, after, limit)
+}
+
+const countByCoach = `SELECT COUNT(*) FROM coach_clients WHERE coach_id = $1 AND status IN ('pending', 'accepted')`
+
+func (r *coachingRepo) CountByCoach(ctx context.Context, coachID uuid.UUID) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countByCoach, coachID).Scan(&total)
+	return total, err
+}
+
+const listByClient = `SELECT ` + relationshipColumns + relationshipJoins + `
+	WHERE cc.client_id = $1 AND cc.status IN ('pending', 'accepted')
+		AND ($2::timestamp IS NULL OR (cc.created_at, cc.id) < ($2, $3))
+	ORDER BY cc.created_at DESC, cc.id DESC
+	LIMIT $4`
+
+func (r *coachingRepo) ListByClient(ctx context.Context, clientID uuid.UUID, after *Relationship, limit int) ([]Relationship, error) {
+	return r.list(ctx, listByClient, clientID, after, limit)
+}
+
+const countByClient = `SELECT COUNT(*) FROM coach_clients WHERE client_id = $1 AND status IN ('pending', 'accepted')`
+
+func (r *coachingRepo) CountByClient(ctx context.Context, clientID uuid.UUID) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countByClient, clientID).Scan(&total)
+	return total, err
+}
+
+func (r *coachingRepo) list(ctx context.Context, query string, userID uuid.UUID, after *Relationship, limit int) ([]Relationship, error) {
+	var afterCreatedAt *time.Time
+	var afterID int
+	if after != nil {
+		afterCreatedAt, afterID = &after.CreatedAt, after.ID
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, query, userID, afterCreatedAt, afterID, limit)
+	if err != nil {
+		log.Printf("List coaching relationships failed for user %s: %v", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var relationships []Relationship
+	for rows.Next() {
+		relationship, err := scanRelationship(rows)
+		if err != nil {
+			return nil, err
+		}
+		relationships = append(relationships, relationship)
+	}
+
+	return relationships, rows.Err()
+}
+
+const updateRelationshipStatus = `
+	UPDATE coach_clients
+	SET status = $2,
+		responded_at = CASE WHEN status = 'pending' THEN NOW() ELSE responded_at END,
+		ended_at = CASE WHEN $2 = 'revoked' THEN NOW() ELSE ended_at END,
+		updated_at = NOW()
+	WHERE id = $1 AND status = ANY($3)`
+
+func (r *coachingRepo) UpdateStatus(ctx context.Context, id int, from []Status, status Status) (Relationship, error) {
+	statuses := make([]string, len(from))
+	for i, s := range from {
+		statuses[i] = string(s)
+	}
+
+	var updated int64
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, updateRelationshipStatus, id, status, pq.Array(statuses))
+		if err != nil {
+			return err
+		}
+		updated, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		log.Printf("Update coaching relationship %d to %s failed: %v", id, status, err)
+		return Relationship{}, err
+	}
+	if updated == 0 {
+		return Relationship{}, nil
+	}
+	return r.GetByID(ctx, id)
+}
+
+const isCoachOf = `
+	SELECT EXISTS (
+		SELECT 1 FROM coach_clients
+		WHERE coach_id = $1 AND client_id = $2 AND status = 'accepted'
+	)`
+
+func (r *coachingRepo) IsCoachOf(ctx context.Context, coachID, clientID uuid.UUID) (bool, error) {
+	var ok bool
+	if err := r.tx.DB().QueryRowContext(ctx, isCoachOf, coachID, clientID).Scan(&ok); err != nil {
+		log.Printf("Coach check failed for coach %s and client %s: %v", coachID, clientID, err)
+		return false, err
+	}
+	return ok, nil
+}
+
+const getClientProfile = `
+	SELECT u.id, u.username, u.first_name, u.last_name,
+		s.id IS NOT NULL, s.weight, s.height, s.body_fat_percent,
+		COALESCE(s.current_streak, 0), COALESCE(s.longest_streak, 0), s.last_workout_date,
+		COALESCE(s.total_workouts, 0), COALESCE(s.total_volume_lifted, 0), COALESCE(s.total_time_minutes, 0),
+		s.recorded_at
+	FROM users u
+	LEFT JOIN LATERAL (
+		SELECT * FROM user_stats
+		WHERE user_id = u.id
+		ORDER BY recorded_at DESC NULLS LAST
+		LIMIT 1
+	) s ON TRUE
+	WHERE u.id = $1`
+
+func (r *coachingRepo) GetClientProfile(ctx context.Context, clientID uuid.UUID) (ClientProfile, error) {
+	var profile ClientProfile
+	var stats ClientStats
+	var hasStats bool
+	err := r.tx.DB().QueryRowContext(ctx, getClientProfile, clientID).Scan(
+		&profile.ID,
+		&profile.Username,
+		&profile.FirstName,
+		&profile.LastName,
+		&hasStats,
+		&stats.Weight,
+		&stats.Height,
+		&stats.BodyFatPercent,
+		&stats.CurrentStreak,
+		&stats.LongestStreak,
+		&stats.LastWorkoutDate,
+		&stats.TotalWorkouts,
+		&stats.TotalVolumeLifted,
+		&stats.TotalTimeMinutes,
+		&stats.RecordedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ClientProfile{}, nil
+		}
+		log.Printf("Get client profile failed for %s: %v", clientID, err)
+		return ClientProfile{}, err
+	}
+
+	if hasStats {
+		profile.Stats = &stats
+	}
+	return profile, nil
+}
@@ -0,0 +1,251 @@
+package coaching
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/cheezecakee/fitrkr/internal/db/user"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
+)
+
+var (
+	ErrNotCoach             = errors.New("user is not a coach")
+	ErrClientNotFound       = errors.New("client not found")
+	ErrCannotCoachSelf      = errors.New("cannot invite yourself")
+	ErrRelationshipExists   = errors.New("invitation or relationship already exists")
+	ErrRelationshipNotFound = errors.New("coaching relationship not found")
+	ErrInvalidTransition    = errors.New("relationship cannot change to the requested status")
+	ErrNotClientsCoach      = errors.New("not an accepted coach of this client")
+)
+
+// ClientAccess lets other domains check whether a user coaches another
+type ClientAccess interface {
+	IsCoachOf(ctx context.Context, coachID, clientID uuid.UUID) (bool, error)
+}
+
+type CoachingService interface {
+	// Coach side
+	InviteClient(ctx context.Context, coachID uuid.UUID, req InviteClientRequest) (Relationship, error)
+	ListClients(ctx context.Context, coachID uuid.UUID, page pagination.Request) (pagination.Page[Relationship], error)
+	GetClientProfile(ctx context.Context, coachID, clientID uuid.UUID) (ClientProfile, error)
+
+	// Client side
+	ListCoaches(ctx context.Context, clientID uuid.UUID, page pagination.Request) (pagination.Page[Relationship], error)
+	AcceptInvitation(ctx context.Context, clientID uuid.UUID, id int) (Relationship, error)
+	DeclineInvitation(ctx context.Context, clientID uuid.UUID, id int) (Relationship, error)
+
+	// Either side can end a relationship or withdraw an invitation
+	EndRelationship(ctx context.Context, userID uuid.UUID, id int) (Relationship, error)
+
+	ClientAccess
+}
+
+type coachingService struct {
+	repo    CoachingRepo
+	userSvc user.UserService
+}
+
+func NewCoachingService(repo CoachingRepo, userSvc user.UserService) CoachingService {
+	return &coachingService{
+		repo:    repo,
+		userSvc: userSvc,
+	}
+}
+
+// InviteClient sends a coaching invitation the client has to accept
+func (s *coachingService) InviteClient(ctx context.Context, coachID uuid.UUID, req InviteClientRequest) (Relationship, error) {
+	isCoach, err := s.isCoach(ctx, coachID)
+	if err != nil {
+		return Relationship{}, err
+	}
+	if !isCoach {
+		return Relationship{}, ErrNotCoach
+	}
+
+	var client user.User
+	if req.Email != "" {
+		client, err = s.userSvc.GetUserByEmail(ctx, req.Email)
+	} else {
+		client, err = s.userSvc.GetUserByUsername(ctx, req.Username)
+	}
+	if err != nil {
+		if err == user.ErrUserNotFound {
+			return Relationship{}, ErrClientNotFound
+		}
+		return Relationship{}, err
+	}
+
+	if client.ID == coachID {
+		return Relationship{}, ErrCannotCoachSelf
+	}
+
+	relationship, err := s.repo.Create(ctx, coachID, client.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return Relationship{}, ErrRelationshipExists
+		}
+		return Relationship{}, err
+	}
+
+	log.Printf("Coach %s invited client %s", coachID, client.ID)
+	return relationship, nil
+}
+
+// ListClients returns a page of the coach's clients and pending
+// invitations, newest first
+func (s *coachingService) ListClients(ctx context.Context, coachID uuid.UUID, page pagination.Request) (pagination.Page[Relationship], error) {
+	return listRelationships(ctx, coachID, page, s.repo.ListByCoach, s.repo.CountByCoach)
+}
+
+// GetClientProfile returns the profile and latest stats of an accepted client
+func (s *coachingService) GetClientProfile(ctx context.Context, coachID, clientID uuid.UUID) (ClientProfile, error) {
+	ok, err := s.IsCoachOf(ctx, coachID, clientID)
+	if err != nil {
+		return ClientProfile{}, err
+	}
+	if !ok {
+		return ClientProfile{}, ErrNotClientsCoach
+	}
+
+	profile, err := s.repo.GetClientProfile(ctx, clientID)
+	if err != nil {
+		return ClientProfile{}, err
+	}
+	if profile.ID == uuid.Nil {
+		return ClientProfile{}, ErrClientNotFound
+	}
+	return profile, nil
+}
+
+// ListCoaches returns a page of the client's coaches and open invitations,
+// newest first
+func (s *coachingService) ListCoaches(ctx context.Context, clientID uuid.UUID, page pagination.Request) (pagination.Page[Relationship], error) {
+	return listRelationships(ctx, clientID, page, s.repo.ListByClient, s.repo.CountByClient)
+}
+
+// listRelationships pages through the relationships of one side with the
+// given repo list and count
+func listRelationships(
+	ctx context.Context,
+	userID uuid.UUID,
+	page pagination.Request,
+	list func(ctx context.Context, userID uuid.UUID, after *Relationship, limit int) ([]Relationship, error),
+	count func(ctx context.Context, userID uuid.UUID) (int, error),
+) (pagination.Page[Relationship], error) {
+	var after *Relationship
+	if page.Cursor != "" {
+		after = &Relationship{}
+		if err := pagination.Decode(page.Cursor, &after.CreatedAt, &after.ID); err != nil {
+			return pagination.Page[Relationship]{}, err
+		}
+	}
+
+	size := page.PageSize()
+	relationships, err := list(ctx, userID, after, size+1)
+	if err != nil {
+		return pagination.Page[Relationship]{}, err
+	}
+	total, err := count(ctx, userID)
+	if err != nil {
+		return pagination.Page[Relationship]{}, err
+	}
+
+	return pagination.NewPage(relationships, size, total, func(relationship Relationship) string {
+		return pagination.Encode(relationship.CreatedAt, relationship.ID)
+	}), nil
+}
+
+func (s *coachingService) AcceptInvitation(ctx context.Context, clientID uuid.UUID, id int) (Relationship, error) {
+	return s.respond(ctx, clientID, id, StatusAccepted)
+}
+
+func (s *coachingService) DeclineInvitation(ctx context.Context, clientID uuid.UUID, id int) (Relationship, error) {
+	return s.respond(ctx, clientID, id, StatusDeclined)
+}
+
+// respond lets the invited client accept or decline a pending invitation
+func (s *coachingService) respond(ctx context.Context, clientID uuid.UUID, id int, status Status) (Relationship, error) {
+	relationship, err := s.getRelationship(ctx, id)
+	if err != nil {
+		return Relationship{}, err
+	}
+
+	// Only the invited client can respond, others should not see it exists
+	if relationship.ClientID != clientID {
+		return Relationship{}, ErrRelationshipNotFound
+	}
+
+	return s.updateStatus(ctx, id, []Status{StatusPending}, status)
+}
+
+// EndRelationship revokes access. The client can do this at any time, and
+// the coach can withdraw an invitation or drop a client.
+func (s *coachingService) EndRelationship(ctx context.Context, userID uuid.UUID, id int) (Relationship, error) {
+	relationship, err := s.getRelationship(ctx, id)
+	if err != nil {
+		return Relationship{}, err
+	}
+
+	if relationship.ClientID != userID && relationship.CoachID != userID {
+		return Relationship{}, ErrRelationshipNotFound
+	}
+
+	updated, err := s.updateStatus(ctx, id, []Status{StatusPending, StatusAccepted}, StatusRevoked)
+	if err != nil {
+		return Relationship{}, err
+	}
+
+	log.Printf("Coaching relationship %d ended by user %s", id, userID)
+	return updated, nil
+}
+
+// IsCoachOf reports whether coachID has an accepted relationship with
+// clientID and still holds the coach permission. Losing the coach role
+// ends delegated access without touching the relationship.
+func (s *coachingService) IsCoachOf(ctx context.Context, coachID, clientID uuid.UUID) (bool, error) {
+	if coachID == clientID {
+		return false, nil
+	}
+
+	ok, err := s.repo.IsCoachOf(ctx, coachID, clientID)
+	if err != nil || !ok {
+		return false, err
+	}
+	return s.isCoach(ctx, coachID)
+}
+
+// isCoach reports whether the user's roles grant the coach permission
+func (s *coachingService) isCoach(ctx context.Context, userID uuid.UUID) (bool, error) {
+	coach, err := s.userSvc.GetUserByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return s.userSvc.HasPermissions(ctx, coach, user.PermCoachClients)
+}
+
+func (s *coachingService) getRelationship(ctx context.Context, id int) (Relationship, error) {
+	relationship, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return Relationship{}, err
+	}
+	if relationship.ID == 0 {
+		return Relationship{}, ErrRelationshipNotFound
+	}
+	return relationship, nil
+}
+
+func (s *coachingService) updateStatus(ctx context.Context, id int, from []Status, status Status) (Relationship, error) {
+	updated, err := s.repo.UpdateStatus(ctx, id, from, status)
+	if err != nil {
+		return Relationship{}, err
+	}
+	if updated.ID == 0 {
+		return Relationship{}, ErrInvalidTransition
+	}
+	return updated, nil
+}
@@ -0,0 +1,62 @@
+// Package coaching links coaches to the clients whose playlists they manage
+package coaching
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"  // invited, waiting for the client
+	StatusAccepted Status = "accepted" // coach can manage the client's playlists
+	StatusDeclined Status = "declined"
+	StatusRevoked  Status = "revoked" // ended by either side
+)
+
+// Relationship is a coach's invitation to, or link with, a client
+type Relationship struct {
+	ID          int        `json:"id" db:"id"`
+	CoachID     uuid.UUID  `json:"coach_id" db:"coach_id"`
+	ClientID    uuid.UUID  `json:"client_id" db:"client_id"`
+	Status      Status     `json:"status" db:"status"`
+	RespondedAt *time.Time `json:"responded_at" db:"responded_at"`
+	EndedAt     *time.Time `json:"ended_at" db:"ended_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined data (not in DB)
+	CoachUsername  string `json:"coach_username"`
+	ClientUsername string `json:"client_username"`
+}
+
+// ClientStats are the latest body metrics and lifetime totals of a client
+type ClientStats struct {
+	Weight            *float64   `json:"weight"`
+	Height            *float64   `json:"height"`
+	BodyFatPercent    *float64   `json:"body_fat_percent"`
+	CurrentStreak     int        `json:"current_streak"`
+	LongestStreak     int        `json:"longest_streak"`
+	LastWorkoutDate   *time.Time `json:"last_workout_date"`
+	TotalWorkouts     int        `json:"total_workouts"`
+	TotalVolumeLifted float64    `json:"total_volume_lifted"`
+	TotalTimeMinutes  int        `json:"total_time_minutes"`
+	RecordedAt        *time.Time `json:"recorded_at"`
+}
+
+// ClientProfile is what a coach can see of a client
+type ClientProfile struct {
+	ID        uuid.UUID    `json:"id"`
+	Username  string       `json:"username"`
+	FirstName string       `json:"first_name"`
+	LastName  string       `json:"last_name"`
+	Stats     *ClientStats `json:"stats"` // nil until the client records stats
+}
+
+// InviteClientRequest invites a client by email or username
+type InviteClientRequest struct {
+	Email    string `json:"email,omitempty" example:"client@example.com"`
+	Username string `json:"username,omitempty"`
+}
@@ -0,0 +1,97 @@
+package exercise
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Errors of the catalog lists: equipment, categories, muscle groups and
+// training types
+var (
+	ErrCatalogNotFound    = errors.New("catalog entry not found")
+	ErrCatalogNameTaken   = errors.New("catalog entry name already exists")
+	ErrCatalogInUse       = errors.New("catalog entry is still used by exercises")
+	ErrInvalidCatalogName = errors.New("invalid catalog entry name")
+	ErrMergeIntoSelf      = errors.New("cannot merge a catalog entry into itself")
+	ErrReservedCatalog    = errors.New("catalog entry is used by playlist block rules")
+)
+
+// Longest names the catalog tables accept
+const (
+	maxCategoryNameLength     = 50
+	maxEquipmentNameLength    = 50
+	maxMuscleGroupNameLength  = 50
+	maxTrainingTypeNameLength = 20
+)
+
+// catalogWriter is the part of the catalog repos that deleting and merging
+// entries needs
+type catalogWriter interface {
+	CountExercises(ctx context.Context, id int) (int, error)
+	Delete(ctx context.Context, id int) error
+	// Merge moves the exercises of sourceID over to targetID and deletes
+	// sourceID in one transaction
+	Merge(ctx context.Context, sourceID, targetID int) error
+}
+
+// validateCatalogName trims name and checks it fits its column
+func validateCatalogName(name string, maxLength int) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxLength {
+		return "", fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidCatalogName, maxLength)
+	}
+	return name, nil
+}
+
+// deleteCatalogEntry deletes an entry no exercise uses. With reassignTo its
+// exercises are moved over to that entry first.
+func deleteCatalogEntry(ctx context.Context, repo catalogWriter, id int, reassignTo *int) error {
+	if reassignTo != nil {
+		return mergeCatalogEntry(ctx, repo, id, *reassignTo)
+	}
+
+	count, err := repo.CountExercises(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %d exercises, reassign them first", ErrCatalogInUse, count)
+	}
+	return catalogError(repo.Delete(ctx, id))
+}
+
+// mergeCatalogEntry folds a duplicate entry into another one
+func mergeCatalogEntry(ctx context.Context, repo catalogWriter, sourceID, targetID int) error {
+	if sourceID == targetID {
+		return ErrMergeIntoSelf
+	}
+	return catalogError(repo.Merge(ctx, sourceID, targetID))
+}
+
+// catalogError turns database errors of the catalog repos into the errors
+// above
+func catalogError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCatalogNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return ErrCatalogNameTaken
+		case "23503": // foreign_key_violation
+			return ErrCatalogInUse
+		}
+	}
+	return err
+}
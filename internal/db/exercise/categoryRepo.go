@@ -11,7 +11,12 @@ type CategoryRepo interface {
 	Create(ctx context.Context, category *Category) error
 	GetByID(ctx context.Context, id int) (*Category, error)
 	GetByName(ctx context.Context, name string) (*Category, error)
-	List(ctx context.Context, offset, limit int) ([]*Category, error)
+	List(ctx context.Context, after *NameCursor, limit int) ([]*Category, error)
+	Count(ctx context.Context) (int, error)
+	Update(ctx context.Context, category *Category) error
+	Delete(ctx context.Context, id int) error
+	CountExercises(ctx context.Context, id int) (int, error)
+	Merge(ctx context.Context, sourceID, targetID int) error
 }
 
 type DBCategoryRepo struct {
@@ -48,10 +53,20 @@ func (r *DBCategoryRepo) GetByName(ctx context.Context, name string) (*Category,
 	return category, err
 }
 
-const listCategories = `SELECT id, name FROM exercise_categories OFFSET $1 LIMIT $2`
+const listCategories = `
+SELECT id, name FROM exercise_categories
+WHERE $1::text IS NULL OR (name, id) > ($1, $2)
+ORDER BY name, id
+LIMIT $3`
 
-func (r *DBCategoryRepo) List(ctx context.Context, offset, limit int) ([]*Category, error) {
-	rows, err := r.tx.DB().QueryContext(ctx, listCategories, offset, limit)
+func (r *DBCategoryRepo) List(ctx context.Context, after *NameCursor, limit int) ([]*Category, error) {
+	var afterName *string
+	var afterID int
+	if after != nil {
+		afterName, afterID = &after.Name, after.ID
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, listCategories, afterName, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -67,3 +82,66 @@ func (r *DBCategoryRepo) List(ctx context.Context, offset, limit int) ([]*Catego
 	}
 	return categories, rows.Err()
 }
+
+const countCategories = `SELECT COUNT(*) FROM exercise_categories`
+
+func (r *DBCategoryRepo) Count(ctx context.Context) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countCategories).Scan(&total)
+	return total, err
+}
+
+const updateCategory = `UPDATE exercise_categories SET name = $2 WHERE id = $1 RETURNING id`
+
+func (r *DBCategoryRepo) Update(ctx context.Context, category *Category) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, updateCategory, category.ID, category.Name).Scan(&category.ID)
+	})
+}
+
+const deleteCategory = `DELETE FROM exercise_categories WHERE id = $1`
+
+func (r *DBCategoryRepo) Delete(ctx context.Context, id int) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, deleteCategory, id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+const countCategoryExercises = `SELECT COUNT(*) FROM exercises WHERE category_id = $1`
+
+func (r *DBCategoryRepo) CountExercises(ctx context.Context, id int) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countCategoryExercises, id).Scan(&total)
+	return total, err
+}
+
+const countCategoryPair = `SELECT COUNT(*) FROM exercise_categories WHERE id IN ($1, $2)`
+
+const reassignCategoryExercises = `UPDATE exercises SET category_id = $2 WHERE category_id = $1`
+
+func (r *DBCategoryRepo) Merge(ctx context.Context, sourceID, targetID int) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var found int
+		if err := tx.QueryRowContext(ctx, countCategoryPair, sourceID, targetID).Scan(&found); err != nil {
+			return err
+		}
+		if found != 2 {
+			return sql.ErrNoRows
+		}
+
+		if _, err := tx.ExecContext(ctx, reassignCategoryExercises, sourceID, targetID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, deleteCategory, sourceID)
+		return err
+	})
+}
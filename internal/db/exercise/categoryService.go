@@ -2,13 +2,21 @@ package exercise
 
 import (
 	"context"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 type CategoryService interface {
 	Create(ctx context.Context, category *Category) error
 	GetByID(ctx context.Context, id int) (*Category, error)
 	GetByName(ctx context.Context, name string) (*Category, error)
-	List(ctx context.Context, offset, limit int) ([]*Category, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[*Category], error)
+	Update(ctx context.Context, id int, name string) (*Category, error)
+	// Delete refuses while exercises use the entry, unless reassignTo names
+	// the entry to move them to
+	Delete(ctx context.Context, id int, reassignTo *int) error
+	// Merge folds a duplicate entry into targetID and returns the target
+	Merge(ctx context.Context, sourceID, targetID int) (*Category, error)
 }
 
 type DBCategoryService struct {
@@ -20,7 +28,12 @@ func NewCategoryService(repo CategoryRepo) CategoryService {
 }
 
 func (s *DBCategoryService) Create(ctx context.Context, category *Category) error {
-	return s.repo.Create(ctx, category)
+	name, err := validateCatalogName(category.Name, maxCategoryNameLength)
+	if err != nil {
+		return err
+	}
+	category.Name = name
+	return catalogError(s.repo.Create(ctx, category))
 }
 
 func (s *DBCategoryService) GetByID(ctx context.Context, id int) (*Category, error) {
@@ -31,6 +44,32 @@ func (s *DBCategoryService) GetByName(ctx context.Context, name string) (*Catego
 	return s.repo.GetByName(ctx, name)
 }
 
-func (s *DBCategoryService) List(ctx context.Context, offset, limit int) ([]*Category, error) {
-	return s.repo.List(ctx, offset, limit)
+func (s *DBCategoryService) List(ctx context.Context, page pagination.Request) (pagination.Page[*Category], error) {
+	return listByName(ctx, page, s.repo.List, s.repo.Count, func(category *Category) NameCursor {
+		return NameCursor{Name: category.Name, ID: category.ID}
+	})
+}
+
+func (s *DBCategoryService) Update(ctx context.Context, id int, name string) (*Category, error) {
+	name, err := validateCatalogName(name, maxCategoryNameLength)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &Category{ID: id, Name: name}
+	if err := s.repo.Update(ctx, category); err != nil {
+		return nil, catalogError(err)
+	}
+	return category, nil
+}
+
+func (s *DBCategoryService) Delete(ctx context.Context, id int, reassignTo *int) error {
+	return deleteCatalogEntry(ctx, s.repo, id, reassignTo)
+}
+
+func (s *DBCategoryService) Merge(ctx context.Context, sourceID, targetID int) (*Category, error) {
+	if err := mergeCatalogEntry(ctx, s.repo, sourceID, targetID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, targetID)
 }
@@ -11,7 +11,12 @@ type EquipmentRepo interface {
 	Create(ctx context.Context, equipment *Equipment) error
 	GetByID(ctx context.Context, id int) (*Equipment, error)
 	GetByName(ctx context.Context, name string) (*Equipment, error)
-	List(ctx context.Context, offset, limit int) ([]*Equipment, error)
+	List(ctx context.Context, after *NameCursor, limit int) ([]*Equipment, error)
+	Count(ctx context.Context) (int, error)
+	Update(ctx context.Context, equipment *Equipment) error
+	Delete(ctx context.Context, id int) error
+	CountExercises(ctx context.Context, id int) (int, error)
+	Merge(ctx context.Context, sourceID, targetID int) error
 }
 
 type DBEquipmentRepo struct {
@@ -48,10 +53,20 @@ func (r *DBEquipmentRepo) GetByName(ctx context.Context, name string) (*Equipmen
 	return equipment, err
 }
 
-const listEquipment = `SELECT id, name FROM equipment OFFSET $1 LIMIT $2`
+const listEquipment = `
+SELECT id, name FROM equipment
+WHERE $1::text IS NULL OR (name, id) > ($1, $2)
+ORDER BY name, id
+LIMIT $3`
 
-func (r *DBEquipmentRepo) List(ctx context.Context, offset, limit int) ([]*Equipment, error) {
-	rows, err := r.tx.DB().QueryContext(ctx, listEquipment, offset, limit)
+func (r *DBEquipmentRepo) List(ctx context.Context, after *NameCursor, limit int) ([]*Equipment, error) {
+	var afterName *string
+	var afterID int
+	if after != nil {
+		afterName, afterID = &after.Name, after.ID
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, listEquipment, afterName, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -67,3 +82,66 @@ func (r *DBEquipmentRepo) List(ctx context.Context, offset, limit int) ([]*Equip
 	}
 	return equipmentList, rows.Err()
 }
+
+const countEquipment = `SELECT COUNT(*) FROM equipment`
+
+func (r *DBEquipmentRepo) Count(ctx context.Context) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countEquipment).Scan(&total)
+	return total, err
+}
+
+const updateEquipment = `UPDATE equipment SET name = $2 WHERE id = $1 RETURNING id`
+
+func (r *DBEquipmentRepo) Update(ctx context.Context, equipment *Equipment) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, updateEquipment, equipment.ID, equipment.Name).Scan(&equipment.ID)
+	})
+}
+
+const deleteEquipment = `DELETE FROM equipment WHERE id = $1`
+
+func (r *DBEquipmentRepo) Delete(ctx context.Context, id int) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, deleteEquipment, id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+const countEquipmentExercises = `SELECT COUNT(*) FROM exercises WHERE equipment_id = $1`
+
+func (r *DBEquipmentRepo) CountExercises(ctx context.Context, id int) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countEquipmentExercises, id).Scan(&total)
+	return total, err
+}
+
+const countEquipmentPair = `SELECT COUNT(*) FROM equipment WHERE id IN ($1, $2)`
+
+const reassignEquipmentExercises = `UPDATE exercises SET equipment_id = $2 WHERE equipment_id = $1`
+
+func (r *DBEquipmentRepo) Merge(ctx context.Context, sourceID, targetID int) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var found int
+		if err := tx.QueryRowContext(ctx, countEquipmentPair, sourceID, targetID).Scan(&found); err != nil {
+			return err
+		}
+		if found != 2 {
+			return sql.ErrNoRows
+		}
+
+		if _, err := tx.ExecContext(ctx, reassignEquipmentExercises, sourceID, targetID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, deleteEquipment, sourceID)
+		return err
+	})
+}
@@ -2,13 +2,21 @@ package exercise
 
 import (
 	"context"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 type EquipmentService interface {
 	Create(ctx context.Context, equipment *Equipment) error
 	GetByID(ctx context.Context, id int) (*Equipment, error)
 	GetByName(ctx context.Context, name string) (*Equipment, error)
-	List(ctx context.Context, offset, limit int) ([]*Equipment, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[*Equipment], error)
+	Update(ctx context.Context, id int, name string) (*Equipment, error)
+	// Delete refuses while exercises use the entry, unless reassignTo names
+	// the entry to move them to
+	Delete(ctx context.Context, id int, reassignTo *int) error
+	// Merge folds a duplicate entry into targetID and returns the target
+	Merge(ctx context.Context, sourceID, targetID int) (*Equipment, error)
 }
 
 type DBEquipmentService struct {
@@ -20,7 +28,12 @@ func NewEquipmentService(repo EquipmentRepo) EquipmentService {
 }
 
 func (s *DBEquipmentService) Create(ctx context.Context, equipment *Equipment) error {
-	return s.repo.Create(ctx, equipment)
+	name, err := validateCatalogName(equipment.Name, maxEquipmentNameLength)
+	if err != nil {
+		return err
+	}
+	equipment.Name = name
+	return catalogError(s.repo.Create(ctx, equipment))
 }
 
 func (s *DBEquipmentService) GetByID(ctx context.Context, id int) (*Equipment, error) {
@@ -31,6 +44,32 @@ func (s *DBEquipmentService) GetByName(ctx context.Context, name string) (*Equip
 	return s.repo.GetByName(ctx, name)
 }
 
-func (s *DBEquipmentService) List(ctx context.Context, offset, limit int) ([]*Equipment, error) {
-	return s.repo.List(ctx, offset, limit)
+func (s *DBEquipmentService) List(ctx context.Context, page pagination.Request) (pagination.Page[*Equipment], error) {
+	return listByName(ctx, page, s.repo.List, s.repo.Count, func(equipment *Equipment) NameCursor {
+		return NameCursor{Name: equipment.Name, ID: equipment.ID}
+	})
+}
+
+func (s *DBEquipmentService) Update(ctx context.Context, id int, name string) (*Equipment, error) {
+	name, err := validateCatalogName(name, maxEquipmentNameLength)
+	if err != nil {
+		return nil, err
+	}
+
+	equipment := &Equipment{ID: id, Name: name}
+	if err := s.repo.Update(ctx, equipment); err != nil {
+		return nil, catalogError(err)
+	}
+	return equipment, nil
+}
+
+func (s *DBEquipmentService) Delete(ctx context.Context, id int, reassignTo *int) error {
+	return deleteCatalogEntry(ctx, s.repo, id, reassignTo)
+}
+
+func (s *DBEquipmentService) Merge(ctx context.Context, sourceID, targetID int) (*Equipment, error) {
+	if err := mergeCatalogEntry(ctx, s.repo, sourceID, targetID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, targetID)
 }
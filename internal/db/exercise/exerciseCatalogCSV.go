@@ -0,0 +1,97 @@
+package exercise
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Columns of a CSV catalog. Muscle groups and training types hold several
+// names separated by catalogListSeparator.
+var catalogColumns = []string{"name", "description", "category", "equipment", "muscle_groups", "training_types"}
+
+const catalogListSeparator = ";"
+
+// ReadCatalogCSV reads a CSV catalog. The header row names the columns, in
+// any order; muscle_groups and training_types may be left out.
+func ReadCatalogCSV(r io.Reader) ([]CatalogExercise, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: csv has no header row", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range catalogColumns[:4] {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("%w: csv is missing the %s column", ErrInvalidImport, column)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := index[column]; ok {
+			return record[i]
+		}
+		return ""
+	}
+
+	var exercises []CatalogExercise
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+
+		exercises = append(exercises, CatalogExercise{
+			Name:          field(record, "name"),
+			Description:   field(record, "description"),
+			Category:      field(record, "category"),
+			Equipment:     field(record, "equipment"),
+			MuscleGroups:  splitCatalogList(field(record, "muscle_groups")),
+			TrainingTypes: splitCatalogList(field(record, "training_types")),
+		})
+	}
+	return exercises, nil
+}
+
+// WriteCatalogCSV writes a catalog that ReadCatalogCSV reads back
+func WriteCatalogCSV(w io.Writer, exercises []CatalogExercise) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(catalogColumns); err != nil {
+		return err
+	}
+	for _, exercise := range exercises {
+		err := writer.Write([]string{
+			exercise.Name,
+			exercise.Description,
+			exercise.Category,
+			exercise.Equipment,
+			strings.Join(exercise.MuscleGroups, catalogListSeparator),
+			strings.Join(exercise.TrainingTypes, catalogListSeparator),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func splitCatalogList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return strings.Split(value, catalogListSeparator)
+}
@@ -0,0 +1,228 @@
+package exercise
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// filterField is one of the fields of ExerciseFilter that has facets
+type filterField int
+
+const (
+	noField filterField = iota
+	categoryField
+	equipmentField
+	muscleGroupField
+	trainingTypeField
+)
+
+// placeholder adds value to args and returns its placeholder
+func placeholder(args *[]any, value any) string {
+	*args = append(*args, value)
+	return fmt.Sprintf("$%d", len(*args))
+}
+
+// filterConditions builds the conditions of filter on exercises e, leaving
+// out skip. Its arguments are numbered after the ones already in args. The
+// query is always the first argument.
+func filterConditions(filter ExerciseFilter, skip filterField, args []any) (string, []any) {
+	var conditions []string
+	arg := func(value any) string {
+		return placeholder(&args, value)
+	}
+
+	if filter.Query != "" {
+		query := arg(filter.Query)
+		conditions = append(conditions, fmt.Sprintf(
+			"(e.search_vector @@ websearch_to_tsquery('english', %[1]s) OR %[1]s <%% e.name)", query))
+	}
+	if len(filter.CategoryIDs) > 0 && skip != categoryField {
+		conditions = append(conditions, "e.category_id = ANY("+arg(pq.Array(filter.CategoryIDs))+")")
+	}
+	if len(filter.EquipmentIDs) > 0 && skip != equipmentField {
+		conditions = append(conditions, "e.equipment_id = ANY("+arg(pq.Array(filter.EquipmentIDs))+")")
+	}
+	if len(filter.MuscleGroupIDs) > 0 && skip != muscleGroupField {
+		ids := arg(pq.Array(filter.MuscleGroupIDs))
+		if filter.MuscleMatch == MuscleMatchAll {
+			conditions = append(conditions, fmt.Sprintf(`(
+				SELECT COUNT(*) FROM exercise_muscles em
+				WHERE em.exercise_id = e.id AND em.muscle_group_id = ANY(%[1]s)
+			) = cardinality(%[1]s::int[])`, ids))
+		} else {
+			conditions = append(conditions, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM exercise_muscles em
+				WHERE em.exercise_id = e.id AND em.muscle_group_id = ANY(%s)
+			)`, ids))
+		}
+	}
+	if len(filter.TrainingTypeIDs) > 0 && skip != trainingTypeField {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM exercise_training_types ett
+			WHERE ett.exercise_id = e.id AND ett.type_id = ANY(%s)
+		)`, arg(pq.Array(filter.TrainingTypeIDs))))
+	}
+
+	if len(conditions) == 0 {
+		return "TRUE", args
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func (r *exerciseRepo) List(ctx context.Context, filter ExerciseFilter, after *ExerciseCursor, limit int) ([]*SearchResult, error) {
+	where, args := filterConditions(filter, noField, nil)
+
+	rank := "0::float8"
+	var orderBy, afterCondition string
+	switch filter.Sort {
+	case ExerciseSortNewest:
+		orderBy = "e.created_at DESC, e.id DESC"
+		if after != nil {
+			afterCondition = fmt.Sprintf("(e.created_at, e.id) < (%s, %s)",
+				placeholder(&args, after.CreatedAt), placeholder(&args, after.ID))
+		}
+	case ExerciseSortRelevance:
+		rank = exerciseRank
+		orderBy = "rank DESC, e.id"
+		if after != nil {
+			afterCondition = fmt.Sprintf("(%[1]s < %[2]s OR (%[1]s = %[2]s AND e.id > %[3]s))",
+				rank, placeholder(&args, after.Rank), placeholder(&args, after.ID))
+		}
+	default:
+		orderBy = "e.name, e.id"
+		if after != nil {
+			afterCondition = fmt.Sprintf("(e.name, e.id) > (%s, %s)",
+				placeholder(&args, after.Name), placeholder(&args, after.ID))
+		}
+	}
+	if afterCondition != "" {
+		where += " AND " + afterCondition
+	}
+
+	query := fmt.Sprintf(`
+		SELECT e.id, e.name, e.description, e.category_id, e.equipment_id, e.created_at, e.updated_at, %s AS rank
+		FROM exercises e
+		WHERE %s
+		ORDER BY %s
+		LIMIT %s`, rank, where, orderBy, placeholder(&args, limit))
+
+	rows, err := r.tx.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*SearchResult
+	for rows.Next() {
+		result := &SearchResult{
+			Exercise: &Exercise{
+				Category:  &Category{},
+				Equipment: &Equipment{},
+			},
+		}
+		err := rows.Scan(
+			&result.ID,
+			&result.Name,
+			&result.Description,
+			&result.Category.ID,
+			&result.Equipment.ID,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+			&result.Rank,
+		)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+func (r *exerciseRepo) Count(ctx context.Context, filter ExerciseFilter) (int, error) {
+	where, args := filterConditions(filter, noField, nil)
+
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, "SELECT COUNT(*) FROM exercises e WHERE "+where, args...).Scan(&total)
+	return total, err
+}
+
+// Each facet query counts, per value, the exercises that have it and match
+// the filter without its own field. In all mode the muscle group filter is
+// kept, as picking another muscle group narrows the result instead.
+const (
+	categoryFacets = `
+		SELECT c.id, c.name, COUNT(e.id)
+		FROM exercise_categories c
+		LEFT JOIN exercises e ON e.category_id = c.id AND %s
+		GROUP BY c.id, c.name
+		ORDER BY c.name`
+
+	equipmentFacets = `
+		SELECT eq.id, eq.name, COUNT(e.id)
+		FROM equipment eq
+		LEFT JOIN exercises e ON e.equipment_id = eq.id AND %s
+		GROUP BY eq.id, eq.name
+		ORDER BY eq.name`
+
+	muscleGroupFacets = `
+		SELECT mg.id, mg.name, COUNT(e.id)
+		FROM muscle_groups mg
+		LEFT JOIN exercise_muscles fem ON fem.muscle_group_id = mg.id
+		LEFT JOIN exercises e ON e.id = fem.exercise_id AND %s
+		GROUP BY mg.id, mg.name
+		ORDER BY mg.name`
+
+	trainingTypeFacets = `
+		SELECT tt.id, tt.name, COUNT(e.id)
+		FROM training_types tt
+		LEFT JOIN exercise_training_types fett ON fett.type_id = tt.id
+		LEFT JOIN exercises e ON e.id = fett.exercise_id AND %s
+		GROUP BY tt.id, tt.name
+		ORDER BY tt.name`
+)
+
+func (r *exerciseRepo) Facets(ctx context.Context, filter ExerciseFilter) (ExerciseFacets, error) {
+	muscleSkip := muscleGroupField
+	if filter.MuscleMatch == MuscleMatchAll {
+		muscleSkip = noField
+	}
+
+	var facets ExerciseFacets
+	var err error
+	if facets.Categories, err = r.facetCounts(ctx, categoryFacets, filter, categoryField); err != nil {
+		return ExerciseFacets{}, err
+	}
+	if facets.Equipment, err = r.facetCounts(ctx, equipmentFacets, filter, equipmentField); err != nil {
+		return ExerciseFacets{}, err
+	}
+	if facets.MuscleGroups, err = r.facetCounts(ctx, muscleGroupFacets, filter, muscleSkip); err != nil {
+		return ExerciseFacets{}, err
+	}
+	if facets.TrainingTypes, err = r.facetCounts(ctx, trainingTypeFacets, filter, trainingTypeField); err != nil {
+		return ExerciseFacets{}, err
+	}
+	return facets, nil
+}
+
+func (r *exerciseRepo) facetCounts(ctx context.Context, query string, filter ExerciseFilter, skip filterField) ([]FacetCount, error) {
+	where, args := filterConditions(filter, skip, nil)
+
+	rows, err := r.tx.DB().QueryContext(ctx, fmt.Sprintf(query, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []FacetCount{}
+	for rows.Next() {
+		var count FacetCount
+		if err := rows.Scan(&count.ID, &count.Name, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
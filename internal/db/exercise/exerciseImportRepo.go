@@ -0,0 +1,212 @@
+package exercise
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const exportExercises = `
+SELECT e.name, e.description, c.name, eq.name,
+    ARRAY(
+        SELECT mg.name FROM exercise_muscles em
+        JOIN muscle_groups mg ON mg.id = em.muscle_group_id
+        WHERE em.exercise_id = e.id
+        ORDER BY mg.name
+    ),
+    ARRAY(
+        SELECT tt.name FROM exercise_training_types ett
+        JOIN training_types tt ON tt.id = ett.type_id
+        WHERE ett.exercise_id = e.id
+        ORDER BY tt.name
+    )
+FROM exercises e
+JOIN exercise_categories c ON c.id = e.category_id
+JOIN equipment eq ON eq.id = e.equipment_id
+ORDER BY e.name`
+
+func (r *exerciseRepo) Export(ctx context.Context) ([]CatalogExercise, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, exportExercises)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exercises []CatalogExercise
+	for rows.Next() {
+		var exercise CatalogExercise
+		err := rows.Scan(
+			&exercise.Name,
+			&exercise.Description,
+			&exercise.Category,
+			&exercise.Equipment,
+			pq.Array(&exercise.MuscleGroups),
+			pq.Array(&exercise.TrainingTypes),
+		)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+	return exercises, rows.Err()
+}
+
+const (
+	getCategoryNames     = `SELECT name FROM exercise_categories`
+	getEquipmentNames    = `SELECT name FROM equipment`
+	getMuscleGroupNames  = `SELECT name FROM muscle_groups`
+	getTrainingTypeNames = `SELECT name FROM training_types`
+)
+
+func (r *exerciseRepo) GetCatalogNames(ctx context.Context) (CatalogNames, error) {
+	var names CatalogNames
+	for query, dest := range map[string]*[]string{
+		getCategoryNames:     &names.Categories,
+		getEquipmentNames:    &names.Equipment,
+		getMuscleGroupNames:  &names.MuscleGroups,
+		getTrainingTypeNames: &names.TrainingTypes,
+	} {
+		rows, err := r.tx.DB().QueryContext(ctx, query)
+		if err != nil {
+			return CatalogNames{}, err
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return CatalogNames{}, err
+			}
+			*dest = append(*dest, name)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return CatalogNames{}, err
+		}
+	}
+	return names, nil
+}
+
+const getExerciseIDsByName = `SELECT id, name FROM exercises WHERE name = ANY($1)`
+
+func (r *exerciseRepo) GetExerciseIDs(ctx context.Context, names []string) (map[string]int, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, getExerciseIDsByName, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int, len(names))
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		ids[name] = id
+	}
+	return ids, rows.Err()
+}
+
+// catalogLookup finds catalog entries by name inside an import, creating
+// the ones that do not exist yet
+type catalogLookup struct {
+	get    string // by name, selecting id and name
+	create string
+	ids    map[string]int
+}
+
+func (l *catalogLookup) id(ctx context.Context, tx *sql.Tx, name string) (int, error) {
+	if id, ok := l.ids[name]; ok {
+		return id, nil
+	}
+
+	var id int
+	err := tx.QueryRowContext(ctx, l.get, name).Scan(&id, new(string))
+	if err == sql.ErrNoRows {
+		err = tx.QueryRowContext(ctx, l.create, name).Scan(&id)
+	}
+	if err != nil {
+		return 0, err
+	}
+	l.ids[name] = id
+	return id, nil
+}
+
+func (l *catalogLookup) idList(ctx context.Context, tx *sql.Tx, names []string) ([]int, error) {
+	ids := make([]int, len(names))
+	for i, name := range names {
+		id, err := l.id(ctx, tx, name)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+const (
+	addImportedMuscle = `INSERT INTO exercise_muscles (exercise_id, muscle_group_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	addImportedType   = `INSERT INTO exercise_training_types (exercise_id, type_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+)
+
+func (r *exerciseRepo) Import(ctx context.Context, entries []ImportEntry) error {
+	categories := &catalogLookup{get: getCategoryByName, create: createCategory, ids: map[string]int{}}
+	equipment := &catalogLookup{get: getEquipmentByName, create: createEquipment, ids: map[string]int{}}
+	muscleGroups := &catalogLookup{get: getMuscleGroupByName, create: createMuscleGroup, ids: map[string]int{}}
+	trainingTypes := &catalogLookup{get: getTrainingTypeByName, create: createTrainingType, ids: map[string]int{}}
+
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		for _, entry := range entries {
+			exercise := entry.Exercise
+
+			categoryID, err := categories.id(ctx, tx, exercise.Category)
+			if err != nil {
+				return err
+			}
+			equipmentID, err := equipment.id(ctx, tx, exercise.Equipment)
+			if err != nil {
+				return err
+			}
+			muscleGroupIDs, err := muscleGroups.idList(ctx, tx, exercise.MuscleGroups)
+			if err != nil {
+				return err
+			}
+			typeIDs, err := trainingTypes.idList(ctx, tx, exercise.TrainingTypes)
+			if err != nil {
+				return err
+			}
+
+			exerciseID := entry.ExerciseID
+			if exerciseID == 0 {
+				err := tx.QueryRowContext(ctx, createExercise, exercise.Name, exercise.Description, categoryID, equipmentID).Scan(&exerciseID)
+				if err != nil {
+					return err
+				}
+			} else {
+				if _, err := tx.ExecContext(ctx, updateExercise, exerciseID, exercise.Name, exercise.Description, categoryID, equipmentID); err != nil {
+					return err
+				}
+				// The catalog row replaces the muscle groups and training types
+				if _, err := tx.ExecContext(ctx, removeAllExerciseMuscles, exerciseID); err != nil {
+					return err
+				}
+				if _, err := tx.ExecContext(ctx, removeAllExerciseTrainingTypes, exerciseID); err != nil {
+					return err
+				}
+			}
+
+			for _, muscleGroupID := range muscleGroupIDs {
+				if _, err := tx.ExecContext(ctx, addImportedMuscle, exerciseID, muscleGroupID); err != nil {
+					return err
+				}
+			}
+			for _, typeID := range typeIDs {
+				if _, err := tx.ExecContext(ctx, addImportedType, exerciseID, typeID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
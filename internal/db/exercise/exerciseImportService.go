@@ -0,0 +1,198 @@
+package exercise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidImport = errors.New("invalid catalog import")
+
+// maxImportRows caps the size of one catalog import
+const maxImportRows = 5000
+
+// Export returns the whole exercise catalog sorted by name
+func (s *exerciseService) Export(ctx context.Context) ([]CatalogExercise, error) {
+	exercises, err := s.repo.Export(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exercises == nil {
+		exercises = []CatalogExercise{}
+	}
+	return exercises, nil
+}
+
+// Import checks every row of a catalog and reports per row what it does.
+// Unless it is a dry run or a row has errors, all rows are then written in
+// a single transaction.
+func (s *exerciseService) Import(ctx context.Context, rows []CatalogExercise, opts ImportOptions) (*ImportReport, error) {
+	switch opts.OnConflict {
+	case "":
+		opts.OnConflict = ImportConflictSkip
+	case ImportConflictSkip, ImportConflictUpdate:
+	default:
+		return nil, fmt.Errorf("%w: on_conflict must be 'skip' or 'update'", ErrInvalidImport)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: catalog has no exercises", ErrInvalidImport)
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("%w: catalog has more than %d exercises", ErrInvalidImport, maxImportRows)
+	}
+
+	for i := range rows {
+		rows[i] = normalizeCatalogExercise(rows[i])
+	}
+
+	known, err := s.repo.GetCatalogNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(rows))
+	for i, row := range rows {
+		names[i] = row.Name
+	}
+	existing, err := s.repo.GetExerciseIDs(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		DryRun: opts.DryRun,
+		Missing: CatalogNames{
+			Categories:    []string{},
+			Equipment:     []string{},
+			MuscleGroups:  []string{},
+			TrainingTypes: []string{},
+		},
+		Rows: make([]ImportRow, len(rows)),
+	}
+	lists := []struct {
+		label   string
+		known   []string
+		missing *[]string
+	}{
+		{"category", known.Categories, &report.Missing.Categories},
+		{"equipment", known.Equipment, &report.Missing.Equipment},
+		{"muscle group", known.MuscleGroups, &report.Missing.MuscleGroups},
+		{"training type", known.TrainingTypes, &report.Missing.TrainingTypes},
+	}
+
+	var entries []ImportEntry
+	firstRow := make(map[string]int, len(rows))
+	for i, row := range rows {
+		result := ImportRow{Row: i + 1, Name: row.Name}
+		errs := validateCatalogExercise(row)
+
+		if first, ok := firstRow[row.Name]; ok && row.Name != "" {
+			errs = append(errs, fmt.Sprintf("duplicate of row %d", first))
+		} else {
+			firstRow[row.Name] = result.Row
+		}
+
+		if len(errs) == 0 {
+			for j, values := range [][]string{{row.Category}, {row.Equipment}, row.MuscleGroups, row.TrainingTypes} {
+				list := lists[j]
+				for _, value := range values {
+					if slices.Contains(list.known, value) {
+						continue
+					}
+					if !slices.Contains(*list.missing, value) {
+						*list.missing = append(*list.missing, value)
+					}
+					if !opts.CreateMissing {
+						errs = append(errs, fmt.Sprintf("unknown %s %q", list.label, value))
+					}
+				}
+			}
+		}
+
+		exerciseID, conflict := existing[row.Name]
+		result.Conflict = conflict
+		switch {
+		case len(errs) > 0:
+			result.Action = ImportActionError
+			result.Errors = errs
+			report.Failed++
+		case conflict && opts.OnConflict == ImportConflictSkip:
+			result.Action = ImportActionSkip
+			report.Skipped++
+		case conflict:
+			result.Action = ImportActionUpdate
+			report.Updated++
+			entries = append(entries, ImportEntry{ExerciseID: exerciseID, Exercise: row})
+		default:
+			result.Action = ImportActionCreate
+			report.Created++
+			entries = append(entries, ImportEntry{Exercise: row})
+		}
+		report.Rows[i] = result
+	}
+
+	if opts.DryRun || report.Failed > 0 || len(entries) == 0 {
+		return report, nil
+	}
+	if err := s.repo.Import(ctx, entries); err != nil {
+		return nil, err
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// normalizeCatalogExercise trims every name and drops empty and repeated
+// muscle groups and training types
+func normalizeCatalogExercise(row CatalogExercise) CatalogExercise {
+	row.Name = strings.TrimSpace(row.Name)
+	row.Description = strings.TrimSpace(row.Description)
+	row.Category = strings.TrimSpace(row.Category)
+	row.Equipment = strings.TrimSpace(row.Equipment)
+	row.MuscleGroups = normalizeNames(row.MuscleGroups)
+	row.TrainingTypes = normalizeNames(row.TrainingTypes)
+	return row
+}
+
+func normalizeNames(names []string) []string {
+	normalized := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
+
+// validateCatalogExercise checks the fields of one catalog row against the
+// same limits as the create endpoints
+func validateCatalogExercise(row CatalogExercise) []string {
+	var errs []string
+	if row.Name == "" {
+		errs = append(errs, "exercise name is required")
+	} else if utf8.RuneCountInString(row.Name) > 100 {
+		errs = append(errs, "exercise name must not exceed 100 characters")
+	}
+	if row.Description == "" {
+		errs = append(errs, "exercise description is required")
+	}
+
+	check := func(label, name string, maxLength int) {
+		if name == "" {
+			errs = append(errs, label+" is required")
+		} else if utf8.RuneCountInString(name) > maxLength {
+			errs = append(errs, fmt.Sprintf("%s %q must not exceed %d characters", label, name, maxLength))
+		}
+	}
+	check("category", row.Category, maxCategoryNameLength)
+	check("equipment", row.Equipment, maxEquipmentNameLength)
+	for _, name := range row.MuscleGroups {
+		check("muscle group", name, maxMuscleGroupNameLength)
+	}
+	for _, name := range row.TrainingTypes {
+		check("training type", name, maxTrainingTypeNameLength)
+	}
+	return errs
+}
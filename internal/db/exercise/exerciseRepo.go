@@ -11,8 +11,12 @@ type ExerciseRepo interface {
 	Create(ctx context.Context, exercise *Exercise) error
 	Update(ctx context.Context, exercise *Exercise) error
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, offset, limit int) ([]*Exercise, error)
-	Search(ctx context.Context, query string) ([]*Exercise, error)
+	// List and Search return the page after the cursor. Rank is only set
+	// when sorting by relevance.
+	List(ctx context.Context, filter ExerciseFilter, after *ExerciseCursor, limit int) ([]*SearchResult, error)
+	Count(ctx context.Context, filter ExerciseFilter) (int, error)
+	Facets(ctx context.Context, filter ExerciseFilter) (ExerciseFacets, error)
+	Search(ctx context.Context, query string, after *ExerciseCursor, limit int) ([]*SearchResult, error)
 
 	GetByID(ctx context.Context, id int) (*Exercise, error)
 	GetByName(ctx context.Context, name string) (*Exercise, error)
@@ -35,6 +39,14 @@ type ExerciseRepo interface {
 	GetExercisesByType(ctx context.Context, typeID int) ([]*Exercise, error)
 	RemoveAllExerciseTypes(ctx context.Context, exerciseID int) error
 	GetExercisesByTypeName(ctx context.Context, typeName string) ([]*Exercise, error)
+
+	// Catalog import and export
+	Export(ctx context.Context) ([]CatalogExercise, error)
+	GetCatalogNames(ctx context.Context) (CatalogNames, error)
+	GetExerciseIDs(ctx context.Context, names []string) (map[string]int, error)
+	// Import writes every entry in one transaction, creating the catalog
+	// entries they name when missing
+	Import(ctx context.Context, entries []ImportEntry) error
 }
 
 type exerciseRepo struct {
@@ -86,77 +98,71 @@ func (r *exerciseRepo) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-const listExercises = `SELECT id, name, description, category_id, equipment_id, created_at, updated_at FROM exercises OFFSET $1 LIMIT $2`
-
-func (r *exerciseRepo) List(ctx context.Context, offset, limit int) ([]*Exercise, error) {
-	rows, err := r.tx.DB().QueryContext(ctx, listExercises, offset, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// exerciseRank scores exercises against the query in $1. Full-text matches
+// on the weighted search vector come first, then names that are close to
+// the query word by word, which catches typos like "dedlift" and missing
+// spaces like "benchpress".
+const exerciseRank = `
+    CASE
+        WHEN e.search_vector @@ websearch_to_tsquery('english', $1)
+            THEN 1 + ts_rank(e.search_vector, websearch_to_tsquery('english', $1))
+        ELSE word_similarity($1, e.name)
+    END::float8`
 
-	var exercises []*Exercise
-	for rows.Next() {
-		exercise := &Exercise{
-			Category:  &Category{},
-			Equipment: &Equipment{},
-		}
-		err := rows.Scan(
-			&exercise.ID,
-			&exercise.Name,
-			&exercise.Description,
-			&exercise.Category.ID,
-			&exercise.Equipment.ID,
-			&exercise.CreatedAt,
-			&exercise.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		exercises = append(exercises, exercise)
+const searchExercise = `
+SELECT id, name, description, category_id, category_name, equipment_id, equipment_name, created_at, updated_at, rank
+FROM (
+    SELECT e.id, e.name, e.description, c.id AS category_id, c.name AS category_name,
+        eq.id AS equipment_id, eq.name AS equipment_name, e.created_at, e.updated_at,
+        ` + exerciseRank + ` AS rank
+    FROM exercises e
+    JOIN exercise_categories c ON e.category_id = c.id
+    JOIN equipment eq ON e.equipment_id = eq.id
+    WHERE e.search_vector @@ websearch_to_tsquery('english', $1) OR $1 <% e.name
+) ranked
+WHERE $2::float8 IS NULL OR rank < $2 OR (rank = $2 AND id > $3)
+ORDER BY rank DESC, id
+LIMIT $4`
+
+func (r *exerciseRepo) Search(ctx context.Context, query string, after *ExerciseCursor, limit int) ([]*SearchResult, error) {
+	var afterRank *float64
+	var afterID int
+	if after != nil {
+		afterRank, afterID = &after.Rank, after.ID
 	}
-	return exercises, rows.Err()
-}
 
-const searchExercise = `
-SELECT e.id, e.name, e.description, e.category_id, e.equipment_id, e.created_at, e.updated_at
-FROM exercises e
-JOIN exercise_categories c ON e.category_id = c.id
-JOIN equipment eq ON e.equipment_id = eq.id
-WHERE
-    e.name ILIKE '%' || $1 || '%' OR
-    e.description ILIKE '%' || $1 || '%' OR
-    c.name ILIKE '%' || $1 || '%' OR
-    eq.name ILIKE '%' || $1 || '%'`
-
-func (r *exerciseRepo) Search(ctx context.Context, query string) ([]*Exercise, error) {
-	rows, err := r.tx.DB().QueryContext(ctx, searchExercise, query)
+	rows, err := r.tx.DB().QueryContext(ctx, searchExercise, query, afterRank, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var exercises []*Exercise
+	var results []*SearchResult
 	for rows.Next() {
-		exercise := &Exercise{
-			Category:  &Category{},
-			Equipment: &Equipment{},
+		result := &SearchResult{
+			Exercise: &Exercise{
+				Category:  &Category{},
+				Equipment: &Equipment{},
+			},
 		}
 		err := rows.Scan(
-			&exercise.ID,
-			&exercise.Name,
-			&exercise.Description,
-			&exercise.Category.ID,
-			&exercise.Equipment.ID,
-			&exercise.CreatedAt,
-			&exercise.UpdatedAt,
+			&result.ID,
+			&result.Name,
+			&result.Description,
+			&result.Category.ID,
+			&result.Category.Name,
+			&result.Equipment.ID,
+			&result.Equipment.Name,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+			&result.Rank,
 		)
 		if err != nil {
 			return nil, err
 		}
-		exercises = append(exercises, exercise)
+		results = append(results, result)
 	}
-	return exercises, rows.Err()
+	return results, rows.Err()
 }
 
 const getExerciseByID = `SELECT id, name, description, category_id, equipment_id, created_at, updated_at FROM exercises WHERE id = $1 LIMIT 1`
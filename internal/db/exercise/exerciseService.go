@@ -3,6 +3,9 @@ package exercise
 import (
 	"context"
 	"errors"
+	"strings"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 // ExerciseService defines the interface for exercise-related operations
@@ -17,8 +20,8 @@ type ExerciseService interface {
 	GetByName(ctx context.Context, name string) (*Exercise, error)
 	GetByCategoryName(ctx context.Context, category string) ([]*Exercise, error)
 	GetByEquipmentName(ctx context.Context, equipment string) ([]*Exercise, error)
-	List(ctx context.Context, offset, limit int) ([]*Exercise, error)
-	Search(ctx context.Context, query string) ([]*Exercise, error)
+	List(ctx context.Context, filter ExerciseFilter, page pagination.Request) (*ExerciseList, error)
+	Search(ctx context.Context, query string, page pagination.Request) (pagination.Page[*SearchResult], error)
 
 	// Relationship operations
 	GetByMuscleGroupID(ctx context.Context, muscleGroupID int) ([]*Exercise, error)
@@ -30,6 +33,10 @@ type ExerciseService interface {
 	GetExerciseWithDetails(ctx context.Context, id int) (*Exercise, error)
 	CreateWithRelations(ctx context.Context, req *CreateExerciseRequest) (*Exercise, error)
 	UpdateWithRelations(ctx context.Context, req *UpdateExerciseRequest, exerciseID int) (*Exercise, error)
+
+	// Catalog import and export
+	Export(ctx context.Context) ([]CatalogExercise, error)
+	Import(ctx context.Context, rows []CatalogExercise, opts ImportOptions) (*ImportReport, error)
 }
 
 type exerciseService struct {
@@ -206,18 +213,106 @@ func (s *exerciseService) GetByEquipmentName(ctx context.Context, equipment stri
 	return s.repo.GetByEquipmentName(ctx, equipment)
 }
 
-func (s *exerciseService) List(ctx context.Context, offset, limit int) ([]*Exercise, error) {
-	if limit <= 0 {
-		return nil, errors.New("limit must be greater than 0")
+// List returns a page of the exercises matching filter with the total
+// number of matches and the facet counts for the filter chips
+func (s *exerciseService) List(ctx context.Context, filter ExerciseFilter, page pagination.Request) (*ExerciseList, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	switch filter.Sort {
+	case "":
+		filter.Sort = ExerciseSortName
+	case ExerciseSortName, ExerciseSortNewest:
+	case ExerciseSortRelevance:
+		if filter.Query == "" {
+			return nil, errors.New("relevance sort requires a search query")
+		}
+	default:
+		return nil, errors.New("sort must be 'name', 'newest' or 'relevance'")
+	}
+
+	switch filter.MuscleMatch {
+	case "":
+		filter.MuscleMatch = MuscleMatchAny
+	case MuscleMatchAny, MuscleMatchAll:
+	default:
+		return nil, errors.New("muscle_match must be 'any' or 'all'")
+	}
+	// All mode compares the number of matched muscle groups
+	filter.MuscleGroupIDs = uniqueIDs(filter.MuscleGroupIDs)
+
+	after, err := decodeExerciseCursor(page.Cursor, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra exercise tells whether another page exists
+	size := page.PageSize()
+	results, err := s.repo.List(ctx, filter, after, size+1)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	facets, err := s.repo.Facets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resultPage := pagination.NewPage(results, size, total, func(result *SearchResult) string {
+		return encodeExerciseCursor(result, filter.Sort)
+	})
+	exercises := make([]*Exercise, len(resultPage.Items))
+	for i, result := range resultPage.Items {
+		exercises[i] = result.Exercise
+	}
+
+	return &ExerciseList{
+		Page: pagination.Page[*Exercise]{
+			Items:      exercises,
+			NextCursor: resultPage.NextCursor,
+			Total:      total,
+		},
+		Facets: facets,
+	}, nil
+}
+
+func uniqueIDs(ids []int) []int {
+	var unique []int
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
 	}
-	return s.repo.List(ctx, offset, limit)
+	return unique
 }
 
-func (s *exerciseService) Search(ctx context.Context, query string) ([]*Exercise, error) {
+func (s *exerciseService) Search(ctx context.Context, query string, page pagination.Request) (pagination.Page[*SearchResult], error) {
+	query = strings.TrimSpace(query)
 	if query == "" {
-		return nil, errors.New("search query is required")
+		return pagination.Page[*SearchResult]{}, errors.New("search query is required")
 	}
-	return s.repo.Search(ctx, query)
+
+	after, err := decodeExerciseCursor(page.Cursor, ExerciseSortRelevance)
+	if err != nil {
+		return pagination.Page[*SearchResult]{}, err
+	}
+
+	size := page.PageSize()
+	results, err := s.repo.Search(ctx, query, after, size+1)
+	if err != nil {
+		return pagination.Page[*SearchResult]{}, err
+	}
+	total, err := s.repo.Count(ctx, ExerciseFilter{Query: query})
+	if err != nil {
+		return pagination.Page[*SearchResult]{}, err
+	}
+
+	return pagination.NewPage(results, size, total, func(result *SearchResult) string {
+		return encodeExerciseCursor(result, ExerciseSortRelevance)
+	}), nil
 }
 
 // Relationship query operations
@@ -3,6 +3,8 @@ package exercise
 
 import (
 	"time"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 type Exercise struct {
@@ -17,6 +19,63 @@ type Exercise struct {
 	UpdatedAt    time.Time      `db:"updated_at" json:"updatedAt"`
 }
 
+// SearchResult is an exercise found by Search. Rank orders the results;
+// full-text matches always rank above fuzzy name matches.
+type SearchResult struct {
+	*Exercise
+	Rank float64 `json:"rank"`
+}
+
+type ExerciseSort string
+
+const (
+	ExerciseSortName      ExerciseSort = "name"
+	ExerciseSortNewest    ExerciseSort = "newest"
+	ExerciseSortRelevance ExerciseSort = "relevance" // needs a query
+)
+
+type MuscleMatch string
+
+const (
+	MuscleMatchAny MuscleMatch = "any"
+	MuscleMatchAll MuscleMatch = "all"
+)
+
+// ExerciseFilter narrows down List. Empty fields match every exercise and
+// all given fields have to match. List fields match any of their values,
+// except muscle groups with MuscleMatchAll.
+type ExerciseFilter struct {
+	CategoryIDs     []int
+	EquipmentIDs    []int // the available equipment, exercises needing other equipment are left out
+	MuscleGroupIDs  []int
+	MuscleMatch     MuscleMatch
+	TrainingTypeIDs []int
+	Query           string
+	Sort            ExerciseSort
+}
+
+// FacetCount is the number of exercises the filter would match with this
+// value picked as well
+type FacetCount struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// ExerciseFacets counts exercises per filter value for filter chips
+type ExerciseFacets struct {
+	Categories    []FacetCount `json:"categories"`
+	Equipment     []FacetCount `json:"equipment"`
+	MuscleGroups  []FacetCount `json:"muscle_groups"`
+	TrainingTypes []FacetCount `json:"training_types"`
+}
+
+// ExerciseList is a page of filtered exercises. Total counts every match.
+type ExerciseList struct {
+	pagination.Page[*Exercise]
+	Facets ExerciseFacets `json:"facets"`
+}
+
 type ExerciseResponse struct {
 	ID           int            `db:"id" json:"id"`
 	Name         string         `db:"name" json:"name"`
@@ -51,6 +110,17 @@ type CreateExerciseRequest struct {
 	MuscleGroupIDs []int  `json:"muscleGroupIDs"`
 }
 
+// CatalogEntryRequest creates or renames an equipment, category, muscle
+// group or training type
+type CatalogEntryRequest struct {
+	Name string `json:"name"`
+}
+
+// MergeCatalogRequest names the entry a duplicate is folded into
+type MergeCatalogRequest struct {
+	IntoID int `json:"intoID"`
+}
+
 type Category struct {
 	ID   int
 	Name string
@@ -70,3 +140,76 @@ type MuscleGroup struct {
 	ID   int
 	Name string
 }
+
+// CatalogExercise is one exercise of an exported or imported catalog. Its
+// category, equipment, muscle groups and training types go by name so a
+// catalog can move between environments.
+type CatalogExercise struct {
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	Category      string   `json:"category"`
+	Equipment     string   `json:"equipment"`
+	MuscleGroups  []string `json:"muscleGroups"`
+	TrainingTypes []string `json:"trainingTypes"`
+}
+
+// CatalogNames lists catalog entries per list
+type CatalogNames struct {
+	Categories    []string `json:"categories"`
+	Equipment     []string `json:"equipment"`
+	MuscleGroups  []string `json:"muscleGroups"`
+	TrainingTypes []string `json:"trainingTypes"`
+}
+
+// ImportConflict is what an import does with a row naming an existing
+// exercise
+type ImportConflict string
+
+const (
+	ImportConflictSkip   ImportConflict = "skip"
+	ImportConflictUpdate ImportConflict = "update"
+)
+
+type ImportOptions struct {
+	DryRun        bool
+	CreateMissing bool // create unknown categories, equipment, muscle groups and training types
+	OnConflict    ImportConflict
+}
+
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "create"
+	ImportActionUpdate ImportAction = "update"
+	ImportActionSkip   ImportAction = "skip"
+	ImportActionError  ImportAction = "error"
+)
+
+// ImportRow reports what an import does with one catalog row
+type ImportRow struct {
+	Row      int          `json:"row"` // 1 based, not counting the CSV header
+	Name     string       `json:"name"`
+	Action   ImportAction `json:"action"`
+	Conflict bool         `json:"conflict"` // an exercise with this name exists
+	Errors   []string     `json:"errors,omitempty"`
+}
+
+// ImportReport is the outcome of a catalog import. Nothing is written on a
+// dry run or when any row has errors.
+type ImportReport struct {
+	DryRun  bool         `json:"dryRun"`
+	Applied bool         `json:"applied"`
+	Created int          `json:"created"`
+	Updated int          `json:"updated"`
+	Skipped int          `json:"skipped"`
+	Failed  int          `json:"failed"`
+	Missing CatalogNames `json:"missing"` // unknown entries, created with CreateMissing
+	Rows    []ImportRow  `json:"rows"`
+}
+
+// ImportEntry is a checked catalog row ready to be written. A zero
+// ExerciseID creates the exercise, any other updates it.
+type ImportEntry struct {
+	ExerciseID int
+	Exercise   CatalogExercise
+}
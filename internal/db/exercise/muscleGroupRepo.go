@@ -11,7 +11,12 @@ type MuscleGroupRepo interface {
 	Create(ctx context.Context, muscleGroup *MuscleGroup) error
 	GetByID(ctx context.Context, id int) (*MuscleGroup, error)
 	GetByName(ctx context.Context, name string) (*MuscleGroup, error)
-	List(ctx context.Context, offset, limit int) ([]*MuscleGroup, error)
+	List(ctx context.Context, after *NameCursor, limit int) ([]*MuscleGroup, error)
+	Count(ctx context.Context) (int, error)
+	Update(ctx context.Context, muscleGroup *MuscleGroup) error
+	Delete(ctx context.Context, id int) error
+	CountExercises(ctx context.Context, id int) (int, error)
+	Merge(ctx context.Context, sourceID, targetID int) error
 }
 
 type muscleGroupRepo struct {
@@ -48,10 +53,20 @@ func (r *muscleGroupRepo) GetByName(ctx context.Context, name string) (*MuscleGr
 	return muscleGroup, err
 }
 
-const listMuscleGroups = `SELECT id, name FROM muscle_groups OFFSET $1 LIMIT $2`
+const listMuscleGroups = `
+SELECT id, name FROM muscle_groups
+WHERE $1::text IS NULL OR (name, id) > ($1, $2)
+ORDER BY name, id
+LIMIT $3`
 
-func (r *muscleGroupRepo) List(ctx context.Context, offset, limit int) ([]*MuscleGroup, error) {
-	rows, err := r.tx.DB().QueryContext(ctx, listMuscleGroups, offset, limit)
+func (r *muscleGroupRepo) List(ctx context.Context, after *NameCursor, limit int) ([]*MuscleGroup, error) {
+	var afterName *string
+	var afterID int
+	if after != nil {
+		afterName, afterID = &after.Name, after.ID
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, listMuscleGroups, afterName, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -67,3 +82,69 @@ func (r *muscleGroupRepo) List(ctx context.Context, offset, limit int) ([]*Muscl
 	}
 	return muscleGroups, rows.Err()
 }
+
+const countMuscleGroups = `SELECT COUNT(*) FROM muscle_groups`
+
+func (r *muscleGroupRepo) Count(ctx context.Context) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countMuscleGroups).Scan(&total)
+	return total, err
+}
+
+const updateMuscleGroup = `UPDATE muscle_groups SET name = $2 WHERE id = $1 RETURNING id`
+
+func (r *muscleGroupRepo) Update(ctx context.Context, muscleGroup *MuscleGroup) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, updateMuscleGroup, muscleGroup.ID, muscleGroup.Name).Scan(&muscleGroup.ID)
+	})
+}
+
+const deleteMuscleGroup = `DELETE FROM muscle_groups WHERE id = $1`
+
+func (r *muscleGroupRepo) Delete(ctx context.Context, id int) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, deleteMuscleGroup, id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+const countMuscleGroupExercises = `SELECT COUNT(*) FROM exercise_muscles WHERE muscle_group_id = $1`
+
+func (r *muscleGroupRepo) CountExercises(ctx context.Context, id int) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countMuscleGroupExercises, id).Scan(&total)
+	return total, err
+}
+
+const countMuscleGroupPair = `SELECT COUNT(*) FROM muscle_groups WHERE id IN ($1, $2)`
+
+const reassignMuscleGroupExercises = `
+INSERT INTO exercise_muscles (exercise_id, muscle_group_id)
+SELECT exercise_id, $2 FROM exercise_muscles WHERE muscle_group_id = $1
+ON CONFLICT DO NOTHING`
+
+func (r *muscleGroupRepo) Merge(ctx context.Context, sourceID, targetID int) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var found int
+		if err := tx.QueryRowContext(ctx, countMuscleGroupPair, sourceID, targetID).Scan(&found); err != nil {
+			return err
+		}
+		if found != 2 {
+			return sql.ErrNoRows
+		}
+
+		if _, err := tx.ExecContext(ctx, reassignMuscleGroupExercises, sourceID, targetID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, deleteMuscleGroup, sourceID)
+		return err
+	})
+}
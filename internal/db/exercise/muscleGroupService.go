@@ -2,13 +2,21 @@ package exercise
 
 import (
 	"context"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 type MuscleGroupService interface {
 	Create(ctx context.Context, muscleGroup *MuscleGroup) error
 	GetByID(ctx context.Context, id int) (*MuscleGroup, error)
 	GetByName(ctx context.Context, name string) (*MuscleGroup, error)
-	List(ctx context.Context, offset, limit int) ([]*MuscleGroup, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[*MuscleGroup], error)
+	Update(ctx context.Context, id int, name string) (*MuscleGroup, error)
+	// Delete refuses while exercises use the entry, unless reassignTo names
+	// the entry to move them to
+	Delete(ctx context.Context, id int, reassignTo *int) error
+	// Merge folds a duplicate entry into targetID and returns the target
+	Merge(ctx context.Context, sourceID, targetID int) (*MuscleGroup, error)
 }
 
 type muscleGroupService struct {
@@ -20,7 +28,12 @@ func NewMuscleGroupService(repo MuscleGroupRepo) MuscleGroupService {
 }
 
 func (s *muscleGroupService) Create(ctx context.Context, muscleGroup *MuscleGroup) error {
-	return s.repo.Create(ctx, muscleGroup)
+	name, err := validateCatalogName(muscleGroup.Name, maxMuscleGroupNameLength)
+	if err != nil {
+		return err
+	}
+	muscleGroup.Name = name
+	return catalogError(s.repo.Create(ctx, muscleGroup))
 }
 
 func (s *muscleGroupService) GetByID(ctx context.Context, id int) (*MuscleGroup, error) {
@@ -31,6 +44,32 @@ func (s *muscleGroupService) GetByName(ctx context.Context, name string) (*Muscl
 	return s.repo.GetByName(ctx, name)
 }
 
-func (s *muscleGroupService) List(ctx context.Context, offset, limit int) ([]*MuscleGroup, error) {
-	return s.repo.List(ctx, offset, limit)
+func (s *muscleGroupService) List(ctx context.Context, page pagination.Request) (pagination.Page[*MuscleGroup], error) {
+	return listByName(ctx, page, s.repo.List, s.repo.Count, func(muscleGroup *MuscleGroup) NameCursor {
+		return NameCursor{Name: muscleGroup.Name, ID: muscleGroup.ID}
+	})
+}
+
+func (s *muscleGroupService) Update(ctx context.Context, id int, name string) (*MuscleGroup, error) {
+	name, err := validateCatalogName(name, maxMuscleGroupNameLength)
+	if err != nil {
+		return nil, err
+	}
+
+	muscleGroup := &MuscleGroup{ID: id, Name: name}
+	if err := s.repo.Update(ctx, muscleGroup); err != nil {
+		return nil, catalogError(err)
+	}
+	return muscleGroup, nil
+}
+
+func (s *muscleGroupService) Delete(ctx context.Context, id int, reassignTo *int) error {
+	return deleteCatalogEntry(ctx, s.repo, id, reassignTo)
+}
+
+func (s *muscleGroupService) Merge(ctx context.Context, sourceID, targetID int) (*MuscleGroup, error) {
+	if err := mergeCatalogEntry(ctx, s.repo, sourceID, targetID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, targetID)
 }
@@ -0,0 +1,93 @@
+package exercise
+
+import (
+	"context"
+	"time"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
+)
+
+// NameCursor is the position of the last item of a page sorted by name
+type NameCursor struct {
+	Name string
+	ID   int
+}
+
+// ExerciseCursor is the position of the last exercise of a page. Only the
+// fields of the sort in use are set.
+type ExerciseCursor struct {
+	Name      string
+	CreatedAt time.Time
+	Rank      float64
+	ID        int
+}
+
+// decodeExerciseCursor reads a cursor made by encodeExerciseCursor for the
+// same sort
+func decodeExerciseCursor(cursor string, sort ExerciseSort) (*ExerciseCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	var after ExerciseCursor
+	var err error
+	switch sort {
+	case ExerciseSortNewest:
+		err = pagination.Decode(cursor, &after.CreatedAt, &after.ID)
+	case ExerciseSortRelevance:
+		err = pagination.Decode(cursor, &after.Rank, &after.ID)
+	default:
+		err = pagination.Decode(cursor, &after.Name, &after.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+func encodeExerciseCursor(result *SearchResult, sort ExerciseSort) string {
+	switch sort {
+	case ExerciseSortNewest:
+		return pagination.Encode(result.CreatedAt, result.ID)
+	case ExerciseSortRelevance:
+		return pagination.Encode(result.Rank, result.ID)
+	default:
+		return pagination.Encode(result.Name, result.ID)
+	}
+}
+
+// listByName pages through one of the catalog lists, which are all sorted
+// by name
+func listByName[T any](
+	ctx context.Context,
+	page pagination.Request,
+	list func(ctx context.Context, after *NameCursor, limit int) ([]T, error),
+	count func(ctx context.Context) (int, error),
+	key func(T) NameCursor,
+) (pagination.Page[T], error) {
+	var after *NameCursor
+	if page.Cursor != "" {
+		var cursor NameCursor
+		if err := pagination.Decode(page.Cursor, &cursor.Name, &cursor.ID); err != nil {
+			return pagination.Page[T]{}, err
+		}
+		after = &cursor
+	}
+
+	// One extra item tells whether another page exists
+	size := page.PageSize()
+	items, err := list(ctx, after, size+1)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	total, err := count(ctx)
+	if err != nil {
+		return pagination.Page[T]{}, err
+	}
+
+	return pagination.NewPage(items, size, total, func(item T) string {
+		cursor := key(item)
+		return pagination.Encode(cursor.Name, cursor.ID)
+	}), nil
+}
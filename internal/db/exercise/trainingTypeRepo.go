@@ -11,7 +11,12 @@ type TrainingTypeRepo interface {
 	Create(ctx context.Context, exerciseType *TrainingType) error
 	GetByName(ctx context.Context, name string) (*TrainingType, error)
 	GetByID(ctx context.Context, id int) (*TrainingType, error)
-	List(ctx context.Context, offset, limit int) ([]*TrainingType, error)
+	List(ctx context.Context, after *NameCursor, limit int) ([]*TrainingType, error)
+	Count(ctx context.Context) (int, error)
+	Update(ctx context.Context, exerciseType *TrainingType) error
+	Delete(ctx context.Context, id int) error
+	CountExercises(ctx context.Context, id int) (int, error)
+	Merge(ctx context.Context, sourceID, targetID int) error
 }
 
 type trainingTypeRepo struct {
@@ -48,10 +53,20 @@ func (r *trainingTypeRepo) GetByName(ctx context.Context, name string) (*Trainin
 	return exerciseType, err
 }
 
-const listTrainingTypes = `SELECT id, name FROM training_types OFFSET $1 LIMIT $2`
+const listTrainingTypes = `
+SELECT id, name FROM training_types
+WHERE $1::text IS NULL OR (name, id) > ($1, $2)
+ORDER BY name, id
+LIMIT $3`
 
-func (r *trainingTypeRepo) List(ctx context.Context, offset, limit int) ([]*TrainingType, error) {
-	rows, err := r.tx.DB().QueryContext(ctx, listTrainingTypes, offset, limit)
+func (r *trainingTypeRepo) List(ctx context.Context, after *NameCursor, limit int) ([]*TrainingType, error) {
+	var afterName *string
+	var afterID int
+	if after != nil {
+		afterName, afterID = &after.Name, after.ID
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, listTrainingTypes, afterName, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -67,3 +82,69 @@ func (r *trainingTypeRepo) List(ctx context.Context, offset, limit int) ([]*Trai
 	}
 	return exerciseTypes, rows.Err()
 }
+
+const countTrainingTypes = `SELECT COUNT(*) FROM training_types`
+
+func (r *trainingTypeRepo) Count(ctx context.Context) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countTrainingTypes).Scan(&total)
+	return total, err
+}
+
+const updateTrainingType = `UPDATE training_types SET name = $2 WHERE id = $1 RETURNING id`
+
+func (r *trainingTypeRepo) Update(ctx context.Context, exerciseType *TrainingType) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, updateTrainingType, exerciseType.ID, exerciseType.Name).Scan(&exerciseType.ID)
+	})
+}
+
+const deleteTrainingType = `DELETE FROM training_types WHERE id = $1`
+
+func (r *trainingTypeRepo) Delete(ctx context.Context, id int) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, deleteTrainingType, id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+const countTrainingTypeExercises = `SELECT COUNT(*) FROM exercise_training_types WHERE type_id = $1`
+
+func (r *trainingTypeRepo) CountExercises(ctx context.Context, id int) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countTrainingTypeExercises, id).Scan(&total)
+	return total, err
+}
+
+const countTrainingTypePair = `SELECT COUNT(*) FROM training_types WHERE id IN ($1, $2)`
+
+const reassignTrainingTypeExercises = `
+INSERT INTO exercise_training_types (exercise_id, type_id)
+SELECT exercise_id, $2 FROM exercise_training_types WHERE type_id = $1
+ON CONFLICT DO NOTHING`
+
+func (r *trainingTypeRepo) Merge(ctx context.Context, sourceID, targetID int) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var found int
+		if err := tx.QueryRowContext(ctx, countTrainingTypePair, sourceID, targetID).Scan(&found); err != nil {
+			return err
+		}
+		if found != 2 {
+			return sql.ErrNoRows
+		}
+
+		if _, err := tx.ExecContext(ctx, reassignTrainingTypeExercises, sourceID, targetID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, deleteTrainingType, sourceID)
+		return err
+	})
+}
@@ -2,13 +2,26 @@ package exercise
 
 import (
 	"context"
+	"strings"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
+// CardioTrainingType is the training type the playlist block rules match,
+// compared without case. It cannot be renamed, merged away or deleted.
+const CardioTrainingType = "cardio"
+
 type TrainingTypeService interface {
 	Create(ctx context.Context, exerciseType *TrainingType) error
 	GetByID(ctx context.Context, id int) (*TrainingType, error)
 	GetByName(ctx context.Context, name string) (*TrainingType, error)
-	List(ctx context.Context, offset, limit int) ([]*TrainingType, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[*TrainingType], error)
+	Update(ctx context.Context, id int, name string) (*TrainingType, error)
+	// Delete refuses while exercises use the entry, unless reassignTo names
+	// the entry to move them to
+	Delete(ctx context.Context, id int, reassignTo *int) error
+	// Merge folds a duplicate entry into targetID and returns the target
+	Merge(ctx context.Context, sourceID, targetID int) (*TrainingType, error)
 }
 
 type trainingTypeService struct {
@@ -20,7 +33,12 @@ func NewTrainingTypeService(repo TrainingTypeRepo) TrainingTypeService {
 }
 
 func (s *trainingTypeService) Create(ctx context.Context, exerciseType *TrainingType) error {
-	return s.repo.Create(ctx, exerciseType)
+	name, err := validateCatalogName(exerciseType.Name, maxTrainingTypeNameLength)
+	if err != nil {
+		return err
+	}
+	exerciseType.Name = name
+	return catalogError(s.repo.Create(ctx, exerciseType))
 }
 
 func (s *trainingTypeService) GetByID(ctx context.Context, id int) (*TrainingType, error) {
@@ -31,6 +49,57 @@ func (s *trainingTypeService) GetByName(ctx context.Context, name string) (*Trai
 	return s.repo.GetByName(ctx, name)
 }
 
-func (s *trainingTypeService) List(ctx context.Context, offset, limit int) ([]*TrainingType, error) {
-	return s.repo.List(ctx, offset, limit)
+func (s *trainingTypeService) List(ctx context.Context, page pagination.Request) (pagination.Page[*TrainingType], error) {
+	return listByName(ctx, page, s.repo.List, s.repo.Count, func(trainingType *TrainingType) NameCursor {
+		return NameCursor{Name: trainingType.Name, ID: trainingType.ID}
+	})
+}
+
+func (s *trainingTypeService) Update(ctx context.Context, id int, name string) (*TrainingType, error) {
+	name, err := validateCatalogName(name, maxTrainingTypeNameLength)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(name, CardioTrainingType) {
+		if err := s.checkNotReserved(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	exerciseType := &TrainingType{ID: id, Name: name}
+	if err := s.repo.Update(ctx, exerciseType); err != nil {
+		return nil, catalogError(err)
+	}
+	return exerciseType, nil
+}
+
+func (s *trainingTypeService) Delete(ctx context.Context, id int, reassignTo *int) error {
+	if err := s.checkNotReserved(ctx, id); err != nil {
+		return err
+	}
+	return deleteCatalogEntry(ctx, s.repo, id, reassignTo)
+}
+
+func (s *trainingTypeService) Merge(ctx context.Context, sourceID, targetID int) (*TrainingType, error) {
+	if err := s.checkNotReserved(ctx, sourceID); err != nil {
+		return nil, err
+	}
+	if err := mergeCatalogEntry(ctx, s.repo, sourceID, targetID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, targetID)
+}
+
+// checkNotReserved refuses to change the name of the cardio training type
+// or remove it, which would silently turn off the cardio block rules
+func (s *trainingTypeService) checkNotReserved(ctx context.Context, id int) error {
+	trainingType, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return catalogError(err)
+	}
+	if strings.EqualFold(trainingType.Name, CardioTrainingType) {
+		return ErrReservedCatalog
+	}
+	return nil
 }
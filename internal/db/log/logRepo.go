@@ -0,0 +1,172 @@
+package log
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type LogRepo interface {
+	// Inserts all logs in a single transaction
+	CreateMany(ctx context.Context, logs []Log) ([]Log, error)
+
+	// Feed of a user, newest first, starting after the given log when set
+	List(ctx context.Context, userID uuid.UUID, filter ListLogsRequest, after *Log, limit int) ([]Log, error)
+	Count(ctx context.Context, userID uuid.UUID, filter ListLogsRequest) (int, error)
+}
+
+type logRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewLogRepo(db *sql.DB) LogRepo {
+	return &logRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const createLog = `
+	INSERT INTO logs (user_id, playlist_id, session, metadata, type, priority, message, pr)
+	VALUES ($1, $2, $3, COALESCE($4, '{}'::jsonb), $5, $6, $7, $8)
+	RETURNING id, created_at, updated_at`
+
+func (r *logRepo) CreateMany(ctx context.Context, logs []Log) ([]Log, error) {
+	var created []Log
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var err error
+		created, err = InsertLogs(ctx, tx, logs)
+		return err
+	})
+	if err != nil {
+		log.Printf("Create logs failed: %v", err)
+		return nil, err
+	}
+	return created, nil
+}
+
+// InsertLogs writes logs inside a transaction of another repo, so they are
+// only kept if the change they describe is
+func InsertLogs(ctx context.Context, tx *sql.Tx, logs []Log) ([]Log, error) {
+	created := make([]Log, 0, len(logs))
+	for _, entry := range logs {
+		var metadata []byte
+		if len(entry.Metadata) > 0 {
+			metadata = entry.Metadata
+		}
+		err := tx.QueryRowContext(ctx, createLog,
+			entry.UserID,
+			entry.PlaylistID,
+			entry.SessionID,
+			metadata,
+			entry.Type,
+			entry.Priority,
+			entry.Message,
+			entry.PR,
+		).Scan(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, entry)
+	}
+	return created, nil
+}
+
+const listLogs = `
+	SELECT id, user_id, playlist_id, session, COALESCE(metadata, '{}'::jsonb), type, priority, message,
+		   COALESCE(pr, FALSE), created_at, updated_at
+	FROM logs
+	WHERE %s
+	ORDER BY created_at DESC, id DESC
+	LIMIT %s`
+
+// logConditions builds the conditions of filter on the logs of userID
+func logConditions(userID uuid.UUID, filter ListLogsRequest) ([]string, []any) {
+	conditions := []string{"user_id = $1"}
+	args := []any{userID}
+	where := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if filter.Type != nil {
+		where("type = %s", *filter.Type)
+	}
+	if filter.Priority != nil {
+		where("priority = %s", *filter.Priority)
+	}
+	if filter.PlaylistID != nil {
+		where("playlist_id = %s", *filter.PlaylistID)
+	}
+	if filter.PR != nil {
+		where("COALESCE(pr, FALSE) = %s", *filter.PR)
+	}
+	if filter.From != nil {
+		where("created_at >= %s", *filter.From)
+	}
+	if filter.To != nil {
+		where("created_at < %s", *filter.To)
+	}
+
+	return conditions, args
+}
+
+func (r *logRepo) List(ctx context.Context, userID uuid.UUID, filter ListLogsRequest, after *Log, limit int) ([]Log, error) {
+	conditions, args := logConditions(userID, filter)
+	if after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(listLogs, strings.Join(conditions, " AND "), fmt.Sprintf("$%d", len(args)))
+	rows, err := r.tx.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []Log
+	for rows.Next() {
+		var entry Log
+		var metadata []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.PlaylistID,
+			&entry.SessionID,
+			&metadata,
+			&entry.Type,
+			&entry.Priority,
+			&entry.Message,
+			&entry.PR,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.Metadata = metadata
+		logs = append(logs, entry)
+	}
+
+	return logs, rows.Err()
+}
+
+func (r *logRepo) Count(ctx context.Context, userID uuid.UUID, filter ListLogsRequest) (int, error) {
+	conditions, args := logConditions(userID, filter)
+	query := "SELECT COUNT(*) FROM logs WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, query, args...).Scan(&total)
+	return total, err
+}
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
+)
+
+var ErrInvalidFilter = errors.New("invalid log filter")
+
+type LogService interface {
+	ListLogs(ctx context.Context, userID uuid.UUID, filter ListLogsRequest, page pagination.Request) (pagination.Page[Log], error)
+}
+
+type logService struct {
+	logRepo LogRepo
+}
+
+func NewLogService(logRepo LogRepo) LogService {
+	return &logService{
+		logRepo: logRepo,
+	}
+}
+
+// ListLogs returns a page of the user's feed, newest first
+func (s *logService) ListLogs(ctx context.Context, userID uuid.UUID, filter ListLogsRequest, page pagination.Request) (pagination.Page[Log], error) {
+	if filter.Type != nil && !filter.Type.IsValid() {
+		return pagination.Page[Log]{}, fmt.Errorf("%w: unknown type %q", ErrInvalidFilter, *filter.Type)
+	}
+	if filter.Priority != nil && !filter.Priority.IsValid() {
+		return pagination.Page[Log]{}, fmt.Errorf("%w: unknown priority %q", ErrInvalidFilter, *filter.Priority)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return pagination.Page[Log]{}, fmt.Errorf("%w: from must be before to", ErrInvalidFilter)
+	}
+
+	var after *Log
+	if page.Cursor != "" {
+		after = &Log{}
+		if err := pagination.Decode(page.Cursor, &after.CreatedAt, &after.ID); err != nil {
+			return pagination.Page[Log]{}, err
+		}
+	}
+
+	size := page.PageSize()
+	logs, err := s.logRepo.List(ctx, userID, filter, after, size+1)
+	if err != nil {
+		return pagination.Page[Log]{}, err
+	}
+	total, err := s.logRepo.Count(ctx, userID, filter)
+	if err != nil {
+		return pagination.Page[Log]{}, err
+	}
+
+	return pagination.NewPage(logs, size, total, func(entry Log) string {
+		return pagination.Encode(entry.CreatedAt, entry.ID)
+	}), nil
+}
@@ -1 +1,92 @@
+// Package log
 package log
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Type string
+
+const (
+	TypePRAchieved       Type = "PR_Achieved"
+	TypeWorkoutCompleted Type = "Workout_Completed"
+)
+
+type Priority string
+
+const (
+	PriorityLegendary Priority = "Legendary"
+	PriorityRare      Priority = "Rare"
+	PriorityUncommon  Priority = "Uncommon"
+	PriorityCommon    Priority = "Common"
+)
+
+// Log is an entry in a user's activity feed
+type Log struct {
+	ID         int64           `json:"id" db:"id"`
+	UserID     uuid.UUID       `json:"user_id" db:"user_id"`
+	PlaylistID *int            `json:"playlist_id" db:"playlist_id"`
+	SessionID  *int            `json:"session_id" db:"session"`
+	Metadata   json.RawMessage `json:"metadata" db:"metadata" swaggertype:"object"`
+	Type       Type            `json:"type" db:"type"`         // 'PR_Achieved', 'Workout_Completed'
+	Priority   Priority        `json:"priority" db:"priority"` // 'Legendary', 'Rare', 'Uncommon', 'Common'
+	Message    string          `json:"message" db:"message"`
+	PR         bool            `json:"pr" db:"pr"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+type RecordKind string
+
+const (
+	RecordHeaviestWeight   RecordKind = "heaviest_weight"
+	RecordMostRepsAtWeight RecordKind = "most_reps_at_weight"
+	RecordEstimatedOneRM   RecordKind = "estimated_1rm"
+	RecordBestVolume       RecordKind = "best_volume"
+	RecordLongestDistance  RecordKind = "longest_distance"
+	RecordFastestPace      RecordKind = "fastest_pace"
+)
+
+// PRMetadata is stored in the metadata column of PR_Achieved logs
+type PRMetadata struct {
+	Record            RecordKind `json:"record"`
+	ExerciseID        int        `json:"exercise_id"`
+	ExerciseName      string     `json:"exercise_name"`
+	SessionExerciseID int        `json:"session_exercise_id"`
+	SetID             *int       `json:"set_id,omitempty"`
+	Value             float64    `json:"value"`
+	Previous          float64    `json:"previous"`
+	Weight            *float64   `json:"weight,omitempty"` // weight the reps record was set at
+	Reps              *int       `json:"reps,omitempty"`
+}
+
+// IsValid reports whether t is a known log type
+func (t Type) IsValid() bool {
+	switch t {
+	case TypePRAchieved, TypeWorkoutCompleted:
+		return true
+	}
+	return false
+}
+
+// IsValid reports whether p is a known priority tier
+func (p Priority) IsValid() bool {
+	switch p {
+	case PriorityLegendary, PriorityRare, PriorityUncommon, PriorityCommon:
+		return true
+	}
+	return false
+}
+
+// ListLogsRequest filters the feed. Nil filters are ignored.
+type ListLogsRequest struct {
+	Type       *Type
+	Priority   *Priority
+	PlaylistID *int
+	PR         *bool
+	From       *time.Time // inclusive
+	To         *time.Time // exclusive
+}
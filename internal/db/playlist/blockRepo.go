@@ -17,6 +17,10 @@ type BlockRepo interface {
 
 	// Reorder blocks within a playlist
 	UpdateBlockOrders(ctx context.Context, playlistID int, blockOrders []BlockOrder) error
+
+	// Deletes a block and closes the gap in the block order. With moveTo its
+	// exercises are appended to that block, otherwise they are deleted.
+	DeleteAndNormalize(ctx context.Context, playlistID, blockID int, moveTo *int) error
 }
 
 type BlockOrder struct {
@@ -177,3 +181,46 @@ func (r *exerciseBlockRepo) UpdateBlockOrders(ctx context.Context, playlistID in
 		return nil
 	})
 }
+
+const appendBlockExercises = `
+	UPDATE playlist_exercises pe
+	SET block_id = $2,
+		exercise_order = o.n + (SELECT COALESCE(MAX(exercise_order), 0) FROM playlist_exercises WHERE block_id = $2),
+		updated_at = NOW()
+	FROM (
+		SELECT id, ROW_NUMBER() OVER (ORDER BY exercise_order, id) AS n
+		FROM playlist_exercises
+		WHERE block_id = $1
+	) o
+	WHERE pe.id = o.id`
+
+const normalizeBlockOrders = `
+	UPDATE exercise_blocks b
+	SET block_order = o.n
+	FROM (
+		SELECT id, ROW_NUMBER() OVER (ORDER BY block_order, id) AS n
+		FROM exercise_blocks
+		WHERE playlist_id = $1
+	) o
+	WHERE b.id = o.id AND b.block_order <> o.n`
+
+func (r *exerciseBlockRepo) DeleteAndNormalize(ctx context.Context, playlistID, blockID int, moveTo *int) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if moveTo != nil {
+			if _, err := tx.ExecContext(ctx, appendBlockExercises, blockID, *moveTo); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, deleteBlock, blockID); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, normalizeBlockOrders, playlistID)
+		return err
+	})
+	if err != nil {
+		log.Printf("Delete exercise block %d failed: %v", blockID, err)
+	}
+	return err
+}
@@ -0,0 +1,164 @@
+package playlist
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cheezecakee/fitrkr/internal/db/exercise"
+)
+
+// Severity tells whether a violation blocks saving
+type Severity string
+
+const (
+	SeverityError      Severity = "error"      // changes that cause it are rejected
+	SeverityIncomplete Severity = "incomplete" // allowed while the block is being built
+)
+
+// Rule names reported in violations
+const (
+	RuleExerciseCount  = "exercise_count"
+	RuleSingleExercise = "single_exercise"
+	RuleWeightSteps    = "weight_steps"
+	RuleTrainingType   = "training_type"
+)
+
+// BlockRule is what a block type accepts. Types without a rule accept any
+// exercises.
+type BlockRule struct {
+	MinExercises   int
+	MaxExercises   int    // 0 is unlimited
+	SingleExercise bool   // every entry is the same exercise
+	WeightSteps    bool   // entries have weights that decrease in order
+	TrainingType   string // every exercise must have this training type
+}
+
+// cardioTrainingType is matched by name, so the catalog refuses to rename
+// or remove it
+const cardioTrainingType = exercise.CardioTrainingType
+
+var BlockRules = map[BlockType]BlockRule{
+	BlockTypeSuperset: {MinExercises: 2, MaxExercises: 2},
+	BlockTypeTriset:   {MinExercises: 3, MaxExercises: 3},
+	BlockTypeCircuit:  {MinExercises: 3},
+	BlockTypeDropset:  {MinExercises: 2, SingleExercise: true, WeightSteps: true},
+	BlockTypeCardio:   {TrainingType: cardioTrainingType},
+}
+
+// Violation is a block rule a block breaks
+type Violation struct {
+	BlockID            int       `json:"block_id"`
+	BlockName          string    `json:"block_name"`
+	BlockType          BlockType `json:"block_type"`
+	PlaylistExerciseID *int      `json:"playlist_exercise_id,omitempty"` // set when one exercise breaks it
+	Rule               string    `json:"rule"`
+	Severity           Severity  `json:"severity"`
+	Message            string    `json:"message"`
+}
+
+// ValidationReport lists the violations of every block of a playlist
+type ValidationReport struct {
+	PlaylistID int         `json:"playlist_id"`
+	Valid      bool        `json:"valid"` // no violations of any severity
+	Violations []Violation `json:"violations"`
+}
+
+// ValidationError is returned when a change would break a block rule
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("block rules violated: %s", e.Violations[0].Message)
+}
+
+// ValidateBlock checks a block against the rule of its type. The block's
+// exercises must be loaded; configs are only needed for weight steps.
+// trainingTypes maps exercise IDs to lower-case training type names.
+func ValidateBlock(block Block, trainingTypes map[int][]string) []Violation {
+	rule, ok := BlockRules[block.BlockType]
+	if !ok {
+		return nil
+	}
+
+	var violations []Violation
+	add := func(exercise *PlaylistExercise, name string, severity Severity, format string, args ...any) {
+		violation := Violation{
+			BlockID:   block.ID,
+			BlockName: block.Name,
+			BlockType: block.BlockType,
+			Rule:      name,
+			Severity:  severity,
+			Message:   fmt.Sprintf(format, args...),
+		}
+		if exercise != nil && exercise.ID != 0 {
+			id := exercise.ID
+			violation.PlaylistExerciseID = &id
+		}
+		violations = append(violations, violation)
+	}
+
+	count := len(block.Exercises)
+	if rule.MaxExercises > 0 && count > rule.MaxExercises {
+		add(nil, RuleExerciseCount, SeverityError, "%s takes at most %d exercises, has %d", block.BlockType, rule.MaxExercises, count)
+	}
+	if count < rule.MinExercises {
+		add(nil, RuleExerciseCount, SeverityIncomplete, "%s needs at least %d exercises, has %d", block.BlockType, rule.MinExercises, count)
+	}
+
+	exercises := slices.Clone(block.Exercises)
+	slices.SortStableFunc(exercises, func(a, b PlaylistExercise) int {
+		return a.ExerciseOrder - b.ExerciseOrder
+	})
+
+	for i := range exercises {
+		exercise := &exercises[i]
+
+		if rule.SingleExercise && exercise.ExerciseID != exercises[0].ExerciseID {
+			add(exercise, RuleSingleExercise, SeverityError, "%s must repeat one exercise", block.BlockType)
+		}
+
+		if rule.TrainingType != "" && !slices.Contains(trainingTypes[exercise.ExerciseID], rule.TrainingType) {
+			add(exercise, RuleTrainingType, SeverityError, "%s only accepts %s exercises", block.BlockType, rule.TrainingType)
+		}
+
+		if rule.WeightSteps {
+			weight := configWeight(exercise)
+			switch {
+			case weight == nil:
+				add(exercise, RuleWeightSteps, SeverityIncomplete, "each %s step needs a weight", block.BlockType)
+			case i > 0 && configWeight(&exercises[i-1]) != nil && *weight >= *configWeight(&exercises[i-1]):
+				add(exercise, RuleWeightSteps, SeverityIncomplete, "%s weights must decrease with each step", block.BlockType)
+			}
+		}
+	}
+
+	return violations
+}
+
+// blockErrors returns the violations that reject a change
+func blockErrors(violations []Violation) error {
+	var errs []Violation
+	for _, violation := range violations {
+		if violation.Severity == SeverityError {
+			errs = append(errs, violation)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: errs}
+}
+
+func configWeight(exercise *PlaylistExercise) *float64 {
+	if exercise.Config == nil {
+		return nil
+	}
+	return exercise.Config.Weight
+}
+
+// normalizeTrainingType lower-cases a training type name for rule matching
+func normalizeTrainingType(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -16,7 +16,7 @@ func (v Visibility) IsValid() bool {
 
 func (bt BlockType) IsValid() bool {
 	switch bt {
-	case BlockTypeStandard, BlockTypeSuperset, BlockTypeTriset, BlockTypeCircuit,
+	case BlockTypePlaylist, BlockTypeStandard, BlockTypeSuperset, BlockTypeTriset, BlockTypeCircuit,
 		BlockTypeDropset, BlockTypeCardio, BlockTypeWarmup, BlockTypeCooldown:
 		return true
 	}
@@ -14,6 +14,8 @@ import (
 type ConfigRepo interface {
 	Create(ctx context.Context, config Config) (Config, error)
 	GetByID(ctx context.Context, id int) (Config, error)
+	// Update replaces every field of the config as long as it still has
+	// config.UpdatedAt. A zero Config means it was changed in between.
 	Update(ctx context.Context, config Config) (Config, error)
 	Delete(ctx context.Context, id int) error
 
@@ -118,20 +120,20 @@ func (r *exerciseConfigRepo) GetByID(ctx context.Context, id int) (Config, error
 
 const updateConfig = `
 	UPDATE exercise_configs 
-	SET sets = COALESCE($2, sets),
-		reps_min = COALESCE($3, reps_min),
-		reps_max = COALESCE($4, reps_max),
-		weight = COALESCE($5, weight),
-		rest_seconds = COALESCE($6, rest_seconds),
-		tempo = COALESCE($7, tempo),
-		duration_seconds = COALESCE($8, duration_seconds),
-		distance = COALESCE($9, distance),
-		target_pace = COALESCE($10, target_pace),
-		target_heart_rate = COALESCE($11, target_heart_rate),
-		incline = COALESCE($12, incline),
-		notes = COALESCE($13, notes),
+	SET sets = $2,
+		reps_min = $3,
+		reps_max = $4,
+		weight = $5,
+		rest_seconds = $6,
+		tempo = $7,
+		duration_seconds = $8,
+		distance = $9,
+		target_pace = $10,
+		target_heart_rate = $11,
+		incline = $12,
+		notes = $13,
 		updated_at = NOW()
-	WHERE id = $1
+	WHERE id = $1 AND updated_at = $14
 	RETURNING id, sets, reps_min, reps_max, weight, rest_seconds, tempo,
 		duration_seconds, distance, target_pace, target_heart_rate, incline, 
 		notes, created_at, updated_at`
@@ -153,6 +155,7 @@ func (r *exerciseConfigRepo) Update(ctx context.Context, config Config) (Config,
 			config.TargetHeartRate,
 			config.Incline,
 			config.Notes,
+			config.UpdatedAt,
 		).Scan(
 			&updatedConfig.ID,
 			&updatedConfig.Sets,
@@ -171,6 +174,9 @@ func (r *exerciseConfigRepo) Update(ctx context.Context, config Config) (Config,
 			&updatedConfig.UpdatedAt,
 		)
 	})
+	if err == sql.ErrNoRows {
+		return Config{}, nil
+	}
 	if err != nil {
 		log.Printf("Update exercise config failed for ID %d: %v", config.ID, err)
 		return Config{}, err
@@ -40,15 +40,18 @@ type Tag struct {
 
 // Playlist represents a workout playlist
 type Playlist struct {
-	ID          int       `json:"id" db:"id"`
-	UserID      uuid.UUID `json:"user_id" db:"user_id"`
-	Title       string    `json:"title" db:"title"`
-	Description *string   `json:"description" db:"description"`
-	IsActive    bool      `json:"is_active" db:"is_active"`
-	LastWorked  bool      `json:"last_worked" db:"last_worked"`
-	Visibility  Visibility    `json:"visibility" db:"visibility"` // 'private', 'public', 'unlisted'
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID               int        `json:"id" db:"id"`
+	UserID           uuid.UUID  `json:"user_id" db:"user_id"`
+	Title            string     `json:"title" db:"title"`
+	Description      *string    `json:"description" db:"description"`
+	IsActive         bool       `json:"is_active" db:"is_active"`
+	LastWorked       bool       `json:"last_worked" db:"last_worked"`
+	Visibility       Visibility `json:"visibility" db:"visibility"`                           // 'private', 'public', 'unlisted'
+	AssignedBy       *uuid.UUID `json:"assigned_by,omitempty" db:"assigned_by"`               // coach that created it for the owner
+	SourcePlaylistID *int       `json:"source_playlist_id,omitempty" db:"source_playlist_id"` // playlist this one was copied from
+	CopyCount        int        `json:"copy_count" db:"copy_count"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
 
 	// Joined data (not in DB)
 	Tags   []Tag   `json:"tags,omitempty"`
@@ -57,12 +60,12 @@ type Playlist struct {
 
 // Block represents an exercise block within a playlist
 type Block struct {
-	ID                    int    `json:"id" db:"id"`
-	PlaylistID            int    `json:"playlist_id" db:"playlist_id"`
-	Name                  string `json:"name" db:"name"`
+	ID                    int       `json:"id" db:"id"`
+	PlaylistID            int       `json:"playlist_id" db:"playlist_id"`
+	Name                  string    `json:"name" db:"name"`
 	BlockType             BlockType `json:"block_type" db:"block_type"` // 'playlist', 'standard', 'superset', 'circuit', 'dropset', etc.
-	BlockOrder            int    `json:"block_order" db:"block_order"`
-	RestAfterBlockSeconds int    `json:"rest_after_block_seconds" db:"rest_after_block_seconds"`
+	BlockOrder            int       `json:"block_order" db:"block_order"`
+	RestAfterBlockSeconds int       `json:"rest_after_block_seconds" db:"rest_after_block_seconds"`
 
 	// Joined data (not in DB)
 	Exercises []PlaylistExercise `json:"exercises,omitempty"`
@@ -113,6 +116,9 @@ type CreatePlaylistRequest struct {
 	Visibility  string  `json:"visibility" validate:"oneof=private public unlisted"`
 	GoalID      *int    `json:"goal_id,omitempty"`
 	TagIDs      []int   `json:"tag_ids,omitempty"`
+
+	// Coaches create playlists in an accepted client's account
+	ClientID *uuid.UUID `json:"client_id,omitempty"`
 }
 
 type UpdatePlaylistRequest struct {
@@ -123,6 +129,25 @@ type UpdatePlaylistRequest struct {
 	TagIDs      []int   `json:"tag_ids,omitempty"`
 }
 
+// BulkPlaylistRequest creates a playlist with its tags, blocks, exercises
+// and configs at once. Without blocks it gets the usual default block.
+type BulkPlaylistRequest struct {
+	CreatePlaylistRequest
+	Blocks []BulkBlockRequest `json:"blocks,omitempty"`
+}
+
+type BulkBlockRequest struct {
+	Name                  string                `json:"name" validate:"required" example:"Push Block"`
+	BlockType             string                `json:"block_type,omitempty" example:"superset"`         // playlist when omitted
+	RestAfterBlockSeconds *int                  `json:"rest_after_block_seconds,omitempty" example:"90"` // 60 when omitted
+	Exercises             []BulkExerciseRequest `json:"exercises,omitempty"`
+}
+
+type BulkExerciseRequest struct {
+	ExerciseID int    `json:"exercise_id" validate:"required" example:"4"`
+	Config     Config `json:"config"`
+}
+
 type AddExerciseToPlaylistRequest struct {
 	ExerciseID int     `json:"exercise_id" validate:"required" example:"4"`
 	BlockID    *int    `json:"block_id,omitempty" example:"1"` // If nil, creates new block
@@ -130,9 +155,226 @@ type AddExerciseToPlaylistRequest struct {
 	Config     Config  `json:"config" validate:"required"`
 }
 
+// UpdateConfigRequest changes some fields of an exercise config. Omitted
+// fields keep their value and nullable fields named in Clear are set to null.
+// UpdatedAt has to be the updated_at the client last read, otherwise the
+// update is rejected as a conflict.
+type UpdateConfigRequest struct {
+	Sets        *int     `json:"sets,omitempty" example:"4"`
+	RepsMin     *int     `json:"reps_min,omitempty" example:"6"`
+	RepsMax     *int     `json:"reps_max,omitempty" example:"10"`
+	Weight      *float64 `json:"weight,omitempty" example:"55.0"`
+	RestSeconds *int     `json:"rest_seconds,omitempty" example:"90"`
+	Tempo       []int64  `json:"tempo,omitempty" example:"3,1,1,0"`
+
+	DurationSeconds *int     `json:"duration_seconds,omitempty" example:"900"`
+	Distance        *float64 `json:"distance,omitempty" example:"3.0"`
+	TargetPace      *float64 `json:"target_pace,omitempty" example:"5.5"`
+	TargetHeartRate *int     `json:"target_heart_rate,omitempty" example:"150"`
+	Incline         *float64 `json:"incline,omitempty" example:"2.0"`
+
+	Notes *string  `json:"notes,omitempty" example:"Pause at the bottom"`
+	Clear []string `json:"clear,omitempty" example:"weight,notes"`
+
+	UpdatedAt time.Time `json:"updated_at" validate:"required"`
+}
+
+type UpdateBlockRequest struct {
+	Name                  *string `json:"name,omitempty" example:"Push Block"`
+	BlockType             *string `json:"block_type,omitempty" example:"superset"`
+	RestAfterBlockSeconds *int    `json:"rest_after_block_seconds,omitempty" example:"90"`
+}
+
+// MoveExerciseRequest moves an exercise to a position in a block of the
+// same playlist
+type MoveExerciseRequest struct {
+	BlockID  int  `json:"block_id" validate:"required" example:"2"`
+	Position *int `json:"position,omitempty" example:"1"` // 1-based, appended when omitted
+}
+
 // PlaylistWithDetails includes all related data
 type PlaylistWithDetails struct {
 	Playlist
 	TotalExercises int `json:"total_exercises"`
 	TotalBlocks    int `json:"total_blocks"`
 }
+
+type DiscoverSort string
+
+const (
+	SortNewest     DiscoverSort = "newest"
+	SortMostCopied DiscoverSort = "most_copied"
+)
+
+// DiscoverPlaylistsRequest filters public playlists. Each filter matches
+// playlists with any of its values, and all given filters must match.
+type DiscoverPlaylistsRequest struct {
+	TagIDs         []int
+	BlockTypes     []BlockType
+	EquipmentIDs   []int // of the exercises inside
+	MuscleGroupIDs []int // targeted by the exercises inside
+	Sort           DiscoverSort
+}
+
+// PlaylistAuthor is the public identity of a playlist owner
+type PlaylistAuthor struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+}
+
+// PublicPlaylist is a discover result. The owner is shown by name only.
+type PublicPlaylist struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Description *string   `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Tags        []Tag     `json:"tags"`
+
+	Author                   PlaylistAuthor `json:"author"`
+	TotalExercises           int            `json:"total_exercises"`
+	TotalBlocks              int            `json:"total_blocks"`
+	EstimatedDurationSeconds int            `json:"estimated_duration_seconds"`
+	CopyCount                int            `json:"copy_count"`
+}
+
+// ShareToken is a revocable link to an unlisted or public playlist
+type ShareToken struct {
+	ID         int        `json:"id" db:"id"`
+	PlaylistID int        `json:"playlist_id" db:"playlist_id"`
+	Token      string     `json:"token" db:"token"`
+	CreatedBy  *uuid.UUID `json:"created_by" db:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"` // nil never expires
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateShareTokenRequest struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2030-01-01T00:00:00Z"`
+}
+
+// SharedPlaylist is what anyone with a share link can see. It leaves out
+// the owner, the notes on exercise configs and every playlist, block and
+// config ID, so the link stays the only way in.
+type SharedPlaylist struct {
+	Title       string        `json:"title"`
+	Description *string       `json:"description"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Tags        []Tag         `json:"tags"`
+	Blocks      []SharedBlock `json:"blocks"`
+}
+
+type SharedBlock struct {
+	Name                  string           `json:"name"`
+	BlockType             BlockType        `json:"block_type"`
+	BlockOrder            int              `json:"block_order"`
+	RestAfterBlockSeconds int              `json:"rest_after_block_seconds"`
+	Exercises             []SharedExercise `json:"exercises"`
+}
+
+// SharedExercise refers to the exercise catalog, which is public
+type SharedExercise struct {
+	ExerciseID    int           `json:"exercise_id"`
+	ExerciseName  string        `json:"exercise_name"`
+	ExerciseOrder int           `json:"exercise_order"`
+	Config        *SharedConfig `json:"config,omitempty"`
+}
+
+// SharedConfig holds the targets of a Config without its notes
+type SharedConfig struct {
+	Sets        *int     `json:"sets"`
+	RepsMin     *int     `json:"reps_min"`
+	RepsMax     *int     `json:"reps_max"`
+	Weight      *float64 `json:"weight"`
+	RestSeconds int      `json:"rest_seconds"`
+	Tempo       []int64  `json:"tempo"`
+
+	DurationSeconds *int     `json:"duration_seconds"`
+	Distance        *float64 `json:"distance"`
+	TargetPace      *float64 `json:"target_pace"`
+	TargetHeartRate *int     `json:"target_heart_rate"`
+	Incline         *float64 `json:"incline"`
+}
+
+// VersionChange is the edit that produced a playlist version
+type VersionChange string
+
+const (
+	ChangeCreated            VersionChange = "created"
+	ChangeBaseline           VersionChange = "baseline" // state before the first tracked edit
+	ChangePlaylistUpdated    VersionChange = "playlist_updated"
+	ChangeBlockCreated       VersionChange = "block_created"
+	ChangeBlockUpdated       VersionChange = "block_updated"
+	ChangeBlockDeleted       VersionChange = "block_deleted"
+	ChangeBlocksReordered    VersionChange = "blocks_reordered"
+	ChangeExerciseAdded      VersionChange = "exercise_added"
+	ChangeExerciseRemoved    VersionChange = "exercise_removed"
+	ChangeExerciseMoved      VersionChange = "exercise_moved"
+	ChangeExercisesReordered VersionChange = "exercises_reordered"
+	ChangeConfigUpdated      VersionChange = "config_updated"
+	ChangeRestored           VersionChange = "restored"
+)
+
+// PlaylistVersion is an immutable snapshot of the playlist tree
+type PlaylistVersion struct {
+	ID         int           `json:"id" db:"id"`
+	PlaylistID int           `json:"playlist_id" db:"playlist_id"`
+	Version    int           `json:"version" db:"version"`
+	Change     VersionChange `json:"change" db:"change"`
+	CreatedBy  *uuid.UUID    `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+
+	// Only loaded for a single version
+	Snapshot *Playlist `json:"snapshot,omitempty" db:"snapshot"`
+}
+
+// VersionDiff is the structural difference between two versions
+type VersionDiff struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+
+	Fields             []FieldChange  `json:"fields"` // title, description, visibility and tags
+	AddedBlocks        []BlockRef     `json:"added_blocks"`
+	RemovedBlocks      []BlockRef     `json:"removed_blocks"`
+	AddedExercises     []ExerciseRef  `json:"added_exercises"`
+	RemovedExercises   []ExerciseRef  `json:"removed_exercises"`
+	ReorderedExercises []ExerciseMove `json:"reordered_exercises"`
+	ConfigChanges      []ConfigChange `json:"config_changes"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type BlockRef struct {
+	BlockID   int       `json:"block_id"`
+	Name      string    `json:"name"`
+	BlockType BlockType `json:"block_type"`
+}
+
+type ExerciseRef struct {
+	PlaylistExerciseID int    `json:"playlist_exercise_id"`
+	ExerciseID         int    `json:"exercise_id"`
+	ExerciseName       string `json:"exercise_name"`
+	BlockName          string `json:"block_name"`
+}
+
+// ExerciseMove is an exercise that changed position or block
+type ExerciseMove struct {
+	ExerciseRef
+	FromBlock string `json:"from_block"`
+	FromOrder int    `json:"from_order"`
+	ToOrder   int    `json:"to_order"`
+}
+
+// ConfigChange is a config field that changed on an exercise kept in both
+// versions
+type ConfigChange struct {
+	ExerciseRef
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
@@ -2,8 +2,11 @@ package playlist
 
 import (
 	"context"
+	"sort"
 
 	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 // CreateBlock creates a new exercise block
@@ -32,20 +35,300 @@ func (s *playlistService) CreateBlock(ctx context.Context, playlistID int, userI
 		RestAfterBlockSeconds: 60,
 	}
 
-	return s.blockRepo.Create(ctx, newBlock)
+	s.snapshotBaseline(ctx, playlistID, userID)
+
+	createdBlock, err := s.blockRepo.Create(ctx, newBlock)
+	if err != nil {
+		return Block{}, err
+	}
+
+	s.recordVersion(ctx, playlistID, userID, ChangeBlockCreated)
+	return createdBlock, nil
+}
+
+// UpdateBlock renames or retypes a block or changes its rest time
+func (s *playlistService) UpdateBlock(ctx context.Context, playlistID, blockID int, userID uuid.UUID, req UpdateBlockRequest) (Block, error) {
+	block, err := s.getPlaylistBlock(ctx, playlistID, blockID, userID)
+	if err != nil {
+		return Block{}, err
+	}
+
+	if req.Name != nil {
+		if *req.Name == "" {
+			return Block{}, ErrInvalidBlockName
+		}
+		block.Name = *req.Name
+	}
+	if req.BlockType != nil {
+		if !isValidBlockType(*req.BlockType) {
+			return Block{}, ErrInvalidBlockType
+		}
+		block.BlockType = BlockType(*req.BlockType)
+
+		// The exercises already in it have to fit the new type
+		block.Exercises, err = s.playlistExerciseRepo.GetBlockExercises(ctx, blockID)
+		if err != nil {
+			return Block{}, err
+		}
+		if err := s.checkBlockRules(ctx, block); err != nil {
+			return Block{}, err
+		}
+	}
+	if req.RestAfterBlockSeconds != nil {
+		if *req.RestAfterBlockSeconds < 0 {
+			return Block{}, ErrInvalidRest
+		}
+		block.RestAfterBlockSeconds = *req.RestAfterBlockSeconds
+	}
+
+	s.snapshotBaseline(ctx, playlistID, userID)
+
+	updatedBlock, err := s.blockRepo.Update(ctx, block)
+	if err != nil {
+		return Block{}, err
+	}
+
+	s.recordVersion(ctx, playlistID, userID, ChangeBlockUpdated)
+	return updatedBlock, nil
+}
+
+// DeleteBlock removes a block. Its exercises are moved to the end of block
+// moveTo if given, and deleted with the block otherwise. Moving is rejected
+// when the target would break its type's rules.
+func (s *playlistService) DeleteBlock(ctx context.Context, playlistID, blockID int, userID uuid.UUID, moveTo *int) error {
+	if _, err := s.getPlaylistBlock(ctx, playlistID, blockID, userID); err != nil {
+		return err
+	}
+
+	if moveTo != nil {
+		if *moveTo == blockID {
+			return ErrBlockNotFound
+		}
+		target, err := s.blockRepo.GetByID(ctx, *moveTo)
+		if err != nil {
+			return err
+		}
+		if target.ID == 0 || target.PlaylistID != playlistID {
+			return ErrBlockNotFound
+		}
+
+		// The target has to follow its type's rules with the moved exercises
+		target.Exercises, err = s.playlistExerciseRepo.GetBlockExercises(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		moved, err := s.playlistExerciseRepo.GetBlockExercises(ctx, blockID)
+		if err != nil {
+			return err
+		}
+		target.Exercises = append(target.Exercises, moved...)
+		if err := s.checkBlockRules(ctx, target); err != nil {
+			return err
+		}
+	}
+
+	s.snapshotBaseline(ctx, playlistID, userID)
+
+	if err := s.blockRepo.DeleteAndNormalize(ctx, playlistID, blockID, moveTo); err != nil {
+		return err
+	}
+
+	s.recordVersion(ctx, playlistID, userID, ChangeBlockDeleted)
+	return nil
 }
 
-// UpdateBlockOrder reorders blocks within a playlist
+// UpdateBlockOrder reorders blocks within a playlist. Every block has to be
+// listed once. Orders are renumbered from 1 in the requested sequence, so
+// only their relative values matter.
 func (s *playlistService) UpdateBlockOrder(ctx context.Context, playlistID int, userID uuid.UUID, blockOrders []BlockOrder) error {
 	// Validate access
 	if err := s.ValidatePlaylistAccess(ctx, playlistID, userID); err != nil {
 		return err
 	}
 
-	return s.blockRepo.UpdateBlockOrders(ctx, playlistID, blockOrders)
+	blocks, err := s.blockRepo.GetPlaylistBlocks(ctx, playlistID)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, len(blockOrders))
+	for i, order := range blockOrders {
+		ids[i] = order.BlockID
+	}
+	existing := make([]int, len(blocks))
+	for i, block := range blocks {
+		existing[i] = block.ID
+	}
+	if !sameIDs(ids, existing) {
+		return ErrInvalidOrder
+	}
+
+	sort.SliceStable(blockOrders, func(i, j int) bool {
+		return blockOrders[i].Order < blockOrders[j].Order
+	})
+	for i := range blockOrders {
+		blockOrders[i].Order = i + 1
+	}
+
+	s.snapshotBaseline(ctx, playlistID, userID)
+
+	if err := s.blockRepo.UpdateBlockOrders(ctx, playlistID, blockOrders); err != nil {
+		return err
+	}
+
+	s.recordVersion(ctx, playlistID, userID, ChangeBlocksReordered)
+	return nil
+}
+
+// UpdateExerciseOrder reorders the exercises of a block. Like blocks, every
+// exercise has to be listed once and orders are renumbered from 1.
+func (s *playlistService) UpdateExerciseOrder(ctx context.Context, playlistID, blockID int, userID uuid.UUID, exerciseOrders []ExerciseOrder) error {
+	if _, err := s.getPlaylistBlock(ctx, playlistID, blockID, userID); err != nil {
+		return err
+	}
+
+	exercises, err := s.playlistExerciseRepo.GetBlockExercises(ctx, blockID)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, len(exerciseOrders))
+	for i, order := range exerciseOrders {
+		ids[i] = order.ExerciseID
+	}
+	existing := make([]int, len(exercises))
+	for i, exercise := range exercises {
+		existing[i] = exercise.ID
+	}
+	if !sameIDs(ids, existing) {
+		return ErrInvalidOrder
+	}
+
+	sort.SliceStable(exerciseOrders, func(i, j int) bool {
+		return exerciseOrders[i].Order < exerciseOrders[j].Order
+	})
+	for i := range exerciseOrders {
+		exerciseOrders[i].Order = i + 1
+	}
+
+	s.snapshotBaseline(ctx, playlistID, userID)
+
+	if err := s.playlistExerciseRepo.UpdateExerciseOrders(ctx, blockID, exerciseOrders); err != nil {
+		return err
+	}
+
+	s.recordVersion(ctx, playlistID, userID, ChangeExercisesReordered)
+	return nil
+}
+
+// MoveExercise moves an exercise to a position in another block of the
+// same playlist, or to a new position in its own block
+func (s *playlistService) MoveExercise(ctx context.Context, exerciseID int, userID uuid.UUID, req MoveExerciseRequest) (PlaylistExercise, error) {
+	exercise, err := s.playlistExerciseRepo.GetByID(ctx, exerciseID)
+	if err != nil {
+		return PlaylistExercise{}, err
+	}
+
+	if exercise.ID == 0 {
+		return PlaylistExercise{}, ErrExerciseNotFound
+	}
+
+	target, err := s.getPlaylistBlock(ctx, exercise.PlaylistID, req.BlockID, userID)
+	if err != nil {
+		return PlaylistExercise{}, err
+	}
+
+	targetExercises, err := s.playlistExerciseRepo.GetBlockExercises(ctx, req.BlockID)
+	if err != nil {
+		return PlaylistExercise{}, err
+	}
+
+	if exercise.BlockID != req.BlockID {
+		target.Exercises = append(targetExercises, exercise)
+		if err := s.checkBlockRules(ctx, target); err != nil {
+			return PlaylistExercise{}, err
+		}
+	}
+
+	// Positions past the end append
+	size := len(targetExercises)
+	if exercise.BlockID != req.BlockID {
+		size++
+	}
+	position := size
+	if req.Position != nil {
+		position = max(1, min(*req.Position, size))
+	}
+
+	s.snapshotBaseline(ctx, exercise.PlaylistID, userID)
+
+	if err := s.playlistExerciseRepo.MoveExerciseToBlock(ctx, exerciseID, req.BlockID, position); err != nil {
+		return PlaylistExercise{}, err
+	}
+
+	s.recordVersion(ctx, exercise.PlaylistID, userID, ChangeExerciseMoved)
+	return s.playlistExerciseRepo.GetByID(ctx, exerciseID)
+}
+
+// getPlaylistBlock checks the user can edit the playlist and that the block
+// belongs to it
+func (s *playlistService) getPlaylistBlock(ctx context.Context, playlistID, blockID int, userID uuid.UUID) (Block, error) {
+	if err := s.ValidatePlaylistAccess(ctx, playlistID, userID); err != nil {
+		return Block{}, err
+	}
+
+	block, err := s.blockRepo.GetByID(ctx, blockID)
+	if err != nil {
+		return Block{}, err
+	}
+	if block.ID == 0 || block.PlaylistID != playlistID {
+		return Block{}, ErrBlockNotFound
+	}
+	return block, nil
+}
+
+// sameIDs reports whether ids holds exactly the existing IDs, each once
+func sameIDs(ids, existing []int) bool {
+	if len(ids) != len(existing) {
+		return false
+	}
+
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	for _, id := range existing {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
 }
 
-// GetAllTags returns all available tags
-func (s *playlistService) GetAllTags(ctx context.Context, userID uuid.UUID) ([]Tag, error) {
-	return s.playlistRepo.GetAllTags(ctx, userID)
+// GetAllTags returns a page of the available tags sorted by name
+func (s *playlistService) GetAllTags(ctx context.Context, userID uuid.UUID, page pagination.Request) (pagination.Page[Tag], error) {
+	var after *Tag
+	if page.Cursor != "" {
+		after = &Tag{}
+		if err := pagination.Decode(page.Cursor, &after.Name, &after.ID); err != nil {
+			return pagination.Page[Tag]{}, err
+		}
+	}
+
+	size := page.PageSize()
+	tags, err := s.playlistRepo.ListTags(ctx, after, size+1)
+	if err != nil {
+		return pagination.Page[Tag]{}, err
+	}
+	total, err := s.playlistRepo.CountTags(ctx)
+	if err != nil {
+		return pagination.Page[Tag]{}, err
+	}
+
+	return pagination.NewPage(tags, size, total, func(tag Tag) string {
+		return pagination.Encode(tag.Name, tag.ID)
+	}), nil
 }
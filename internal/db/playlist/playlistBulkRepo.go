@@ -0,0 +1,153 @@
+package playlist
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+// CreateTree inserts the playlist, its tags and its blocks in order. Every
+// exercise of a block gets its own config from its Config field. The
+// returned playlist holds the created rows with their IDs.
+func (r *playlistRepo) CreateTree(ctx context.Context, playlist Playlist, tagIDs []int) (Playlist, error) {
+	var created Playlist
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, createPlaylist,
+			playlist.UserID,
+			playlist.Title,
+			playlist.Description,
+			playlist.Visibility,
+			playlist.AssignedBy,
+		).Scan(
+			&created.ID,
+			&created.UserID,
+			&created.Title,
+			&created.Description,
+			&created.IsActive,
+			&created.LastWorked,
+			&created.Visibility,
+			&created.AssignedBy,
+			&created.SourcePlaylistID,
+			&created.CopyCount,
+			&created.CreatedAt,
+			&created.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, tagID := range tagIDs {
+			if _, err := tx.ExecContext(ctx, addTagsToPlaylist, created.ID, tagID); err != nil {
+				return err
+			}
+		}
+
+		created.Blocks = make([]Block, 0, len(playlist.Blocks))
+		for i, block := range playlist.Blocks {
+			createdBlock, err := createTreeBlock(ctx, tx, created.ID, i+1, block)
+			if err != nil {
+				return err
+			}
+			created.Blocks = append(created.Blocks, createdBlock)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Create playlist tree failed: %v", err)
+		return Playlist{}, err
+	}
+	return created, nil
+}
+
+// createTreeBlock inserts a block at blockOrder with its exercises
+func createTreeBlock(ctx context.Context, tx *sql.Tx, playlistID, blockOrder int, block Block) (Block, error) {
+	var created Block
+	err := tx.QueryRowContext(ctx, createBlock,
+		playlistID,
+		block.Name,
+		block.BlockType,
+		blockOrder,
+		block.RestAfterBlockSeconds,
+	).Scan(
+		&created.ID,
+		&created.PlaylistID,
+		&created.Name,
+		&created.BlockType,
+		&created.BlockOrder,
+		&created.RestAfterBlockSeconds,
+	)
+	if err != nil {
+		return Block{}, err
+	}
+
+	created.Exercises = make([]PlaylistExercise, 0, len(block.Exercises))
+	for i, exercise := range block.Exercises {
+		var config Config
+		if exercise.Config != nil {
+			config = *exercise.Config
+		}
+
+		var createdConfig Config
+		err := tx.QueryRowContext(ctx, createConfig,
+			config.Sets,
+			config.RepsMin,
+			config.RepsMax,
+			config.Weight,
+			config.RestSeconds,
+			pq.Array(config.Tempo),
+			config.DurationSeconds,
+			config.Distance,
+			config.TargetPace,
+			config.TargetHeartRate,
+			config.Incline,
+			config.Notes,
+		).Scan(
+			&createdConfig.ID,
+			&createdConfig.Sets,
+			&createdConfig.RepsMin,
+			&createdConfig.RepsMax,
+			&createdConfig.Weight,
+			&createdConfig.RestSeconds,
+			pq.Array(&createdConfig.Tempo),
+			&createdConfig.DurationSeconds,
+			&createdConfig.Distance,
+			&createdConfig.TargetPace,
+			&createdConfig.TargetHeartRate,
+			&createdConfig.Incline,
+			&createdConfig.Notes,
+			&createdConfig.CreatedAt,
+			&createdConfig.UpdatedAt,
+		)
+		if err != nil {
+			return Block{}, err
+		}
+
+		var createdExercise PlaylistExercise
+		err = tx.QueryRowContext(ctx, createPlaylistExercise,
+			playlistID,
+			exercise.ExerciseID,
+			created.ID,
+			createdConfig.ID,
+			i+1,
+		).Scan(
+			&createdExercise.ID,
+			&createdExercise.PlaylistID,
+			&createdExercise.ExerciseID,
+			&createdExercise.BlockID,
+			&createdExercise.ConfigID,
+			&createdExercise.ExerciseOrder,
+			&createdExercise.CreatedAt,
+			&createdExercise.UpdatedAt,
+		)
+		if err != nil {
+			return Block{}, err
+		}
+
+		createdExercise.ExerciseName = exercise.ExerciseName
+		createdExercise.Config = &createdConfig
+		created.Exercises = append(created.Exercises, createdExercise)
+	}
+	return created, nil
+}
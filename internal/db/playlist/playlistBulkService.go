@@ -0,0 +1,186 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// CreatePlaylistTree creates a playlist from a full document. Everything is
+// checked before the first write and the tree is then created in a single
+// transaction, so a bad exercise never leaves a half-built playlist behind.
+func (s *playlistService) CreatePlaylistTree(ctx context.Context, userID uuid.UUID, req BulkPlaylistRequest) (Playlist, error) {
+	if req.Visibility == "" {
+		req.Visibility = string(VisibilityPrivate)
+	}
+
+	if req.Title == "" || utf8.RuneCountInString(req.Title) > maxTitleLength {
+		return Playlist{}, fmt.Errorf("%w: title must be 1 to %d characters", ErrInvalidPlaylist, maxTitleLength)
+	}
+	switch Visibility(req.Visibility) {
+	case VisibilityPrivate, VisibilityPublic, VisibilityUnlisted:
+	default:
+		return Playlist{}, fmt.Errorf("%w: unknown visibility %q", ErrInvalidPlaylist, req.Visibility)
+	}
+
+	ownerID, assignedBy, err := s.playlistOwner(ctx, userID, req.CreatePlaylistRequest)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	tags, err := s.bulkTags(ctx, userID, req.TagIDs)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	blocks, err := s.bulkBlocks(ctx, req.Blocks)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	tagIDs := make([]int, len(tags))
+	for i, tag := range tags {
+		tagIDs[i] = tag.ID
+	}
+
+	created, err := s.playlistRepo.CreateTree(ctx, Playlist{
+		UserID:      ownerID,
+		Title:       req.Title,
+		Description: req.Description,
+		Visibility:  Visibility(req.Visibility),
+		AssignedBy:  assignedBy,
+		Blocks:      blocks,
+	}, tagIDs)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return Playlist{}, ErrPlaylistExists
+		}
+		return Playlist{}, fmt.Errorf("failed to create playlist: %w", err)
+	}
+	created.Tags = tags
+
+	s.recordVersion(ctx, created.ID, userID, ChangeCreated)
+	return created, nil
+}
+
+// bulkTags looks up the requested tags, dropping duplicates
+func (s *playlistService) bulkTags(ctx context.Context, userID uuid.UUID, tagIDs []int) ([]Tag, error) {
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+
+	allTags, err := s.playlistRepo.GetAllTags(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]Tag, len(allTags))
+	for _, tag := range allTags {
+		byID[tag.ID] = tag
+	}
+
+	tags := make([]Tag, 0, len(tagIDs))
+	seen := make(map[int]bool, len(tagIDs))
+	for _, id := range tagIDs {
+		tag, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown tag %d", ErrInvalidPlaylist, id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// bulkBlocks turns the requested blocks into blocks ready to insert,
+// checking names, types, exercises, configs and block rules on the way.
+// Without any blocks the playlist gets the same default block as
+// CreatePlaylist.
+func (s *playlistService) bulkBlocks(ctx context.Context, reqBlocks []BulkBlockRequest) ([]Block, error) {
+	if len(reqBlocks) == 0 {
+		return []Block{{
+			Name:                  "Playlist",
+			BlockType:             BlockTypePlaylist,
+			BlockOrder:            1,
+			RestAfterBlockSeconds: 60,
+		}}, nil
+	}
+
+	var exerciseIDs []int
+	for _, reqBlock := range reqBlocks {
+		for _, reqExercise := range reqBlock.Exercises {
+			if !slices.Contains(exerciseIDs, reqExercise.ExerciseID) {
+				exerciseIDs = append(exerciseIDs, reqExercise.ExerciseID)
+			}
+		}
+	}
+
+	names, err := s.playlistExerciseRepo.GetExerciseNames(ctx, exerciseIDs)
+	if err != nil {
+		return nil, err
+	}
+	trainingTypes, err := s.playlistExerciseRepo.GetTrainingTypes(ctx, exerciseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]Block, len(reqBlocks))
+	for i, reqBlock := range reqBlocks {
+		if reqBlock.Name == "" {
+			return nil, fmt.Errorf("blocks[%d]: %w", i, ErrInvalidBlockName)
+		}
+		if reqBlock.BlockType == "" {
+			reqBlock.BlockType = string(BlockTypePlaylist)
+		}
+		if !isValidBlockType(reqBlock.BlockType) {
+			return nil, fmt.Errorf("blocks[%d]: %w %q", i, ErrInvalidBlockType, reqBlock.BlockType)
+		}
+		rest := 60
+		if reqBlock.RestAfterBlockSeconds != nil {
+			rest = *reqBlock.RestAfterBlockSeconds
+		}
+		if rest < 0 {
+			return nil, fmt.Errorf("blocks[%d]: %w", i, ErrInvalidRest)
+		}
+
+		block := Block{
+			Name:                  reqBlock.Name,
+			BlockType:             BlockType(reqBlock.BlockType),
+			BlockOrder:            i + 1,
+			RestAfterBlockSeconds: rest,
+			Exercises:             make([]PlaylistExercise, len(reqBlock.Exercises)),
+		}
+
+		for j, reqExercise := range reqBlock.Exercises {
+			name, ok := names[reqExercise.ExerciseID]
+			if !ok {
+				return nil, fmt.Errorf("%w: blocks[%d].exercises[%d]: unknown exercise %d", ErrInvalidPlaylist, i, j, reqExercise.ExerciseID)
+			}
+
+			config := reqExercise.Config
+			if err := validateConfig(config); err != nil {
+				return nil, fmt.Errorf("blocks[%d].exercises[%d]: %w", i, j, err)
+			}
+			if hasCardioFields(config) && !slices.Contains(trainingTypes[reqExercise.ExerciseID], cardioTrainingType) {
+				return nil, fmt.Errorf("blocks[%d].exercises[%d]: %w: cardio fields can only be set on cardio exercises", i, j, ErrInvalidConfig)
+			}
+
+			block.Exercises[j] = PlaylistExercise{
+				ExerciseID:    reqExercise.ExerciseID,
+				ExerciseOrder: j + 1,
+				ExerciseName:  name,
+				Config:        &config,
+			}
+		}
+
+		if err := blockErrors(ValidateBlock(block, trainingTypes)); err != nil {
+			return nil, err
+		}
+		blocks[i] = block
+	}
+	return blocks, nil
+}
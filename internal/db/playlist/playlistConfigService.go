@@ -0,0 +1,226 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// UpdateConfig applies a partial update to the config of a playlist
+// exercise. The merged config is validated as a whole, and the update only
+// goes through if nobody changed the config since req.UpdatedAt.
+func (s *playlistService) UpdateConfig(ctx context.Context, exerciseID int, userID uuid.UUID, req UpdateConfigRequest) (Config, error) {
+	exercise, err := s.playlistExerciseRepo.GetByID(ctx, exerciseID)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if exercise.ID == 0 {
+		return Config{}, ErrExerciseNotFound
+	}
+
+	if err := s.ValidatePlaylistAccess(ctx, exercise.PlaylistID, userID); err != nil {
+		return Config{}, err
+	}
+
+	config, err := s.configRepo.GetByID(ctx, exercise.ConfigID)
+	if err != nil {
+		return Config{}, err
+	}
+	if config.ID == 0 {
+		return Config{}, ErrConfigNotFound
+	}
+	if !config.UpdatedAt.Equal(req.UpdatedAt) {
+		return Config{}, ErrConfigConflict
+	}
+
+	if err := mergeConfig(&config, req); err != nil {
+		return Config{}, err
+	}
+	if err := validateConfig(config); err != nil {
+		return Config{}, err
+	}
+
+	if hasCardioFields(config) {
+		trainingTypes, err := s.playlistExerciseRepo.GetTrainingTypes(ctx, []int{exercise.ExerciseID})
+		if err != nil {
+			return Config{}, err
+		}
+		if !slices.Contains(trainingTypes[exercise.ExerciseID], cardioTrainingType) {
+			return Config{}, fmt.Errorf("%w: cardio fields can only be set on cardio exercises", ErrInvalidConfig)
+		}
+	}
+
+	s.snapshotBaseline(ctx, exercise.PlaylistID, userID)
+
+	updatedConfig, err := s.configRepo.Update(ctx, config)
+	if err != nil {
+		return Config{}, err
+	}
+	if updatedConfig.ID == 0 {
+		return Config{}, ErrConfigConflict
+	}
+
+	s.recordVersion(ctx, exercise.PlaylistID, userID, ChangeConfigUpdated)
+	return updatedConfig, nil
+}
+
+// mergeConfig copies the fields set in req onto config and nulls the ones
+// listed in req.Clear
+func mergeConfig(config *Config, req UpdateConfigRequest) error {
+	for _, field := range req.Clear {
+		if fieldSet(req, field) {
+			return fmt.Errorf("%w: %s cannot be set and cleared at once", ErrInvalidConfig, field)
+		}
+
+		switch field {
+		case "sets":
+			config.Sets = nil
+		case "reps_min":
+			config.RepsMin = nil
+		case "reps_max":
+			config.RepsMax = nil
+		case "weight":
+			config.Weight = nil
+		case "tempo":
+			config.Tempo = nil
+		case "duration_seconds":
+			config.DurationSeconds = nil
+		case "distance":
+			config.Distance = nil
+		case "target_pace":
+			config.TargetPace = nil
+		case "target_heart_rate":
+			config.TargetHeartRate = nil
+		case "incline":
+			config.Incline = nil
+		case "notes":
+			config.Notes = nil
+		default:
+			return fmt.Errorf("%w: %q cannot be cleared", ErrInvalidConfig, field)
+		}
+	}
+
+	if req.Sets != nil {
+		config.Sets = req.Sets
+	}
+	if req.RepsMin != nil {
+		config.RepsMin = req.RepsMin
+	}
+	if req.RepsMax != nil {
+		config.RepsMax = req.RepsMax
+	}
+	if req.Weight != nil {
+		config.Weight = req.Weight
+	}
+	if req.RestSeconds != nil {
+		config.RestSeconds = *req.RestSeconds
+	}
+	if req.Tempo != nil {
+		config.Tempo = req.Tempo
+	}
+	if req.DurationSeconds != nil {
+		config.DurationSeconds = req.DurationSeconds
+	}
+	if req.Distance != nil {
+		config.Distance = req.Distance
+	}
+	if req.TargetPace != nil {
+		config.TargetPace = req.TargetPace
+	}
+	if req.TargetHeartRate != nil {
+		config.TargetHeartRate = req.TargetHeartRate
+	}
+	if req.Incline != nil {
+		config.Incline = req.Incline
+	}
+	if req.Notes != nil {
+		config.Notes = req.Notes
+	}
+	return nil
+}
+
+// fieldSet reports whether req sets the field with the given JSON name
+func fieldSet(req UpdateConfigRequest, field string) bool {
+	switch field {
+	case "sets":
+		return req.Sets != nil
+	case "reps_min":
+		return req.RepsMin != nil
+	case "reps_max":
+		return req.RepsMax != nil
+	case "weight":
+		return req.Weight != nil
+	case "tempo":
+		return req.Tempo != nil
+	case "duration_seconds":
+		return req.DurationSeconds != nil
+	case "distance":
+		return req.Distance != nil
+	case "target_pace":
+		return req.TargetPace != nil
+	case "target_heart_rate":
+		return req.TargetHeartRate != nil
+	case "incline":
+		return req.Incline != nil
+	case "notes":
+		return req.Notes != nil
+	}
+	return false
+}
+
+// validateConfig checks the values of a config after a merge
+func validateConfig(config Config) error {
+	if config.Sets != nil && *config.Sets < 1 {
+		return fmt.Errorf("%w: sets must be at least 1", ErrInvalidConfig)
+	}
+	if config.RepsMin != nil && *config.RepsMin < 1 {
+		return fmt.Errorf("%w: reps_min must be at least 1", ErrInvalidConfig)
+	}
+	if config.RepsMax != nil && *config.RepsMax < 1 {
+		return fmt.Errorf("%w: reps_max must be at least 1", ErrInvalidConfig)
+	}
+	if config.RepsMin != nil && config.RepsMax != nil && *config.RepsMin > *config.RepsMax {
+		return fmt.Errorf("%w: reps_min cannot be greater than reps_max", ErrInvalidConfig)
+	}
+	if config.Weight != nil && *config.Weight < 0 {
+		return fmt.Errorf("%w: weight cannot be negative", ErrInvalidConfig)
+	}
+	if config.RestSeconds < 0 {
+		return fmt.Errorf("%w: rest_seconds cannot be negative", ErrInvalidConfig)
+	}
+	if len(config.Tempo) > 0 {
+		if len(config.Tempo) != 4 {
+			return fmt.Errorf("%w: tempo must have exactly 4 values", ErrInvalidConfig)
+		}
+		for _, phase := range config.Tempo {
+			if phase < 0 {
+				return fmt.Errorf("%w: tempo values cannot be negative", ErrInvalidConfig)
+			}
+		}
+	}
+	if config.DurationSeconds != nil && *config.DurationSeconds < 1 {
+		return fmt.Errorf("%w: duration_seconds must be at least 1", ErrInvalidConfig)
+	}
+	if config.Distance != nil && *config.Distance <= 0 {
+		return fmt.Errorf("%w: distance must be positive", ErrInvalidConfig)
+	}
+	if config.TargetPace != nil && *config.TargetPace <= 0 {
+		return fmt.Errorf("%w: target_pace must be positive", ErrInvalidConfig)
+	}
+	if config.TargetHeartRate != nil && *config.TargetHeartRate < 1 {
+		return fmt.Errorf("%w: target_heart_rate must be at least 1", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// hasCardioFields reports whether any cardio-only field is set
+func hasCardioFields(config Config) bool {
+	return config.DurationSeconds != nil ||
+		config.Distance != nil ||
+		config.TargetPace != nil ||
+		config.TargetHeartRate != nil ||
+		config.Incline != nil
+}
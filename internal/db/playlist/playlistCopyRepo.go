@@ -0,0 +1,232 @@
+package playlist
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// maxTitleLength matches playlists.title
+const maxTitleLength = 100
+
+const getPlaylistTitle = `SELECT title FROM playlists WHERE id = $1`
+
+const titleTaken = `SELECT EXISTS (SELECT 1 FROM playlists WHERE user_id = $1 AND title = $2)`
+
+const copyPlaylist = `
+	INSERT INTO playlists (user_id, title, description, visibility, source_playlist_id)
+	SELECT $1, $2, description, 'private', id
+	FROM playlists
+	WHERE id = $3
+	RETURNING id, user_id, title, description, is_active, last_worked, visibility, assigned_by, source_playlist_id, copy_count, created_at, updated_at`
+
+const copyPlaylistTags = `
+	INSERT INTO playlist_tags (playlist_id, tag_id)
+	SELECT $1, tag_id FROM playlist_tags WHERE playlist_id = $2`
+
+const copyBlock = `
+	INSERT INTO exercise_blocks (playlist_id, name, block_type, block_order, rest_after_block_seconds)
+	SELECT $1, name, block_type, block_order, rest_after_block_seconds
+	FROM exercise_blocks
+	WHERE id = $2
+	RETURNING id`
+
+const getCopySourceBlocks = `SELECT id FROM exercise_blocks WHERE playlist_id = $1 ORDER BY block_order`
+
+const getCopySourceExercises = `
+	SELECT id, block_id
+	FROM playlist_exercises
+	WHERE playlist_id = $1
+	ORDER BY block_id, exercise_order`
+
+const copyExerciseConfig = `
+	INSERT INTO exercise_configs (sets, reps_min, reps_max, weight, rest_seconds, tempo,
+		duration_seconds, distance, target_pace, target_heart_rate, incline, notes)
+	SELECT c.sets, c.reps_min, c.reps_max, c.weight, c.rest_seconds, c.tempo,
+		c.duration_seconds, c.distance, c.target_pace, c.target_heart_rate, c.incline,
+		CASE WHEN $2 THEN c.notes END
+	FROM playlist_exercises pe
+	JOIN exercise_configs c ON pe.config_id = c.id
+	WHERE pe.id = $1
+	RETURNING id`
+
+const copyPlaylistExercise = `
+	INSERT INTO playlist_exercises (playlist_id, exercise_id, block_id, config_id, exercise_order)
+	SELECT $1, exercise_id, $2, $3, exercise_order
+	FROM playlist_exercises
+	WHERE id = $4`
+
+const incrementCopyCount = `UPDATE playlists SET copy_count = copy_count + 1 WHERE id = $1`
+
+// Copy clones the playlist with its tags, blocks, exercises and a new config
+// per exercise in one transaction. The title gets a " (copy)" suffix when
+// userID already has a playlist with that title. Config notes are only
+// copied with keepNotes.
+func (r *playlistRepo) Copy(ctx context.Context, sourceID int, userID uuid.UUID, keepNotes bool) (Playlist, error) {
+	var copied Playlist
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var title string
+		if err := tx.QueryRowContext(ctx, getPlaylistTitle, sourceID).Scan(&title); err != nil {
+			return err
+		}
+
+		title, err := freeCopyTitle(ctx, tx, userID, title)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, copyPlaylist, userID, title, sourceID).Scan(
+			&copied.ID,
+			&copied.UserID,
+			&copied.Title,
+			&copied.Description,
+			&copied.IsActive,
+			&copied.LastWorked,
+			&copied.Visibility,
+			&copied.AssignedBy,
+			&copied.SourcePlaylistID,
+			&copied.CopyCount,
+			&copied.CreatedAt,
+			&copied.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, copyPlaylistTags, copied.ID, sourceID); err != nil {
+			return err
+		}
+
+		blockIDs, err := copyBlocks(ctx, tx, sourceID, copied.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := copyExercises(ctx, tx, sourceID, copied.ID, blockIDs, keepNotes); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, incrementCopyCount, sourceID)
+		return err
+	})
+	if err != nil {
+		log.Printf("Copy playlist %d for user %s failed: %v", sourceID, userID, err)
+		return Playlist{}, err
+	}
+	return copied, nil
+}
+
+// copyBlocks copies the blocks of the source playlist and maps their IDs
+// to the new ones
+func copyBlocks(ctx context.Context, tx *sql.Tx, sourceID, playlistID int) (map[int]int, error) {
+	rows, err := tx.QueryContext(ctx, getCopySourceBlocks, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceBlockIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sourceBlockIDs = append(sourceBlockIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	blockIDs := make(map[int]int, len(sourceBlockIDs))
+	for _, sourceBlockID := range sourceBlockIDs {
+		var id int
+		if err := tx.QueryRowContext(ctx, copyBlock, playlistID, sourceBlockID).Scan(&id); err != nil {
+			return nil, err
+		}
+		blockIDs[sourceBlockID] = id
+	}
+	return blockIDs, nil
+}
+
+// copyExercises copies every exercise into its new block with its own
+// config, so editing the copy never changes the source
+func copyExercises(ctx context.Context, tx *sql.Tx, sourceID, playlistID int, blockIDs map[int]int, keepNotes bool) error {
+	rows, err := tx.QueryContext(ctx, getCopySourceExercises, sourceID)
+	if err != nil {
+		return err
+	}
+
+	type sourceExercise struct {
+		id      int
+		blockID int
+	}
+	var exercises []sourceExercise
+	for rows.Next() {
+		var exercise sourceExercise
+		if err := rows.Scan(&exercise.id, &exercise.blockID); err != nil {
+			rows.Close()
+			return err
+		}
+		exercises = append(exercises, exercise)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, exercise := range exercises {
+		blockID, ok := blockIDs[exercise.blockID]
+		if !ok {
+			return fmt.Errorf("block %d of exercise %d not copied", exercise.blockID, exercise.id)
+		}
+
+		var configID int
+		if err := tx.QueryRowContext(ctx, copyExerciseConfig, exercise.id, keepNotes).Scan(&configID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, copyPlaylistExercise, playlistID, blockID, configID, exercise.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// freeCopyTitle returns title, or the first of "title (copy)",
+// "title (copy 2)", ... that userID does not use yet
+func freeCopyTitle(ctx context.Context, tx *sql.Tx, userID uuid.UUID, title string) (string, error) {
+	for n := 0; ; n++ {
+		candidate := copyTitle(title, n)
+
+		var taken bool
+		if err := tx.QueryRowContext(ctx, titleTaken, userID, candidate).Scan(&taken); err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+}
+
+func copyTitle(title string, n int) string {
+	var suffix string
+	switch {
+	case n == 0:
+		return title
+	case n == 1:
+		suffix = " (copy)"
+	default:
+		suffix = fmt.Sprintf(" (copy %d)", n)
+	}
+
+	// Trim the title, not the suffix, to fit the column
+	runes := []rune(title)
+	if limit := maxTitleLength - len([]rune(suffix)); len(runes) > limit {
+		runes = runes[:limit]
+	}
+	return string(runes) + suffix
+}
@@ -0,0 +1,70 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
+)
+
+// DiscoverPlaylists lists public playlists of every user
+func (s *playlistService) DiscoverPlaylists(ctx context.Context, req DiscoverPlaylistsRequest, page pagination.Request) (pagination.Page[PublicPlaylist], error) {
+	switch req.Sort {
+	case "":
+		req.Sort = SortNewest
+	case SortNewest, SortMostCopied:
+	default:
+		return pagination.Page[PublicPlaylist]{}, fmt.Errorf("%w: unknown sort %q", ErrInvalidFilter, req.Sort)
+	}
+
+	for _, blockType := range req.BlockTypes {
+		if !blockType.IsValid() {
+			return pagination.Page[PublicPlaylist]{}, fmt.Errorf("%w: unknown block type %q", ErrInvalidFilter, blockType)
+		}
+	}
+
+	// Cursors hold the sort keys of the last playlist, so they only work
+	// with the sort they were made for
+	var after *PublicPlaylist
+	if page.Cursor != "" {
+		after = &PublicPlaylist{}
+		var err error
+		if req.Sort == SortMostCopied {
+			err = pagination.Decode(page.Cursor, &after.CopyCount, &after.CreatedAt, &after.ID)
+		} else {
+			err = pagination.Decode(page.Cursor, &after.CreatedAt, &after.ID)
+		}
+		if err != nil {
+			return pagination.Page[PublicPlaylist]{}, err
+		}
+	}
+
+	size := page.PageSize()
+	playlists, err := s.playlistRepo.DiscoverPublic(ctx, req, after, size+1)
+	if err != nil {
+		return pagination.Page[PublicPlaylist]{}, err
+	}
+	total, err := s.playlistRepo.CountPublic(ctx, req)
+	if err != nil {
+		return pagination.Page[PublicPlaylist]{}, err
+	}
+
+	result := pagination.NewPage(playlists, size, total, func(playlist PublicPlaylist) string {
+		if req.Sort == SortMostCopied {
+			return pagination.Encode(playlist.CopyCount, playlist.CreatedAt, playlist.ID)
+		}
+		return pagination.Encode(playlist.CreatedAt, playlist.ID)
+	})
+
+	for i := range result.Items {
+		tags, err := s.playlistRepo.GetPlaylistTags(ctx, result.Items[i].ID)
+		if err != nil {
+			log.Printf("Failed to get tags for playlist %d: %v", result.Items[i].ID, err)
+			continue
+		}
+		result.Items[i].Tags = tags
+	}
+
+	return result, nil
+}
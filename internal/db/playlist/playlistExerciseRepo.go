@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"log"
 
+	"github.com/lib/pq"
+
 	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
 )
 
 type PlaylistExerciseRepo interface {
+	// Create appends the exercise to its block when ExerciseOrder is 0
 	Create(ctx context.Context, playlistExercise PlaylistExercise) (PlaylistExercise, error)
 	GetByID(ctx context.Context, id int) (PlaylistExercise, error)
 	GetBlockExercises(ctx context.Context, blockID int) ([]PlaylistExercise, error)
@@ -16,10 +19,20 @@ type PlaylistExerciseRepo interface {
 	Update(ctx context.Context, playlistExercise PlaylistExercise) (PlaylistExercise, error)
 	Delete(ctx context.Context, id int) error
 
+	// Deletes an exercise and closes the gap in its block's order
+	DeleteAndNormalize(ctx context.Context, id int) error
+
 	// Reorder exercises within a block
 	UpdateExerciseOrders(ctx context.Context, blockID int, exerciseOrders []ExerciseOrder) error
 
-	// Move exercise to different block
+	// Training type names of catalog exercises, keyed by exercise ID
+	GetTrainingTypes(ctx context.Context, exerciseIDs []int) (map[int][]string, error)
+
+	// Names of catalog exercises, keyed by exercise ID. Unknown IDs are left out.
+	GetExerciseNames(ctx context.Context, exerciseIDs []int) (map[int]string, error)
+
+	// Move exercise to position newOrder of a block, shifting the exercises
+	// after it. Orders of both blocks stay gap-free.
 	MoveExerciseToBlock(ctx context.Context, exerciseID int, newBlockID int, newOrder int) error
 }
 
@@ -43,9 +56,22 @@ const createPlaylistExercise = `
 	VALUES ($1, $2, $3, $4, $5)
 	RETURNING id, playlist_id, exercise_id, block_id, config_id, exercise_order, created_at, updated_at`
 
+// Locks the block so concurrent appends get different orders
+const nextExerciseOrder = `
+	SELECT COALESCE(MAX(pe.exercise_order), 0) + 1
+	FROM (SELECT id FROM exercise_blocks WHERE id = $1 FOR UPDATE) b
+	LEFT JOIN playlist_exercises pe ON pe.block_id = b.id`
+
 func (r *playlistExerciseRepo) Create(ctx context.Context, playlistExercise PlaylistExercise) (PlaylistExercise, error) {
 	var newPlaylistExercise PlaylistExercise
 	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if playlistExercise.ExerciseOrder == 0 {
+			err := tx.QueryRowContext(ctx, nextExerciseOrder, playlistExercise.BlockID).Scan(&playlistExercise.ExerciseOrder)
+			if err != nil {
+				return err
+			}
+		}
+
 		return tx.QueryRowContext(ctx, createPlaylistExercise,
 			playlistExercise.PlaylistID,
 			playlistExercise.ExerciseID,
@@ -219,6 +245,26 @@ func (r *playlistExerciseRepo) Delete(ctx context.Context, id int) error {
 	})
 }
 
+func (r *playlistExerciseRepo) DeleteAndNormalize(ctx context.Context, id int) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var blockID int
+		if err := tx.QueryRowContext(ctx, getExerciseBlockID, id).Scan(&blockID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, deletePlaylistExercise, id); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, normalizeExerciseOrders, blockID, id)
+		return err
+	})
+	if err != nil {
+		log.Printf("Delete playlist exercise %d failed: %v", id, err)
+	}
+	return err
+}
+
 const updateExerciseOrder = `UPDATE playlist_exercises SET exercise_order = $2 WHERE id = $1 AND block_id = $3`
 
 func (r *playlistExerciseRepo) UpdateExerciseOrders(ctx context.Context, blockID int, exerciseOrders []ExerciseOrder) error {
@@ -233,14 +279,112 @@ func (r *playlistExerciseRepo) UpdateExerciseOrders(ctx context.Context, blockID
 	})
 }
 
+const getExerciseBlockID = `SELECT block_id FROM playlist_exercises WHERE id = $1`
+
 const moveExerciseToBlock = `
 	UPDATE playlist_exercises 
 	SET block_id = $2, exercise_order = $3, updated_at = NOW() 
 	WHERE id = $1`
 
+// Renumbers a block from 1, leaving out exercise $2
+const normalizeExerciseOrders = `
+	UPDATE playlist_exercises pe
+	SET exercise_order = o.n
+	FROM (
+		SELECT id, ROW_NUMBER() OVER (ORDER BY exercise_order, id) AS n
+		FROM playlist_exercises
+		WHERE block_id = $1 AND id <> $2
+	) o
+	WHERE pe.id = o.id AND pe.exercise_order <> o.n`
+
+const makeRoomForExercise = `
+	UPDATE playlist_exercises
+	SET exercise_order = exercise_order + 1
+	WHERE block_id = $1 AND exercise_order >= $2 AND id <> $3`
+
 func (r *playlistExerciseRepo) MoveExerciseToBlock(ctx context.Context, exerciseID int, newBlockID int, newOrder int) error {
-	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var oldBlockID int
+		if err := tx.QueryRowContext(ctx, getExerciseBlockID, exerciseID).Scan(&oldBlockID); err != nil {
+			return err
+		}
+
+		// Close the gap it leaves, then open one where it goes
+		if _, err := tx.ExecContext(ctx, normalizeExerciseOrders, oldBlockID, exerciseID); err != nil {
+			return err
+		}
+		if oldBlockID != newBlockID {
+			if _, err := tx.ExecContext(ctx, normalizeExerciseOrders, newBlockID, exerciseID); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, makeRoomForExercise, newBlockID, newOrder, exerciseID); err != nil {
+			return err
+		}
+
 		_, err := tx.ExecContext(ctx, moveExerciseToBlock, exerciseID, newBlockID, newOrder)
 		return err
 	})
+	if err != nil {
+		log.Printf("Move playlist exercise %d to block %d failed: %v", exerciseID, newBlockID, err)
+	}
+	return err
+}
+
+const getTrainingTypes = `
+	SELECT ett.exercise_id, tt.name
+	FROM exercise_training_types ett
+	JOIN training_types tt ON ett.type_id = tt.id
+	WHERE ett.exercise_id = ANY($1)`
+
+func (r *playlistExerciseRepo) GetTrainingTypes(ctx context.Context, exerciseIDs []int) (map[int][]string, error) {
+	types := make(map[int][]string)
+	if len(exerciseIDs) == 0 {
+		return types, nil
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, getTrainingTypes, pq.Array(exerciseIDs))
+	if err != nil {
+		log.Printf("Get training types failed: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var exerciseID int
+		var name string
+		if err := rows.Scan(&exerciseID, &name); err != nil {
+			return nil, err
+		}
+		types[exerciseID] = append(types[exerciseID], normalizeTrainingType(name))
+	}
+
+	return types, rows.Err()
+}
+
+const getExerciseNames = `SELECT id, name FROM exercises WHERE id = ANY($1)`
+
+func (r *playlistExerciseRepo) GetExerciseNames(ctx context.Context, exerciseIDs []int) (map[int]string, error) {
+	names := make(map[int]string)
+	if len(exerciseIDs) == 0 {
+		return names, nil
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, getExerciseNames, pq.Array(exerciseIDs))
+	if err != nil {
+		log.Printf("Get exercise names failed: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		names[id] = name
+	}
+
+	return names, rows.Err()
 }
@@ -15,6 +15,8 @@ func (s *playlistService) AddExerciseToPlaylist(ctx context.Context, playlistID
 		return PlaylistExercise{}, err
 	}
 
+	s.snapshotBaseline(ctx, playlistID, userID)
+
 	var blockID int
 
 	// Handle block - create new or use existing
@@ -25,6 +27,21 @@ func (s *playlistService) AddExerciseToPlaylist(ctx context.Context, playlistID
 		if err != nil || block.PlaylistID != playlistID {
 			return PlaylistExercise{}, ErrBlockNotFound
 		}
+
+		// The block has to follow its type's rules with the new exercise
+		block.Exercises, err = s.playlistExerciseRepo.GetBlockExercises(ctx, blockID)
+		if err != nil {
+			return PlaylistExercise{}, err
+		}
+		config := req.Config
+		block.Exercises = append(block.Exercises, PlaylistExercise{
+			ExerciseID:    req.ExerciseID,
+			ExerciseOrder: len(block.Exercises) + 1,
+			Config:        &config,
+		})
+		if err := s.checkBlockRules(ctx, block); err != nil {
+			return PlaylistExercise{}, err
+		}
 	} else {
 		// Create new block
 		blockName := "New Block"
@@ -59,22 +76,21 @@ func (s *playlistService) AddExerciseToPlaylist(ctx context.Context, playlistID
 		return PlaylistExercise{}, fmt.Errorf("failed to create config: %w", err)
 	}
 
-	// Get next exercise order within block
-	blockExercises, err := s.playlistExerciseRepo.GetBlockExercises(ctx, blockID)
-	if err != nil {
-		return PlaylistExercise{}, err
+	// Create playlist exercise, appended after the last one of the block
+	playlistExercise := PlaylistExercise{
+		PlaylistID: playlistID,
+		ExerciseID: req.ExerciseID,
+		BlockID:    blockID,
+		ConfigID:   config.ID,
 	}
 
-	// Create playlist exercise
-	playlistExercise := PlaylistExercise{
-		PlaylistID:    playlistID,
-		ExerciseID:    req.ExerciseID,
-		BlockID:       blockID,
-		ConfigID:      config.ID,
-		ExerciseOrder: len(blockExercises) + 1,
+	createdExercise, err := s.playlistExerciseRepo.Create(ctx, playlistExercise)
+	if err != nil {
+		return PlaylistExercise{}, err
 	}
 
-	return s.playlistExerciseRepo.Create(ctx, playlistExercise)
+	s.recordVersion(ctx, playlistID, userID, ChangeExerciseAdded)
+	return createdExercise, nil
 }
 
 // RemoveExerciseFromPlaylist removes an exercise from playlist
@@ -94,29 +110,14 @@ func (s *playlistService) RemoveExerciseFromPlaylist(ctx context.Context, exerci
 		return err
 	}
 
-	return s.playlistExerciseRepo.Delete(ctx, exerciseID)
-}
-
-// UpdateConfig updates exercise configuration
-func (s *playlistService) UpdateConfig(ctx context.Context, exerciseID int, userID uuid.UUID, config Config) (Config, error) {
-	// Get exercise to validate access
-	exercise, err := s.playlistExerciseRepo.GetByID(ctx, exerciseID)
-	if err != nil {
-		return Config{}, err
-	}
+	s.snapshotBaseline(ctx, exercise.PlaylistID, userID)
 
-	if exercise.ID == 0 {
-		return Config{}, errors.New("exercise not found")
-	}
-
-	// Validate playlist access
-	if err := s.ValidatePlaylistAccess(ctx, exercise.PlaylistID, userID); err != nil {
-		return Config{}, err
+	if err := s.playlistExerciseRepo.DeleteAndNormalize(ctx, exerciseID); err != nil {
+		return err
 	}
 
-	// Update config
-	config.ID = exercise.ConfigID
-	return s.configRepo.Update(ctx, config)
+	s.recordVersion(ctx, exercise.PlaylistID, userID, ChangeExerciseRemoved)
+	return nil
 }
 
 // GetPlaylistForSession returns complete playlist data for starting a workout
@@ -3,9 +3,13 @@ package playlist
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
 )
@@ -14,15 +18,33 @@ type PlaylistRepo interface {
 	// Playlist CRUD
 	Create(ctx context.Context, playlist Playlist) (Playlist, error)
 	GetByID(ctx context.Context, id int) (Playlist, error)
-	GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]Playlist, error)
+	// GetUserPlaylists and DiscoverPublic return the page after the given
+	// playlist, or the first page when it is nil
+	GetUserPlaylists(ctx context.Context, userID uuid.UUID, after *Playlist, limit int) ([]Playlist, error)
+	CountUserPlaylists(ctx context.Context, userID uuid.UUID) (int, error)
+	DiscoverPublic(ctx context.Context, filter DiscoverPlaylistsRequest, after *PublicPlaylist, limit int) ([]PublicPlaylist, error)
+	CountPublic(ctx context.Context, filter DiscoverPlaylistsRequest) (int, error)
 	Update(ctx context.Context, playlist Playlist) (Playlist, error)
 	Delete(ctx context.Context, id int) error
 
+	// Deep-copies a playlist into userID's account as a private playlist
+	Copy(ctx context.Context, sourceID int, userID uuid.UUID, keepNotes bool) (Playlist, error)
+
+	// Creates a playlist with its tags, blocks, exercises and their configs
+	// in one transaction
+	CreateTree(ctx context.Context, playlist Playlist, tagIDs []int) (Playlist, error)
+
+	// Replaces the details, tags, blocks and exercises of a playlist with
+	// those of a snapshot
+	Restore(ctx context.Context, snapshot Playlist) error
+
 	// Playlist with details
 	GetPlaylistWithBlocks(ctx context.Context, id int) (Playlist, error)
 
 	// Tag operations
 	GetAllTags(ctx context.Context, userID uuid.UUID) ([]Tag, error)
+	ListTags(ctx context.Context, after *Tag, limit int) ([]Tag, error)
+	CountTags(ctx context.Context) (int, error)
 	CreateTag(ctx context.Context, name string) (Tag, error)
 	AddTagsToPlaylist(ctx context.Context, playlistID int, tagIDs []int) error
 	RemoveTagsFromPlaylist(ctx context.Context, playlistID int, tagIDs []int) error
@@ -42,9 +64,9 @@ func NewPlaylistRepo(db *sql.DB) PlaylistRepo {
 // Playlist CRUD Operations
 
 const createPlaylist = `
-	INSERT INTO playlists (user_id, title, description, visibility)
-	VALUES ($1, $2, $3, $4)
-	RETURNING id, user_id, title, description, is_active, last_worked, visibility, created_at, updated_at`
+	INSERT INTO playlists (user_id, title, description, visibility, assigned_by)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, user_id, title, description, is_active, last_worked, visibility, assigned_by, source_playlist_id, copy_count, created_at, updated_at`
 
 func (r *playlistRepo) Create(ctx context.Context, playlist Playlist) (Playlist, error) {
 	var newPlaylist Playlist
@@ -54,6 +76,7 @@ func (r *playlistRepo) Create(ctx context.Context, playlist Playlist) (Playlist,
 			playlist.Title,
 			playlist.Description,
 			playlist.Visibility,
+			playlist.AssignedBy,
 		).Scan(
 			&newPlaylist.ID,
 			&newPlaylist.UserID,
@@ -62,6 +85,9 @@ func (r *playlistRepo) Create(ctx context.Context, playlist Playlist) (Playlist,
 			&newPlaylist.IsActive,
 			&newPlaylist.LastWorked,
 			&newPlaylist.Visibility,
+			&newPlaylist.AssignedBy,
+			&newPlaylist.SourcePlaylistID,
+			&newPlaylist.CopyCount,
 			&newPlaylist.CreatedAt,
 			&newPlaylist.UpdatedAt,
 		)
@@ -75,7 +101,7 @@ func (r *playlistRepo) Create(ctx context.Context, playlist Playlist) (Playlist,
 
 const getPlaylistByID = `
 	SELECT p.id, p.user_id, p.title, p.description, p.is_active, p.last_worked, 
-		   p.visibility, p.created_at, p.updated_at
+		   p.visibility, p.assigned_by, p.source_playlist_id, p.copy_count, p.created_at, p.updated_at
 	FROM playlists p
 	WHERE p.id = $1`
 
@@ -90,6 +116,9 @@ func (r *playlistRepo) GetByID(ctx context.Context, id int) (Playlist, error) {
 		&playlist.IsActive,
 		&playlist.LastWorked,
 		&playlist.Visibility,
+		&playlist.AssignedBy,
+		&playlist.SourcePlaylistID,
+		&playlist.CopyCount,
 		&playlist.CreatedAt,
 		&playlist.UpdatedAt,
 	)
@@ -105,13 +134,21 @@ func (r *playlistRepo) GetByID(ctx context.Context, id int) (Playlist, error) {
 
 const getUserPlaylists = `
 	SELECT p.id, p.user_id, p.title, p.description, p.is_active, p.last_worked, 
-		   p.visibility, p.created_at, p.updated_at
+		   p.visibility, p.assigned_by, p.source_playlist_id, p.copy_count, p.created_at, p.updated_at
 	FROM playlists p
 	WHERE p.user_id = $1
-	ORDER BY p.updated_at DESC`
+		AND ($2::timestamp IS NULL OR (p.updated_at, p.id) < ($2, $3))
+	ORDER BY p.updated_at DESC, p.id DESC
+	LIMIT $4`
 
-func (r *playlistRepo) GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]Playlist, error) {
-	rows, err := r.tx.DB().QueryContext(ctx, getUserPlaylists, userID)
+func (r *playlistRepo) GetUserPlaylists(ctx context.Context, userID uuid.UUID, after *Playlist, limit int) ([]Playlist, error) {
+	var afterUpdatedAt *time.Time
+	var afterID int
+	if after != nil {
+		afterUpdatedAt, afterID = &after.UpdatedAt, after.ID
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, getUserPlaylists, userID, afterUpdatedAt, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -129,6 +166,9 @@ func (r *playlistRepo) GetUserPlaylists(ctx context.Context, userID uuid.UUID) (
 			&playlist.IsActive,
 			&playlist.LastWorked,
 			&playlist.Visibility,
+			&playlist.AssignedBy,
+			&playlist.SourcePlaylistID,
+			&playlist.CopyCount,
 			&playlist.CreatedAt,
 			&playlist.UpdatedAt,
 		)
@@ -142,6 +182,155 @@ func (r *playlistRepo) GetUserPlaylists(ctx context.Context, userID uuid.UUID) (
 	return playlists, rows.Err()
 }
 
+const countUserPlaylists = `SELECT COUNT(*) FROM playlists WHERE user_id = $1`
+
+func (r *playlistRepo) CountUserPlaylists(ctx context.Context, userID uuid.UUID) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countUserPlaylists, userID).Scan(&total)
+	return total, err
+}
+
+// Estimated seconds of one playlist exercise: the cardio duration, or per
+// set the average reps at the tempo (3s a rep without one) plus rest
+const estimatedExerciseSeconds = `
+	CASE WHEN c.duration_seconds IS NOT NULL THEN c.duration_seconds
+	ELSE COALESCE(c.sets, 1) * (
+		COALESCE((c.reps_min + c.reps_max) / 2.0, c.reps_min, c.reps_max, 10)
+			* COALESCE((SELECT SUM(t) FROM unnest(c.tempo) t), 3)
+		+ COALESCE(c.rest_seconds, 0))
+	END`
+
+const discoverPlaylists = `
+	SELECT p.id, p.title, p.description, p.created_at, p.updated_at, p.copy_count,
+		   u.username, TRIM(u.first_name || ' ' || u.last_name),
+		   COALESCE(ex.total, 0), COALESCE(bl.total, 0),
+		   ROUND(COALESCE(ex.seconds, 0) + COALESCE(bl.rest_seconds, 0))::INT
+	FROM playlists p
+	JOIN users u ON u.id = p.user_id
+	LEFT JOIN LATERAL (
+		SELECT COUNT(*) AS total, SUM(` + estimatedExerciseSeconds + `) AS seconds
+		FROM playlist_exercises pe
+		JOIN exercise_configs c ON c.id = pe.config_id
+		WHERE pe.playlist_id = p.id
+	) ex ON TRUE
+	LEFT JOIN LATERAL (
+		SELECT COUNT(*) AS total, SUM(COALESCE(b.rest_after_block_seconds, 0)) AS rest_seconds
+		FROM exercise_blocks b
+		WHERE b.playlist_id = p.id
+	) bl ON TRUE
+	WHERE %s
+	ORDER BY %s
+	LIMIT %s`
+
+// discoverConditions builds the conditions of filter on playlists p
+func discoverConditions(filter DiscoverPlaylistsRequest) ([]string, []any) {
+	conditions := []string{"p.visibility = 'public'"}
+	var args []any
+	where := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if len(filter.TagIDs) > 0 {
+		where(`EXISTS (SELECT 1 FROM playlist_tags pt WHERE pt.playlist_id = p.id AND pt.tag_id = ANY(%s))`, pq.Array(filter.TagIDs))
+	}
+	if len(filter.BlockTypes) > 0 {
+		blockTypes := make([]string, len(filter.BlockTypes))
+		for i, blockType := range filter.BlockTypes {
+			blockTypes[i] = string(blockType)
+		}
+		where(`EXISTS (SELECT 1 FROM exercise_blocks b WHERE b.playlist_id = p.id AND b.block_type = ANY(%s))`, pq.Array(blockTypes))
+	}
+	if len(filter.EquipmentIDs) > 0 {
+		where(`EXISTS (
+			SELECT 1 FROM playlist_exercises pe
+			JOIN exercises e ON e.id = pe.exercise_id
+			WHERE pe.playlist_id = p.id AND e.equipment_id = ANY(%s))`, pq.Array(filter.EquipmentIDs))
+	}
+	if len(filter.MuscleGroupIDs) > 0 {
+		where(`EXISTS (
+			SELECT 1 FROM playlist_exercises pe
+			JOIN exercise_muscles em ON em.exercise_id = pe.exercise_id
+			WHERE pe.playlist_id = p.id AND em.muscle_group_id = ANY(%s))`, pq.Array(filter.MuscleGroupIDs))
+	}
+
+	return conditions, args
+}
+
+func (r *playlistRepo) DiscoverPublic(ctx context.Context, filter DiscoverPlaylistsRequest, after *PublicPlaylist, limit int) ([]PublicPlaylist, error) {
+	conditions, args := discoverConditions(filter)
+	placeholder := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	orderBy := "p.created_at DESC, p.id DESC"
+	if filter.Sort == SortMostCopied {
+		orderBy = "p.copy_count DESC, " + orderBy
+		if after != nil {
+			conditions = append(conditions, fmt.Sprintf("(p.copy_count, p.created_at, p.id) < (%s, %s, %s)",
+				placeholder(after.CopyCount), placeholder(after.CreatedAt), placeholder(after.ID)))
+		}
+	} else if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(p.created_at, p.id) < (%s, %s)",
+			placeholder(after.CreatedAt), placeholder(after.ID)))
+	}
+
+	query := fmt.Sprintf(discoverPlaylists,
+		strings.Join(conditions, " AND "),
+		orderBy,
+		placeholder(limit),
+	)
+
+	rows, err := r.tx.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("Discover playlists failed: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var playlists []PublicPlaylist
+	for rows.Next() {
+		var playlist PublicPlaylist
+		err := rows.Scan(
+			&playlist.ID,
+			&playlist.Title,
+			&playlist.Description,
+			&playlist.CreatedAt,
+			&playlist.UpdatedAt,
+			&playlist.CopyCount,
+			&playlist.Author.Username,
+			&playlist.Author.DisplayName,
+			&playlist.TotalExercises,
+			&playlist.TotalBlocks,
+			&playlist.EstimatedDurationSeconds,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		playlists = append(playlists, playlist)
+	}
+
+	return playlists, rows.Err()
+}
+
+func (r *playlistRepo) CountPublic(ctx context.Context, filter DiscoverPlaylistsRequest) (int, error) {
+	conditions, args := discoverConditions(filter)
+	query := "SELECT COUNT(*) FROM playlists p WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.tx.DB().QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		log.Printf("Count public playlists failed: %v", err)
+		return 0, err
+	}
+	return total, nil
+}
+
 const updatePlaylist = `
 	UPDATE playlists 
 	SET title = COALESCE(NULLIF($2, ''), title),
@@ -149,7 +338,7 @@ const updatePlaylist = `
 		visibility = COALESCE(NULLIF($4, ''), visibility),
 		updated_at = NOW()
 	WHERE id = $1 AND user_id = $5
-	RETURNING id, user_id, title, description, is_active, last_worked, visibility, created_at, updated_at`
+	RETURNING id, user_id, title, description, is_active, last_worked, visibility, assigned_by, source_playlist_id, copy_count, created_at, updated_at`
 
 func (r *playlistRepo) Update(ctx context.Context, playlist Playlist) (Playlist, error) {
 	var updatedPlaylist Playlist
@@ -168,6 +357,9 @@ func (r *playlistRepo) Update(ctx context.Context, playlist Playlist) (Playlist,
 			&updatedPlaylist.IsActive,
 			&updatedPlaylist.LastWorked,
 			&updatedPlaylist.Visibility,
+			&updatedPlaylist.AssignedBy,
+			&updatedPlaylist.SourcePlaylistID,
+			&updatedPlaylist.CopyCount,
 			&updatedPlaylist.CreatedAt,
 			&updatedPlaylist.UpdatedAt,
 		)
@@ -188,8 +380,108 @@ func (r *playlistRepo) Delete(ctx context.Context, id int) error {
 	})
 }
 
-// GetPlaylistWithBlocks - placeholder for now, will implement after block repo
+const getPlaylistTreeBlocks = `
+	SELECT id, playlist_id, name, block_type, block_order, rest_after_block_seconds
+	FROM exercise_blocks
+	WHERE playlist_id = $1
+	ORDER BY block_order ASC`
+
+const getPlaylistTreeExercises = `
+	SELECT pe.id, pe.playlist_id, pe.exercise_id, pe.block_id, pe.config_id,
+		   pe.exercise_order, pe.created_at, pe.updated_at, e.name,
+		   c.id, c.sets, c.reps_min, c.reps_max, c.weight, c.rest_seconds, c.tempo,
+		   c.duration_seconds, c.distance, c.target_pace, c.target_heart_rate, c.incline,
+		   c.notes, c.created_at, c.updated_at
+	FROM playlist_exercises pe
+	JOIN exercises e ON pe.exercise_id = e.id
+	JOIN exercise_configs c ON pe.config_id = c.id
+	WHERE pe.playlist_id = $1
+	ORDER BY pe.block_id, pe.exercise_order ASC`
+
+// GetPlaylistWithBlocks returns the playlist with its blocks in order and
+// the exercises and configs of each block. Returns an empty playlist if
+// it does not exist.
 func (r *playlistRepo) GetPlaylistWithBlocks(ctx context.Context, id int) (Playlist, error) {
-	// This will be implemented once we have block repo
-	return r.GetByID(ctx, id)
+	playlist, err := r.GetByID(ctx, id)
+	if err != nil || playlist.ID == 0 {
+		return playlist, err
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, getPlaylistTreeBlocks, id)
+	if err != nil {
+		log.Printf("Get blocks failed for playlist %d: %v", id, err)
+		return Playlist{}, err
+	}
+	defer rows.Close()
+
+	blockIndex := make(map[int]int)
+	for rows.Next() {
+		var block Block
+		err := rows.Scan(
+			&block.ID,
+			&block.PlaylistID,
+			&block.Name,
+			&block.BlockType,
+			&block.BlockOrder,
+			&block.RestAfterBlockSeconds,
+		)
+		if err != nil {
+			return Playlist{}, err
+		}
+		blockIndex[block.ID] = len(playlist.Blocks)
+		playlist.Blocks = append(playlist.Blocks, block)
+	}
+	if err := rows.Err(); err != nil {
+		return Playlist{}, err
+	}
+
+	exerciseRows, err := r.tx.DB().QueryContext(ctx, getPlaylistTreeExercises, id)
+	if err != nil {
+		log.Printf("Get exercises failed for playlist %d: %v", id, err)
+		return Playlist{}, err
+	}
+	defer exerciseRows.Close()
+
+	for exerciseRows.Next() {
+		var exercise PlaylistExercise
+		var config Config
+		err := exerciseRows.Scan(
+			&exercise.ID,
+			&exercise.PlaylistID,
+			&exercise.ExerciseID,
+			&exercise.BlockID,
+			&exercise.ConfigID,
+			&exercise.ExerciseOrder,
+			&exercise.CreatedAt,
+			&exercise.UpdatedAt,
+			&exercise.ExerciseName,
+			&config.ID,
+			&config.Sets,
+			&config.RepsMin,
+			&config.RepsMax,
+			&config.Weight,
+			&config.RestSeconds,
+			pq.Array(&config.Tempo),
+			&config.DurationSeconds,
+			&config.Distance,
+			&config.TargetPace,
+			&config.TargetHeartRate,
+			&config.Incline,
+			&config.Notes,
+			&config.CreatedAt,
+			&config.UpdatedAt,
+		)
+		if err != nil {
+			return Playlist{}, err
+		}
+		exercise.Config = &config
+
+		i, ok := blockIndex[exercise.BlockID]
+		if !ok {
+			continue
+		}
+		playlist.Blocks[i].Exercises = append(playlist.Blocks[i].Exercises, exercise)
+	}
+
+	return playlist, exerciseRows.Err()
 }
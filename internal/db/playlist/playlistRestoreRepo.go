@@ -0,0 +1,142 @@
+package playlist
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+const restorePlaylistDetails = `
+	UPDATE playlists
+	SET title = $2, description = $3, visibility = $4, updated_at = NOW()
+	WHERE id = $1`
+
+const clearPlaylistTags = `DELETE FROM playlist_tags WHERE playlist_id = $1`
+
+// Tags deleted since the snapshot are skipped
+const restorePlaylistTags = `
+	INSERT INTO playlist_tags (playlist_id, tag_id)
+	SELECT $1, id FROM tags WHERE id = ANY($2)`
+
+// Cascades to playlist_exercises. Configs are kept for the sessions that
+// still reference them.
+const clearPlaylistBlocks = `DELETE FROM exercise_blocks WHERE playlist_id = $1`
+
+const restoreBlock = `
+	INSERT INTO exercise_blocks (playlist_id, name, block_type, block_order, rest_after_block_seconds)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id`
+
+const exerciseExists = `SELECT EXISTS (SELECT 1 FROM exercises WHERE id = $1)`
+
+const restoreConfig = `
+	INSERT INTO exercise_configs (sets, reps_min, reps_max, weight, rest_seconds, tempo,
+		duration_seconds, distance, target_pace, target_heart_rate, incline, notes)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	RETURNING id`
+
+const restorePlaylistExercise = `
+	INSERT INTO playlist_exercises (playlist_id, exercise_id, block_id, config_id, exercise_order)
+	VALUES ($1, $2, $3, $4, $5)`
+
+// Restore rebuilds the playlist from a snapshot in one transaction.
+// Exercises removed from the catalog since the snapshot are skipped.
+func (r *playlistRepo) Restore(ctx context.Context, snapshot Playlist) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, restorePlaylistDetails,
+			snapshot.ID,
+			snapshot.Title,
+			snapshot.Description,
+			snapshot.Visibility,
+		)
+		if err != nil {
+			return err
+		}
+
+		tagIDs := make([]int, len(snapshot.Tags))
+		for i, tag := range snapshot.Tags {
+			tagIDs[i] = tag.ID
+		}
+		if _, err := tx.ExecContext(ctx, clearPlaylistTags, snapshot.ID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, restorePlaylistTags, snapshot.ID, pq.Array(tagIDs)); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, clearPlaylistBlocks, snapshot.ID); err != nil {
+			return err
+		}
+
+		for _, block := range snapshot.Blocks {
+			var blockID int
+			err := tx.QueryRowContext(ctx, restoreBlock,
+				snapshot.ID,
+				block.Name,
+				block.BlockType,
+				block.BlockOrder,
+				block.RestAfterBlockSeconds,
+			).Scan(&blockID)
+			if err != nil {
+				return err
+			}
+
+			for _, exercise := range block.Exercises {
+				if err := restoreExercise(ctx, tx, snapshot.ID, blockID, exercise); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Restore playlist %d failed: %v", snapshot.ID, err)
+	}
+	return err
+}
+
+func restoreExercise(ctx context.Context, tx *sql.Tx, playlistID, blockID int, exercise PlaylistExercise) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, exerciseExists, exercise.ExerciseID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		log.Printf("Skipping deleted exercise %d while restoring playlist %d", exercise.ExerciseID, playlistID)
+		return nil
+	}
+
+	config := Config{RestSeconds: 60}
+	if exercise.Config != nil {
+		config = *exercise.Config
+	}
+
+	var configID int
+	err := tx.QueryRowContext(ctx, restoreConfig,
+		config.Sets,
+		config.RepsMin,
+		config.RepsMax,
+		config.Weight,
+		config.RestSeconds,
+		pq.Array(config.Tempo),
+		config.DurationSeconds,
+		config.Distance,
+		config.TargetPace,
+		config.TargetHeartRate,
+		config.Incline,
+		config.Notes,
+	).Scan(&configID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, restorePlaylistExercise,
+		playlistID,
+		exercise.ExerciseID,
+		blockID,
+		configID,
+		exercise.ExerciseOrder,
+	)
+	return err
+}
@@ -7,24 +7,45 @@ import (
 	"log"
 
 	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/coaching"
+	"github.com/cheezecakee/fitrkr/internal/db/user"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 var (
 	ErrPlaylistNotFound   = errors.New("playlist not found")
 	ErrPlaylistExists     = errors.New("playlist with this title already exists")
+	ErrInvalidPlaylist    = errors.New("invalid playlist")
 	ErrUnauthorizedAccess = errors.New("unauthorized access to playlist")
 	ErrBlockNotFound      = errors.New("exercise block not found")
 	ErrInvalidBlockType   = errors.New("invalid block type")
+	ErrInvalidBlockName   = errors.New("block name is required")
+	ErrInvalidRest        = errors.New("rest seconds cannot be negative")
 	ErrConfigNotFound     = errors.New("exercise config not found")
+	ErrInvalidConfig      = errors.New("invalid exercise config")
+	ErrConfigConflict     = errors.New("exercise config was changed since it was read")
+	ErrInvalidFilter      = errors.New("invalid playlist filter")
+	ErrShareTokenNotFound = errors.New("share link not found")
+	ErrPrivatePlaylist    = errors.New("private playlists cannot be shared")
+	ErrInvalidExpiry      = errors.New("expiry must be in the future")
+	ErrVersionNotFound    = errors.New("playlist version not found")
+	ErrExerciseNotFound   = errors.New("playlist exercise not found")
+	ErrInvalidOrder       = errors.New("order must list every item exactly once")
 )
 
 type PlaylistService interface {
 	// Playlist operations
 	CreatePlaylist(ctx context.Context, userID uuid.UUID, req CreatePlaylistRequest) (Playlist, error)
 	GetPlaylistByID(ctx context.Context, id int, userID uuid.UUID) (Playlist, error)
-	GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]PlaylistWithDetails, error)
+	GetUserPlaylists(ctx context.Context, userID uuid.UUID, page pagination.Request) (pagination.Page[PlaylistWithDetails], error)
+	GetClientPlaylists(ctx context.Context, coachID, clientID uuid.UUID, page pagination.Request) (pagination.Page[PlaylistWithDetails], error)
+	DiscoverPlaylists(ctx context.Context, req DiscoverPlaylistsRequest, page pagination.Request) (pagination.Page[PublicPlaylist], error)
 	UpdatePlaylist(ctx context.Context, id int, userID uuid.UUID, req UpdatePlaylistRequest) (Playlist, error)
 	DeletePlaylist(ctx context.Context, id int, userID uuid.UUID) error
+	CopyPlaylist(ctx context.Context, id int, userID uuid.UUID) (Playlist, error)
+	CopySharedPlaylist(ctx context.Context, token string, userID uuid.UUID) (Playlist, error)
+	CreatePlaylistTree(ctx context.Context, userID uuid.UUID, req BulkPlaylistRequest) (Playlist, error)
 
 	// Full playlist with all exercises (for starting a session)
 	GetPlaylistForSession(ctx context.Context, id int, userID uuid.UUID) (Playlist, error)
@@ -32,14 +53,33 @@ type PlaylistService interface {
 	// Exercise management
 	AddExerciseToPlaylist(ctx context.Context, playlistID int, userID uuid.UUID, req AddExerciseToPlaylistRequest) (PlaylistExercise, error)
 	RemoveExerciseFromPlaylist(ctx context.Context, exerciseID int, userID uuid.UUID) error
-	UpdateConfig(ctx context.Context, exerciseID int, userID uuid.UUID, config Config) (Config, error)
+	UpdateConfig(ctx context.Context, exerciseID int, userID uuid.UUID, req UpdateConfigRequest) (Config, error)
 
 	// Block management
 	CreateBlock(ctx context.Context, playlistID int, userID uuid.UUID, blockName string, blockType string) (Block, error)
+	UpdateBlock(ctx context.Context, playlistID, blockID int, userID uuid.UUID, req UpdateBlockRequest) (Block, error)
+	DeleteBlock(ctx context.Context, playlistID, blockID int, userID uuid.UUID, moveTo *int) error
 	UpdateBlockOrder(ctx context.Context, playlistID int, userID uuid.UUID, blockOrders []BlockOrder) error
+	UpdateExerciseOrder(ctx context.Context, playlistID, blockID int, userID uuid.UUID, exerciseOrders []ExerciseOrder) error
+	MoveExercise(ctx context.Context, exerciseID int, userID uuid.UUID, req MoveExerciseRequest) (PlaylistExercise, error)
+
+	// Version history
+	ListVersions(ctx context.Context, playlistID int, userID uuid.UUID) ([]PlaylistVersion, error)
+	GetVersion(ctx context.Context, playlistID int, userID uuid.UUID, version int) (PlaylistVersion, error)
+	DiffVersions(ctx context.Context, playlistID int, userID uuid.UUID, from, to int) (VersionDiff, error)
+	RestoreVersion(ctx context.Context, playlistID int, userID uuid.UUID, version int) (Playlist, error)
+
+	// Share links
+	CreateShareToken(ctx context.Context, playlistID int, userID uuid.UUID, req CreateShareTokenRequest) (ShareToken, error)
+	ListShareTokens(ctx context.Context, playlistID int, userID uuid.UUID) ([]ShareToken, error)
+	RevokeShareToken(ctx context.Context, playlistID int, userID uuid.UUID, tokenID int) error
+	GetSharedPlaylist(ctx context.Context, token string) (SharedPlaylist, error)
+
+	// Block rules
+	ValidatePlaylist(ctx context.Context, playlistID int, userID uuid.UUID) (ValidationReport, error)
 
 	// Utility methods
-	GetAllTags(ctx context.Context, userID uuid.UUID) ([]Tag, error)
+	GetAllTags(ctx context.Context, userID uuid.UUID, page pagination.Request) (pagination.Page[Tag], error)
 
 	// Validation helpers
 	ValidatePlaylistAccess(ctx context.Context, playlistID int, userID uuid.UUID) error
@@ -50,6 +90,10 @@ type playlistService struct {
 	blockRepo            BlockRepo
 	playlistExerciseRepo PlaylistExerciseRepo
 	configRepo           ConfigRepo
+	shareTokenRepo       ShareTokenRepo
+	versionRepo          PlaylistVersionRepo
+	accountPolicy        user.AccountPolicy
+	clientAccess         coaching.ClientAccess
 }
 
 func NewPlaylistService(
@@ -57,27 +101,42 @@ func NewPlaylistService(
 	blockRepo BlockRepo,
 	playlistExerciseRepo PlaylistExerciseRepo,
 	configRepo ConfigRepo,
+	shareTokenRepo ShareTokenRepo,
+	versionRepo PlaylistVersionRepo,
+	accountPolicy user.AccountPolicy,
+	clientAccess coaching.ClientAccess,
 ) PlaylistService {
 	return &playlistService{
 		playlistRepo:         playlistRepo,
 		blockRepo:            blockRepo,
 		playlistExerciseRepo: playlistExerciseRepo,
 		configRepo:           configRepo,
+		shareTokenRepo:       shareTokenRepo,
+		versionRepo:          versionRepo,
+		accountPolicy:        accountPolicy,
+		clientAccess:         clientAccess,
 	}
 }
 
-// CreatePlaylist creates a new playlist with default block
+// CreatePlaylist creates a new playlist with default block. Coaches can
+// create it in the account of an accepted client.
 func (s *playlistService) CreatePlaylist(ctx context.Context, userID uuid.UUID, req CreatePlaylistRequest) (Playlist, error) {
 	// Set defaults
 	if req.Visibility == "" {
 		req.Visibility = string(VisibilityPrivate)
 	}
 
+	ownerID, assignedBy, err := s.playlistOwner(ctx, userID, req)
+	if err != nil {
+		return Playlist{}, err
+	}
+
 	playlist := Playlist{
-		UserID:      userID,
+		UserID:      ownerID,
 		Title:       req.Title,
 		Description: req.Description,
 		Visibility:  Visibility(req.Visibility),
+		AssignedBy:  assignedBy,
 	}
 
 	// Create playlist
@@ -113,9 +172,38 @@ func (s *playlistService) CreatePlaylist(ctx context.Context, userID uuid.UUID,
 		}
 	}
 
+	s.recordVersion(ctx, createdPlaylist.ID, userID, ChangeCreated)
+
 	return createdPlaylist, nil
 }
 
+// playlistOwner returns who a new playlist belongs to. Coaches can create
+// playlists for an accepted client, which records them as assigner.
+func (s *playlistService) playlistOwner(ctx context.Context, userID uuid.UUID, req CreatePlaylistRequest) (uuid.UUID, *uuid.UUID, error) {
+	ownerID := userID
+	var assignedBy *uuid.UUID
+	if req.ClientID != nil && *req.ClientID != userID {
+		if err := s.checkCoachOf(ctx, userID, *req.ClientID); err != nil {
+			return uuid.Nil, nil, err
+		}
+		ownerID = *req.ClientID
+		assignedBy = &userID
+
+		// Publishing is left to the client
+		if Visibility(req.Visibility) == VisibilityPublic {
+			return uuid.Nil, nil, ErrUnauthorizedAccess
+		}
+	}
+
+	// Publishing may require a verified email
+	if Visibility(req.Visibility) == VisibilityPublic {
+		if err := s.accountPolicy.CheckVerified(ctx, ownerID); err != nil {
+			return uuid.Nil, nil, err
+		}
+	}
+	return ownerID, assignedBy, nil
+}
+
 // GetPlaylistByID returns basic playlist info
 func (s *playlistService) GetPlaylistByID(ctx context.Context, id int, userID uuid.UUID) (Playlist, error) {
 	playlist, err := s.playlistRepo.GetByID(ctx, id)
@@ -127,9 +215,12 @@ func (s *playlistService) GetPlaylistByID(ctx context.Context, id int, userID uu
 		return Playlist{}, ErrPlaylistNotFound
 	}
 
-	// Check access
-	if playlist.UserID != userID && playlist.Visibility == VisibilityPrivate {
-		return Playlist{}, ErrUnauthorizedAccess
+	// Only public playlists are open to everyone. Unlisted ones are reached
+	// through share links, and coaches can see their clients' playlists.
+	if playlist.UserID != userID && playlist.Visibility != VisibilityPublic {
+		if err := s.checkCoachOf(ctx, userID, playlist.UserID); err != nil {
+			return Playlist{}, err
+		}
 	}
 
 	// Get tags
@@ -143,15 +234,34 @@ func (s *playlistService) GetPlaylistByID(ctx context.Context, id int, userID uu
 	return playlist, nil
 }
 
-// GetUserPlaylists returns all playlists for a user with summary info
-func (s *playlistService) GetUserPlaylists(ctx context.Context, userID uuid.UUID) ([]PlaylistWithDetails, error) {
-	playlists, err := s.playlistRepo.GetUserPlaylists(ctx, userID)
+// GetUserPlaylists returns a page of the user's playlists with summary
+// info, most recently updated first
+func (s *playlistService) GetUserPlaylists(ctx context.Context, userID uuid.UUID, page pagination.Request) (pagination.Page[PlaylistWithDetails], error) {
+	var after *Playlist
+	if page.Cursor != "" {
+		after = &Playlist{}
+		if err := pagination.Decode(page.Cursor, &after.UpdatedAt, &after.ID); err != nil {
+			return pagination.Page[PlaylistWithDetails]{}, err
+		}
+	}
+
+	// One extra playlist tells whether another page exists
+	size := page.PageSize()
+	playlists, err := s.playlistRepo.GetUserPlaylists(ctx, userID, after, size+1)
+	if err != nil {
+		return pagination.Page[PlaylistWithDetails]{}, err
+	}
+	total, err := s.playlistRepo.CountUserPlaylists(ctx, userID)
 	if err != nil {
-		return nil, err
+		return pagination.Page[PlaylistWithDetails]{}, err
 	}
 
-	var playlistsWithDetails []PlaylistWithDetails
-	for _, playlist := range playlists {
+	playlistPage := pagination.NewPage(playlists, size, total, func(playlist Playlist) string {
+		return pagination.Encode(playlist.UpdatedAt, playlist.ID)
+	})
+
+	playlistsWithDetails := []PlaylistWithDetails{}
+	for _, playlist := range playlistPage.Items {
 		// Get exercise count for each playlist
 		exercises, err := s.playlistExerciseRepo.GetPlaylistExercises(ctx, playlist.ID)
 		if err != nil {
@@ -181,20 +291,33 @@ func (s *playlistService) GetUserPlaylists(ctx context.Context, userID uuid.UUID
 		playlistsWithDetails = append(playlistsWithDetails, playlistWithDetails)
 	}
 
-	return playlistsWithDetails, nil
+	return pagination.Page[PlaylistWithDetails]{
+		Items:      playlistsWithDetails,
+		NextCursor: playlistPage.NextCursor,
+		Total:      total,
+	}, nil
+}
+
+// GetClientPlaylists returns the playlists of a client the user coaches
+func (s *playlistService) GetClientPlaylists(ctx context.Context, coachID, clientID uuid.UUID, page pagination.Request) (pagination.Page[PlaylistWithDetails], error) {
+	if err := s.checkCoachOf(ctx, coachID, clientID); err != nil {
+		return pagination.Page[PlaylistWithDetails]{}, err
+	}
+	return s.GetUserPlaylists(ctx, clientID, page)
 }
 
 // UpdatePlaylist updates playlist details
 func (s *playlistService) UpdatePlaylist(ctx context.Context, id int, userID uuid.UUID, req UpdatePlaylistRequest) (Playlist, error) {
 	// Validate access
-	if err := s.ValidatePlaylistAccess(ctx, id, userID); err != nil {
+	existing, err := s.authorizePlaylist(ctx, id, userID)
+	if err != nil {
 		return Playlist{}, err
 	}
 
 	// Build update struct with only provided fields
 	updatePlaylist := Playlist{
 		ID:     id,
-		UserID: userID,
+		UserID: existing.UserID,
 	}
 
 	if req.Title != nil {
@@ -205,8 +328,19 @@ func (s *playlistService) UpdatePlaylist(ctx context.Context, id int, userID uui
 	}
 	if req.Visibility != nil {
 		updatePlaylist.Visibility = Visibility(*req.Visibility)
+		if updatePlaylist.Visibility == VisibilityPublic {
+			// Coaches edit their clients' playlists but do not publish them
+			if existing.UserID != userID && existing.Visibility != VisibilityPublic {
+				return Playlist{}, ErrUnauthorizedAccess
+			}
+			if err := s.accountPolicy.CheckVerified(ctx, existing.UserID); err != nil {
+				return Playlist{}, err
+			}
+		}
 	}
 
+	s.snapshotBaseline(ctx, id, userID)
+
 	updatedPlaylist, err := s.playlistRepo.Update(ctx, updatePlaylist)
 	if err != nil {
 		if isUniqueConstraintError(err) {
@@ -235,13 +369,14 @@ func (s *playlistService) UpdatePlaylist(ctx context.Context, id int, userID uui
 		}
 	}
 
+	s.recordVersion(ctx, id, userID, ChangePlaylistUpdated)
+
 	return updatedPlaylist, nil
 }
 
 // DeletePlaylist removes a playlist and all associated data
 func (s *playlistService) DeletePlaylist(ctx context.Context, id int, userID uuid.UUID) error {
-	// Validate access
-	if err := s.ValidatePlaylistAccess(ctx, id, userID); err != nil {
+	if _, err := s.ownPlaylist(ctx, id, userID); err != nil {
 		return err
 	}
 
@@ -249,20 +384,122 @@ func (s *playlistService) DeletePlaylist(ctx context.Context, id int, userID uui
 	return s.playlistRepo.Delete(ctx, id)
 }
 
-// ValidatePlaylistAccess checks if user can access playlist
+// CopyPlaylist copies a public playlist, one of the user's own or one of
+// a client they coach into their account as a private playlist they can
+// adapt. Unlisted playlists of others are copied through a share link.
+func (s *playlistService) CopyPlaylist(ctx context.Context, id int, userID uuid.UUID) (Playlist, error) {
+	source, err := s.playlistRepo.GetByID(ctx, id)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	if source.ID == 0 {
+		return Playlist{}, ErrPlaylistNotFound
+	}
+
+	if source.UserID != userID && source.Visibility != VisibilityPublic {
+		if err := s.checkCoachOf(ctx, userID, source.UserID); err != nil {
+			return Playlist{}, err
+		}
+	}
+
+	return s.copyPlaylist(ctx, source, userID)
+}
+
+// CopySharedPlaylist copies the playlist behind a share link, with the
+// same checks as viewing it
+func (s *playlistService) CopySharedPlaylist(ctx context.Context, token string, userID uuid.UUID) (Playlist, error) {
+	playlistID, err := s.shareTokenRepo.GetPlaylistID(ctx, token)
+	if err != nil {
+		return Playlist{}, err
+	}
+	if playlistID == 0 {
+		return Playlist{}, ErrShareTokenNotFound
+	}
+
+	source, err := s.playlistRepo.GetByID(ctx, playlistID)
+	if err != nil {
+		return Playlist{}, err
+	}
+	if source.ID == 0 || source.Visibility == VisibilityPrivate {
+		return Playlist{}, ErrShareTokenNotFound
+	}
+
+	return s.copyPlaylist(ctx, source, userID)
+}
+
+func (s *playlistService) copyPlaylist(ctx context.Context, source Playlist, userID uuid.UUID) (Playlist, error) {
+	// Notes are the owner's own cues, like in the shared view
+	keepNotes := source.UserID == userID
+
+	copied, err := s.playlistRepo.Copy(ctx, source.ID, userID, keepNotes)
+	if err != nil {
+		// Another request took the same title first
+		if isUniqueConstraintError(err) {
+			return Playlist{}, ErrPlaylistExists
+		}
+		return Playlist{}, fmt.Errorf("failed to copy playlist: %w", err)
+	}
+
+	log.Printf("User %s copied playlist %d to %d", userID, source.ID, copied.ID)
+	return copied, nil
+}
+
+// ValidatePlaylistAccess checks if user can edit playlist, as its owner or
+// as an accepted coach of the owner
 func (s *playlistService) ValidatePlaylistAccess(ctx context.Context, playlistID int, userID uuid.UUID) error {
+	_, err := s.authorizePlaylist(ctx, playlistID, userID)
+	return err
+}
+
+// authorizePlaylist returns the playlist if user can edit it
+func (s *playlistService) authorizePlaylist(ctx context.Context, playlistID int, userID uuid.UUID) (Playlist, error) {
 	playlist, err := s.playlistRepo.GetByID(ctx, playlistID)
 	if err != nil {
-		return err
+		return Playlist{}, err
 	}
 
 	if playlist.ID == 0 {
-		return ErrPlaylistNotFound
+		return Playlist{}, ErrPlaylistNotFound
 	}
 
 	if playlist.UserID != userID {
-		return ErrUnauthorizedAccess
+		if err := s.checkCoachOf(ctx, userID, playlist.UserID); err != nil {
+			return Playlist{}, err
+		}
+	}
+
+	return playlist, nil
+}
+
+// ownPlaylist returns the playlist if user owns it. Coaches can edit their
+// clients' playlists, but deleting, sharing and publishing are left to the
+// owner.
+func (s *playlistService) ownPlaylist(ctx context.Context, playlistID int, userID uuid.UUID) (Playlist, error) {
+	playlist, err := s.playlistRepo.GetByID(ctx, playlistID)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	if playlist.ID == 0 {
+		return Playlist{}, ErrPlaylistNotFound
 	}
 
+	if playlist.UserID != userID {
+		return Playlist{}, ErrUnauthorizedAccess
+	}
+
+	return playlist, nil
+}
+
+// checkCoachOf returns ErrUnauthorizedAccess unless coachID coaches clientID
+func (s *playlistService) checkCoachOf(ctx context.Context, coachID, clientID uuid.UUID) error {
+	ok, err := s.clientAccess.IsCoachOf(ctx, coachID, clientID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUnauthorizedAccess
+	}
 	return nil
 }
@@ -0,0 +1,148 @@
+package playlist
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/helper"
+)
+
+// CreateShareToken creates a link anyone can use to view the playlist.
+// Private playlists have to be made unlisted or public first. Share links
+// are managed by the owner only.
+func (s *playlistService) CreateShareToken(ctx context.Context, playlistID int, userID uuid.UUID, req CreateShareTokenRequest) (ShareToken, error) {
+	playlist, err := s.ownPlaylist(ctx, playlistID, userID)
+	if err != nil {
+		return ShareToken{}, err
+	}
+
+	if playlist.Visibility == VisibilityPrivate {
+		return ShareToken{}, ErrPrivatePlaylist
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return ShareToken{}, ErrInvalidExpiry
+	}
+
+	token, err := helper.MakeRefreshToken()
+	if err != nil {
+		return ShareToken{}, err
+	}
+
+	shareToken, err := s.shareTokenRepo.Create(ctx, ShareToken{
+		PlaylistID: playlistID,
+		Token:      token,
+		CreatedBy:  &userID,
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		return ShareToken{}, err
+	}
+
+	log.Printf("User %s created share link %d for playlist %d", userID, shareToken.ID, playlistID)
+	return shareToken, nil
+}
+
+// ListShareTokens returns every link of the playlist, including revoked
+// and expired ones
+func (s *playlistService) ListShareTokens(ctx context.Context, playlistID int, userID uuid.UUID) ([]ShareToken, error) {
+	if _, err := s.ownPlaylist(ctx, playlistID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.shareTokenRepo.ListByPlaylist(ctx, playlistID)
+}
+
+func (s *playlistService) RevokeShareToken(ctx context.Context, playlistID int, userID uuid.UUID, tokenID int) error {
+	if _, err := s.ownPlaylist(ctx, playlistID, userID); err != nil {
+		return err
+	}
+
+	revoked, err := s.shareTokenRepo.Revoke(ctx, playlistID, tokenID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrShareTokenNotFound
+	}
+
+	log.Printf("User %s revoked share link %d of playlist %d", userID, tokenID, playlistID)
+	return nil
+}
+
+// GetSharedPlaylist returns the playlist behind a share link. Links stop
+// working when revoked, expired or when the playlist is made private.
+func (s *playlistService) GetSharedPlaylist(ctx context.Context, token string) (SharedPlaylist, error) {
+	playlistID, err := s.shareTokenRepo.GetPlaylistID(ctx, token)
+	if err != nil {
+		return SharedPlaylist{}, err
+	}
+	if playlistID == 0 {
+		return SharedPlaylist{}, ErrShareTokenNotFound
+	}
+
+	playlist, err := s.playlistRepo.GetPlaylistWithBlocks(ctx, playlistID)
+	if err != nil {
+		return SharedPlaylist{}, err
+	}
+	if playlist.ID == 0 || playlist.Visibility == VisibilityPrivate {
+		return SharedPlaylist{}, ErrShareTokenNotFound
+	}
+
+	tags, err := s.playlistRepo.GetPlaylistTags(ctx, playlistID)
+	if err != nil {
+		return SharedPlaylist{}, err
+	}
+
+	return SharedPlaylist{
+		Title:       playlist.Title,
+		Description: playlist.Description,
+		CreatedAt:   playlist.CreatedAt,
+		UpdatedAt:   playlist.UpdatedAt,
+		Tags:        tags,
+		Blocks:      sharedBlocks(playlist.Blocks),
+	}, nil
+}
+
+// sharedBlocks copies the blocks into their shared form. Notes are the
+// owner's own cues and stay out.
+func sharedBlocks(blocks []Block) []SharedBlock {
+	shared := make([]SharedBlock, len(blocks))
+	for i, block := range blocks {
+		exercises := make([]SharedExercise, len(block.Exercises))
+		for j, exercise := range block.Exercises {
+			exercises[j] = SharedExercise{
+				ExerciseID:    exercise.ExerciseID,
+				ExerciseName:  exercise.ExerciseName,
+				ExerciseOrder: exercise.ExerciseOrder,
+			}
+			if config := exercise.Config; config != nil {
+				exercises[j].Config = &SharedConfig{
+					Sets:            config.Sets,
+					RepsMin:         config.RepsMin,
+					RepsMax:         config.RepsMax,
+					Weight:          config.Weight,
+					RestSeconds:     config.RestSeconds,
+					Tempo:           config.Tempo,
+					DurationSeconds: config.DurationSeconds,
+					Distance:        config.Distance,
+					TargetPace:      config.TargetPace,
+					TargetHeartRate: config.TargetHeartRate,
+					Incline:         config.Incline,
+				}
+			}
+		}
+
+		shared[i] = SharedBlock{
+			Name:                  block.Name,
+			BlockType:             block.BlockType,
+			BlockOrder:            block.BlockOrder,
+			RestAfterBlockSeconds: block.RestAfterBlockSeconds,
+			Exercises:             exercises,
+		}
+	}
+	return shared
+}
@@ -26,6 +26,44 @@ func (r *playlistRepo) GetAllTags(ctx context.Context, userID uuid.UUID) ([]Tag,
 	return tags, rows.Err()
 }
 
+const listTags = `
+	SELECT id, name FROM tags
+	WHERE $1::text IS NULL OR (name, id) > ($1, $2)
+	ORDER BY name, id
+	LIMIT $3`
+
+func (r *playlistRepo) ListTags(ctx context.Context, after *Tag, limit int) ([]Tag, error) {
+	var afterName *string
+	var afterID int
+	if after != nil {
+		afterName, afterID = &after.Name, after.ID
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, listTags, afterName, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []Tag
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+const countTags = `SELECT COUNT(*) FROM tags`
+
+func (r *playlistRepo) CountTags(ctx context.Context) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countTags).Scan(&total)
+	return total, err
+}
+
 const createTag = `INSERT INTO tags (name) VALUES ($1) RETURNING id, name`
 
 func (r *playlistRepo) CreateTag(ctx context.Context, name string) (Tag, error) {
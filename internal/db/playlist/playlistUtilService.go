@@ -1,21 +1,21 @@
 package playlist
 
 import (
-	"slices"
+	"errors"
 
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 func isUniqueConstraintError(err error) bool {
-	// For PostgreSQL with pq driver
-	if pqErr, ok := err.(*pq.Error); ok {
-		return pqErr.Code == "23505" // unique_violation
+	// For PostgreSQL with pgx driver
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505" // unique_violation
 	}
 	return false
 }
 
 // isValidBlockType validates if the block type is supported
 func isValidBlockType(blockType string) bool {
-	validTypes := []BlockType{BlockTypePlaylist, BlockTypeSuperset, BlockTypeCircuit, BlockTypeDropset}
-	return slices.Contains(validTypes, BlockType(blockType))
+	return BlockType(blockType).IsValid()
 }
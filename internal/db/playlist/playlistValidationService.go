@@ -0,0 +1,63 @@
+package playlist
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ValidatePlaylist checks every block of a playlist against the rules of
+// its type, including incomplete blocks that saving allows
+func (s *playlistService) ValidatePlaylist(ctx context.Context, playlistID int, userID uuid.UUID) (ValidationReport, error) {
+	if _, err := s.GetPlaylistByID(ctx, playlistID, userID); err != nil {
+		return ValidationReport{}, err
+	}
+
+	playlist, err := s.playlistRepo.GetPlaylistWithBlocks(ctx, playlistID)
+	if err != nil {
+		return ValidationReport{}, err
+	}
+
+	var exerciseIDs []int
+	for _, block := range playlist.Blocks {
+		for _, exercise := range block.Exercises {
+			exerciseIDs = append(exerciseIDs, exercise.ExerciseID)
+		}
+	}
+
+	trainingTypes, err := s.playlistExerciseRepo.GetTrainingTypes(ctx, exerciseIDs)
+	if err != nil {
+		return ValidationReport{}, err
+	}
+
+	report := ValidationReport{
+		PlaylistID: playlistID,
+		Violations: []Violation{},
+	}
+	for _, block := range playlist.Blocks {
+		report.Violations = append(report.Violations, ValidateBlock(block, trainingTypes)...)
+	}
+	report.Valid = len(report.Violations) == 0
+
+	return report, nil
+}
+
+// checkBlockRules returns a *ValidationError if the block, with its
+// exercises loaded, breaks a rule that rejects changes
+func (s *playlistService) checkBlockRules(ctx context.Context, block Block) error {
+	if _, ok := BlockRules[block.BlockType]; !ok {
+		return nil
+	}
+
+	exerciseIDs := make([]int, len(block.Exercises))
+	for i, exercise := range block.Exercises {
+		exerciseIDs[i] = exercise.ExerciseID
+	}
+
+	trainingTypes, err := s.playlistExerciseRepo.GetTrainingTypes(ctx, exerciseIDs)
+	if err != nil {
+		return err
+	}
+
+	return blockErrors(ValidateBlock(block, trainingTypes))
+}
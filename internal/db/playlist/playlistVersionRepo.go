@@ -0,0 +1,151 @@
+package playlist
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type PlaylistVersionRepo interface {
+	// Stores the snapshot as the next version of the playlist
+	Create(ctx context.Context, playlistID int, createdBy uuid.UUID, change VersionChange, snapshot Playlist) (PlaylistVersion, error)
+	Exists(ctx context.Context, playlistID int) (bool, error)
+
+	// Lists versions newest first, without snapshots
+	List(ctx context.Context, playlistID int) ([]PlaylistVersion, error)
+
+	// Returns a version with its snapshot, or an empty version
+	Get(ctx context.Context, playlistID, version int) (PlaylistVersion, error)
+}
+
+type playlistVersionRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewPlaylistVersionRepo(db *sql.DB) PlaylistVersionRepo {
+	return &playlistVersionRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+// Serializes version numbering per playlist. The insert below runs after
+// the lock is granted and so sees the version a concurrent edit added.
+const lockPlaylistVersions = `SELECT id FROM playlists WHERE id = $1 FOR UPDATE`
+
+const createPlaylistVersion = `
+	INSERT INTO playlist_versions (playlist_id, version, change, snapshot, created_by)
+	SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4
+	FROM playlist_versions
+	WHERE playlist_id = $1
+	RETURNING id, playlist_id, version, change, created_by, created_at`
+
+func (r *playlistVersionRepo) Create(ctx context.Context, playlistID int, createdBy uuid.UUID, change VersionChange, snapshot Playlist) (PlaylistVersion, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return PlaylistVersion{}, err
+	}
+
+	var version PlaylistVersion
+	err = r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, lockPlaylistVersions, playlistID).Scan(new(int)); err != nil {
+			return err
+		}
+
+		return tx.QueryRowContext(ctx, createPlaylistVersion, playlistID, change, data, createdBy).Scan(
+			&version.ID,
+			&version.PlaylistID,
+			&version.Version,
+			&version.Change,
+			&version.CreatedBy,
+			&version.CreatedAt,
+		)
+	})
+	if err != nil {
+		log.Printf("Create version of playlist %d failed: %v", playlistID, err)
+		return PlaylistVersion{}, err
+	}
+	return version, nil
+}
+
+const playlistVersionsExist = `SELECT EXISTS (SELECT 1 FROM playlist_versions WHERE playlist_id = $1)`
+
+func (r *playlistVersionRepo) Exists(ctx context.Context, playlistID int) (bool, error) {
+	var exists bool
+	if err := r.tx.DB().QueryRowContext(ctx, playlistVersionsExist, playlistID).Scan(&exists); err != nil {
+		log.Printf("Version check failed for playlist %d: %v", playlistID, err)
+		return false, err
+	}
+	return exists, nil
+}
+
+const listPlaylistVersions = `
+	SELECT id, playlist_id, version, change, created_by, created_at
+	FROM playlist_versions
+	WHERE playlist_id = $1
+	ORDER BY version DESC`
+
+func (r *playlistVersionRepo) List(ctx context.Context, playlistID int) ([]PlaylistVersion, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, listPlaylistVersions, playlistID)
+	if err != nil {
+		log.Printf("List versions failed for playlist %d: %v", playlistID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []PlaylistVersion
+	for rows.Next() {
+		var version PlaylistVersion
+		err := rows.Scan(
+			&version.ID,
+			&version.PlaylistID,
+			&version.Version,
+			&version.Change,
+			&version.CreatedBy,
+			&version.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+const getPlaylistVersion = `
+	SELECT id, playlist_id, version, change, created_by, created_at, snapshot
+	FROM playlist_versions
+	WHERE playlist_id = $1 AND version = $2`
+
+func (r *playlistVersionRepo) Get(ctx context.Context, playlistID, version int) (PlaylistVersion, error) {
+	var v PlaylistVersion
+	var data []byte
+	err := r.tx.DB().QueryRowContext(ctx, getPlaylistVersion, playlistID, version).Scan(
+		&v.ID,
+		&v.PlaylistID,
+		&v.Version,
+		&v.Change,
+		&v.CreatedBy,
+		&v.CreatedAt,
+		&data,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return PlaylistVersion{}, nil
+		}
+		log.Printf("Get version %d of playlist %d failed: %v", version, playlistID, err)
+		return PlaylistVersion{}, err
+	}
+
+	var snapshot Playlist
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return PlaylistVersion{}, err
+	}
+	v.Snapshot = &snapshot
+	return v, nil
+}
@@ -0,0 +1,306 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// ListVersions returns the versions of a playlist, newest first
+func (s *playlistService) ListVersions(ctx context.Context, playlistID int, userID uuid.UUID) ([]PlaylistVersion, error) {
+	if _, err := s.authorizePlaylist(ctx, playlistID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.versionRepo.List(ctx, playlistID)
+}
+
+// GetVersion returns a version with its snapshot
+func (s *playlistService) GetVersion(ctx context.Context, playlistID int, userID uuid.UUID, version int) (PlaylistVersion, error) {
+	if _, err := s.authorizePlaylist(ctx, playlistID, userID); err != nil {
+		return PlaylistVersion{}, err
+	}
+
+	return s.getVersion(ctx, playlistID, version)
+}
+
+// DiffVersions compares two versions. Exercises are matched by their
+// playlist exercise ID, so a restore shows its exercises as re-added.
+func (s *playlistService) DiffVersions(ctx context.Context, playlistID int, userID uuid.UUID, from, to int) (VersionDiff, error) {
+	if _, err := s.authorizePlaylist(ctx, playlistID, userID); err != nil {
+		return VersionDiff{}, err
+	}
+
+	fromVersion, err := s.getVersion(ctx, playlistID, from)
+	if err != nil {
+		return VersionDiff{}, err
+	}
+
+	toVersion, err := s.getVersion(ctx, playlistID, to)
+	if err != nil {
+		return VersionDiff{}, err
+	}
+
+	diff := diffPlaylists(*fromVersion.Snapshot, *toVersion.Snapshot)
+	diff.From = from
+	diff.To = to
+	return diff, nil
+}
+
+// RestoreVersion rebuilds the playlist as it was in a version. The restore
+// is itself recorded as a new version, so it can be undone.
+func (s *playlistService) RestoreVersion(ctx context.Context, playlistID int, userID uuid.UUID, version int) (Playlist, error) {
+	existing, err := s.authorizePlaylist(ctx, playlistID, userID)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	v, err := s.getVersion(ctx, playlistID, version)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	snapshot := *v.Snapshot
+	snapshot.ID = playlistID
+
+	// Publishing is left to the owner and may require a verified email
+	if snapshot.Visibility == VisibilityPublic && existing.Visibility != VisibilityPublic {
+		if existing.UserID != userID {
+			return Playlist{}, ErrUnauthorizedAccess
+		}
+		if err := s.accountPolicy.CheckVerified(ctx, existing.UserID); err != nil {
+			return Playlist{}, err
+		}
+	}
+
+	s.snapshotBaseline(ctx, playlistID, userID)
+
+	if err := s.playlistRepo.Restore(ctx, snapshot); err != nil {
+		if isUniqueConstraintError(err) {
+			return Playlist{}, ErrPlaylistExists
+		}
+		return Playlist{}, fmt.Errorf("failed to restore playlist: %w", err)
+	}
+
+	s.recordVersion(ctx, playlistID, userID, ChangeRestored)
+
+	log.Printf("User %s restored playlist %d to version %d", userID, playlistID, version)
+	return s.GetPlaylistForSession(ctx, playlistID, userID)
+}
+
+func (s *playlistService) getVersion(ctx context.Context, playlistID, version int) (PlaylistVersion, error) {
+	v, err := s.versionRepo.Get(ctx, playlistID, version)
+	if err != nil {
+		return PlaylistVersion{}, err
+	}
+	if v.ID == 0 {
+		return PlaylistVersion{}, ErrVersionNotFound
+	}
+	return v, nil
+}
+
+// snapshotBaseline stores the current tree as the first version of a
+// playlist created before versioning, so the state before its first
+// tracked edit can be restored. Call it before the edit.
+func (s *playlistService) snapshotBaseline(ctx context.Context, playlistID int, userID uuid.UUID) {
+	exists, err := s.versionRepo.Exists(ctx, playlistID)
+	if err != nil || exists {
+		return
+	}
+	s.recordVersion(ctx, playlistID, userID, ChangeBaseline)
+}
+
+// recordVersion stores the current tree after an edit. Failures are only
+// logged since the edit itself went through.
+func (s *playlistService) recordVersion(ctx context.Context, playlistID int, userID uuid.UUID, change VersionChange) {
+	snapshot, err := s.playlistRepo.GetPlaylistWithBlocks(ctx, playlistID)
+	if err != nil || snapshot.ID == 0 {
+		log.Printf("Failed to snapshot playlist %d: %v", playlistID, err)
+		return
+	}
+
+	tags, err := s.playlistRepo.GetPlaylistTags(ctx, playlistID)
+	if err != nil {
+		log.Printf("Failed to snapshot tags of playlist %d: %v", playlistID, err)
+		return
+	}
+	snapshot.Tags = tags
+
+	if _, err := s.versionRepo.Create(ctx, playlistID, userID, change, snapshot); err != nil {
+		log.Printf("Failed to record %s version of playlist %d: %v", change, playlistID, err)
+	}
+}
+
+func diffPlaylists(from, to Playlist) VersionDiff {
+	diff := VersionDiff{
+		Fields:             []FieldChange{},
+		AddedBlocks:        []BlockRef{},
+		RemovedBlocks:      []BlockRef{},
+		AddedExercises:     []ExerciseRef{},
+		RemovedExercises:   []ExerciseRef{},
+		ReorderedExercises: []ExerciseMove{},
+		ConfigChanges:      []ConfigChange{},
+	}
+
+	// Playlist details
+	if from.Title != to.Title {
+		diff.Fields = append(diff.Fields, FieldChange{Field: "title", From: from.Title, To: to.Title})
+	}
+	if !reflect.DeepEqual(from.Description, to.Description) {
+		diff.Fields = append(diff.Fields, FieldChange{Field: "description", From: from.Description, To: to.Description})
+	}
+	if from.Visibility != to.Visibility {
+		diff.Fields = append(diff.Fields, FieldChange{Field: "visibility", From: from.Visibility, To: to.Visibility})
+	}
+	if fromTags, toTags := tagNames(from.Tags), tagNames(to.Tags); !reflect.DeepEqual(fromTags, toTags) {
+		diff.Fields = append(diff.Fields, FieldChange{Field: "tags", From: fromTags, To: toTags})
+	}
+
+	// Blocks
+	fromBlocks := blocksByID(from)
+	toBlocks := blocksByID(to)
+	for _, block := range to.Blocks {
+		if _, ok := fromBlocks[block.ID]; !ok {
+			diff.AddedBlocks = append(diff.AddedBlocks, blockRef(block))
+		}
+	}
+	for _, block := range from.Blocks {
+		if _, ok := toBlocks[block.ID]; !ok {
+			diff.RemovedBlocks = append(diff.RemovedBlocks, blockRef(block))
+		}
+	}
+
+	// Exercises and their configs
+	fromExercises := exercisesByID(from)
+	toExercises := exercisesByID(to)
+	for _, block := range to.Blocks {
+		for _, exercise := range block.Exercises {
+			ref := exerciseRef(exercise, block)
+
+			previous, ok := fromExercises[exercise.ID]
+			if !ok {
+				diff.AddedExercises = append(diff.AddedExercises, ref)
+				continue
+			}
+
+			if previous.block.ID != block.ID || previous.exercise.ExerciseOrder != exercise.ExerciseOrder {
+				diff.ReorderedExercises = append(diff.ReorderedExercises, ExerciseMove{
+					ExerciseRef: ref,
+					FromBlock:   previous.block.Name,
+					FromOrder:   previous.exercise.ExerciseOrder,
+					ToOrder:     exercise.ExerciseOrder,
+				})
+			}
+
+			for _, field := range diffConfigs(previous.exercise.Config, exercise.Config) {
+				diff.ConfigChanges = append(diff.ConfigChanges, ConfigChange{
+					ExerciseRef: ref,
+					Field:       field.Field,
+					From:        field.From,
+					To:          field.To,
+				})
+			}
+		}
+	}
+	for _, block := range from.Blocks {
+		for _, exercise := range block.Exercises {
+			if _, ok := toExercises[exercise.ID]; !ok {
+				diff.RemovedExercises = append(diff.RemovedExercises, exerciseRef(exercise, block))
+			}
+		}
+	}
+
+	return diff
+}
+
+// diffConfigs lists the changed fields between two configs
+func diffConfigs(from, to *Config) []FieldChange {
+	if from == nil {
+		from = &Config{}
+	}
+	if to == nil {
+		to = &Config{}
+	}
+
+	fromFields := configFields(*from)
+	var changes []FieldChange
+	for i, field := range configFields(*to) {
+		if previous := fromFields[i].To; !reflect.DeepEqual(previous, field.To) {
+			changes = append(changes, FieldChange{Field: field.Field, From: previous, To: field.To})
+		}
+	}
+	return changes
+}
+
+// configFields returns the user-editable config values, with pointers
+// dereferenced so they compare by value
+func configFields(c Config) []FieldChange {
+	return []FieldChange{
+		{Field: "sets", To: deref(c.Sets)},
+		{Field: "reps_min", To: deref(c.RepsMin)},
+		{Field: "reps_max", To: deref(c.RepsMax)},
+		{Field: "weight", To: deref(c.Weight)},
+		{Field: "rest_seconds", To: c.RestSeconds},
+		{Field: "tempo", To: c.Tempo},
+		{Field: "duration_seconds", To: deref(c.DurationSeconds)},
+		{Field: "distance", To: deref(c.Distance)},
+		{Field: "target_pace", To: deref(c.TargetPace)},
+		{Field: "target_heart_rate", To: deref(c.TargetHeartRate)},
+		{Field: "incline", To: deref(c.Incline)},
+		{Field: "notes", To: deref(c.Notes)},
+	}
+}
+
+func deref[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+type exerciseInBlock struct {
+	exercise PlaylistExercise
+	block    Block
+}
+
+func exercisesByID(playlist Playlist) map[int]exerciseInBlock {
+	exercises := make(map[int]exerciseInBlock)
+	for _, block := range playlist.Blocks {
+		for _, exercise := range block.Exercises {
+			exercises[exercise.ID] = exerciseInBlock{exercise: exercise, block: block}
+		}
+	}
+	return exercises
+}
+
+func blocksByID(playlist Playlist) map[int]Block {
+	blocks := make(map[int]Block, len(playlist.Blocks))
+	for _, block := range playlist.Blocks {
+		blocks[block.ID] = block
+	}
+	return blocks
+}
+
+func blockRef(block Block) BlockRef {
+	return BlockRef{BlockID: block.ID, Name: block.Name, BlockType: block.BlockType}
+}
+
+func exerciseRef(exercise PlaylistExercise, block Block) ExerciseRef {
+	return ExerciseRef{
+		PlaylistExerciseID: exercise.ID,
+		ExerciseID:         exercise.ExerciseID,
+		ExerciseName:       exercise.ExerciseName,
+		BlockName:          block.Name,
+	}
+}
+
+func tagNames(tags []Tag) []string {
+	names := []string{}
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
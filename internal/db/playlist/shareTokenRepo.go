@@ -0,0 +1,136 @@
+package playlist
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type ShareTokenRepo interface {
+	Create(ctx context.Context, token ShareToken) (ShareToken, error)
+	ListByPlaylist(ctx context.Context, playlistID int) ([]ShareToken, error)
+
+	// Revokes a token of the playlist. Returns false if there is no such
+	// unrevoked token.
+	Revoke(ctx context.Context, playlistID, id int) (bool, error)
+
+	// Returns the playlist ID of an unrevoked, unexpired token, or 0
+	GetPlaylistID(ctx context.Context, token string) (int, error)
+}
+
+type shareTokenRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewShareTokenRepo(db *sql.DB) ShareTokenRepo {
+	return &shareTokenRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+func scanShareToken(row interface{ Scan(dest ...any) error }) (ShareToken, error) {
+	var token ShareToken
+	err := row.Scan(
+		&token.ID,
+		&token.PlaylistID,
+		&token.Token,
+		&token.CreatedBy,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	return token, err
+}
+
+const createShareToken = `
+	INSERT INTO playlist_share_tokens (playlist_id, token, created_by, expires_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, playlist_id, token, created_by, expires_at, revoked_at, created_at`
+
+func (r *shareTokenRepo) Create(ctx context.Context, token ShareToken) (ShareToken, error) {
+	var created ShareToken
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var err error
+		created, err = scanShareToken(tx.QueryRowContext(ctx, createShareToken,
+			token.PlaylistID,
+			token.Token,
+			token.CreatedBy,
+			token.ExpiresAt,
+		))
+		return err
+	})
+	if err != nil {
+		log.Printf("Create share token failed for playlist %d: %v", token.PlaylistID, err)
+		return ShareToken{}, err
+	}
+	return created, nil
+}
+
+const listShareTokens = `
+	SELECT id, playlist_id, token, created_by, expires_at, revoked_at, created_at
+	FROM playlist_share_tokens
+	WHERE playlist_id = $1
+	ORDER BY created_at DESC`
+
+func (r *shareTokenRepo) ListByPlaylist(ctx context.Context, playlistID int) ([]ShareToken, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, listShareTokens, playlistID)
+	if err != nil {
+		log.Printf("List share tokens failed for playlist %d: %v", playlistID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []ShareToken
+	for rows.Next() {
+		token, err := scanShareToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+const revokeShareToken = `
+	UPDATE playlist_share_tokens
+	SET revoked_at = NOW()
+	WHERE id = $1 AND playlist_id = $2 AND revoked_at IS NULL`
+
+func (r *shareTokenRepo) Revoke(ctx context.Context, playlistID, id int) (bool, error) {
+	var revoked int64
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, revokeShareToken, id, playlistID)
+		if err != nil {
+			return err
+		}
+		revoked, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		log.Printf("Revoke share token %d failed for playlist %d: %v", id, playlistID, err)
+		return false, err
+	}
+	return revoked > 0, nil
+}
+
+const getSharedPlaylistID = `
+	SELECT playlist_id
+	FROM playlist_share_tokens
+	WHERE token = $1 AND revoked_at IS NULL
+		AND (expires_at IS NULL OR expires_at > NOW())`
+
+func (r *shareTokenRepo) GetPlaylistID(ctx context.Context, token string) (int, error) {
+	var playlistID int
+	err := r.tx.DB().QueryRowContext(ctx, getSharedPlaylistID, token).Scan(&playlistID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		log.Printf("Share token lookup failed: %v", err)
+		return 0, err
+	}
+	return playlistID, nil
+}
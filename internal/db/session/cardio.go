@@ -0,0 +1,218 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+)
+
+var ErrInvalidCardioEntry = errors.New("invalid cardio entry")
+
+// applyCardioRequest copies the provided fields of req onto entry
+func applyCardioRequest(entry CardioEntry, req LogCardioRequest) CardioEntry {
+	if req.EntryNumber != nil {
+		entry.EntryNumber = *req.EntryNumber
+	}
+	if req.DurationSeconds != nil {
+		entry.DurationSeconds = req.DurationSeconds
+	}
+	if req.Distance != nil {
+		entry.Distance = req.Distance
+	}
+	if req.AvgHeartRate != nil {
+		entry.AvgHeartRate = req.AvgHeartRate
+	}
+	if req.MaxHeartRate != nil {
+		entry.MaxHeartRate = req.MaxHeartRate
+	}
+	if req.AvgPace != nil {
+		entry.AvgPace = req.AvgPace
+	}
+	if req.Incline != nil {
+		entry.Incline = req.Incline
+	}
+	if req.Laps != nil {
+		entry.Laps = req.Laps
+	}
+	return entry
+}
+
+// validateCardioEntries checks all entries of one exercise
+func validateCardioEntries(blockType playlist.BlockType, entries []CardioEntry) error {
+	if blockType != playlist.BlockTypeCardio {
+		return fmt.Errorf("%w: cardio entries can only be logged for cardio blocks", ErrInvalidCardioEntry)
+	}
+
+	seen := make(map[int]bool)
+	for _, entry := range entries {
+		if entry.EntryNumber < 1 {
+			return fmt.Errorf("%w: entry number must be at least 1", ErrInvalidCardioEntry)
+		}
+		if seen[entry.EntryNumber] {
+			return fmt.Errorf("%w: duplicate entry number %d", ErrInvalidCardioEntry, entry.EntryNumber)
+		}
+		seen[entry.EntryNumber] = true
+
+		if entry.DurationSeconds == nil && entry.Distance == nil {
+			return fmt.Errorf("%w: entry %d needs a duration or a distance", ErrInvalidCardioEntry, entry.EntryNumber)
+		}
+		if entry.DurationSeconds != nil && *entry.DurationSeconds < 0 {
+			return fmt.Errorf("%w: entry %d duration must not be negative", ErrInvalidCardioEntry, entry.EntryNumber)
+		}
+		if entry.Distance != nil && *entry.Distance < 0 {
+			return fmt.Errorf("%w: entry %d distance must not be negative", ErrInvalidCardioEntry, entry.EntryNumber)
+		}
+		if entry.AvgPace != nil && *entry.AvgPace < 0 {
+			return fmt.Errorf("%w: entry %d pace must not be negative", ErrInvalidCardioEntry, entry.EntryNumber)
+		}
+		if entry.AvgHeartRate != nil && *entry.AvgHeartRate <= 0 || entry.MaxHeartRate != nil && *entry.MaxHeartRate <= 0 {
+			return fmt.Errorf("%w: entry %d heart rate must be positive", ErrInvalidCardioEntry, entry.EntryNumber)
+		}
+		if entry.AvgHeartRate != nil && entry.MaxHeartRate != nil && *entry.AvgHeartRate > *entry.MaxHeartRate {
+			return fmt.Errorf("%w: entry %d average heart rate exceeds max heart rate", ErrInvalidCardioEntry, entry.EntryNumber)
+		}
+
+		for i, lap := range entry.Laps {
+			if lap.LapNumber != i+1 {
+				return fmt.Errorf("%w: entry %d laps must be numbered from 1 in order", ErrInvalidCardioEntry, entry.EntryNumber)
+			}
+			if lap.DurationSeconds < 0 || lap.Distance != nil && *lap.Distance < 0 {
+				return fmt.Errorf("%w: entry %d lap %d must not be negative", ErrInvalidCardioEntry, entry.EntryNumber, lap.LapNumber)
+			}
+		}
+	}
+	return nil
+}
+
+// summarizeCardio totals the entries of an exercise and compares them with
+// the targets of its config. config may be nil.
+func summarizeCardio(config *playlist.Config, entries []CardioEntry) *CardioSummary {
+	summary := &CardioSummary{}
+	if config != nil {
+		summary.Target = CardioMetrics{
+			DurationSeconds: config.DurationSeconds,
+			Distance:        config.Distance,
+			Pace:            config.TargetPace,
+			HeartRate:       config.TargetHeartRate,
+			Incline:         config.Incline,
+		}
+	}
+
+	var (
+		duration, paceDuration, hrSeconds, hrWeighted, hrSum, hrCount, paceCount, inclineCount int
+		distance, paceDistance, paceSum, inclineSum                                            float64
+		hasDuration, hasDistance                                                               bool
+	)
+	for _, entry := range entries {
+		if entry.DurationSeconds != nil {
+			duration += *entry.DurationSeconds
+			hasDuration = true
+		}
+		if entry.Distance != nil {
+			distance += *entry.Distance
+			hasDistance = true
+		}
+		// Pace only counts entries that logged both
+		if entry.DurationSeconds != nil && entry.Distance != nil {
+			paceDuration += *entry.DurationSeconds
+			paceDistance += *entry.Distance
+		}
+		if entry.AvgHeartRate != nil {
+			hrSum += *entry.AvgHeartRate
+			hrCount++
+			if entry.DurationSeconds != nil {
+				hrWeighted += *entry.AvgHeartRate * *entry.DurationSeconds
+				hrSeconds += *entry.DurationSeconds
+			}
+		}
+		if entry.MaxHeartRate != nil && (summary.MaxHeartRate == nil || *entry.MaxHeartRate > *summary.MaxHeartRate) {
+			summary.MaxHeartRate = entry.MaxHeartRate
+		}
+		if entry.AvgPace != nil {
+			paceSum += *entry.AvgPace
+			paceCount++
+		}
+		if entry.Incline != nil {
+			inclineSum += *entry.Incline
+			inclineCount++
+		}
+	}
+
+	if hasDuration {
+		summary.Actual.DurationSeconds = &duration
+	}
+	if hasDistance {
+		summary.Actual.Distance = roundPtr(distance)
+	}
+
+	// Prefer pace derived from totals over the average of logged paces
+	switch {
+	case paceDistance > 0:
+		summary.Actual.Pace = roundPtr(float64(paceDuration) / 60 / paceDistance)
+	case paceCount > 0:
+		summary.Actual.Pace = roundPtr(paceSum / float64(paceCount))
+	}
+
+	switch {
+	case hrSeconds > 0:
+		heartRate := hrWeighted / hrSeconds
+		summary.Actual.HeartRate = &heartRate
+	case hrCount > 0:
+		heartRate := hrSum / hrCount
+		summary.Actual.HeartRate = &heartRate
+	}
+
+	if inclineCount > 0 {
+		summary.Actual.Incline = roundPtr(inclineSum / float64(inclineCount))
+	}
+
+	// Deltas only where both sides are known
+	if summary.Target.DurationSeconds != nil && summary.Actual.DurationSeconds != nil {
+		delta := *summary.Actual.DurationSeconds - *summary.Target.DurationSeconds
+		summary.Delta.DurationSeconds = &delta
+	}
+	if summary.Target.Distance != nil && summary.Actual.Distance != nil {
+		summary.Delta.Distance = roundPtr(*summary.Actual.Distance - *summary.Target.Distance)
+	}
+	if summary.Target.Pace != nil && summary.Actual.Pace != nil {
+		summary.Delta.Pace = roundPtr(*summary.Actual.Pace - *summary.Target.Pace)
+	}
+	if summary.Target.HeartRate != nil && summary.Actual.HeartRate != nil {
+		delta := *summary.Actual.HeartRate - *summary.Target.HeartRate
+		summary.Delta.HeartRate = &delta
+	}
+	if summary.Target.Incline != nil && summary.Actual.Incline != nil {
+		summary.Delta.Incline = roundPtr(*summary.Actual.Incline - *summary.Target.Incline)
+	}
+
+	return summary
+}
+
+func roundPtr(value float64) *float64 {
+	rounded := math.Round(value*100) / 100
+	return &rounded
+}
+
+// snapshotPosition is where an exercise sits in a session snapshot
+type snapshotPosition struct {
+	blockOrder    int
+	exerciseOrder int
+}
+
+// snapshotConfigs maps the position of each exercise in the snapshot to its
+// config at session start. Positions outlive the playlist exercise, whose
+// ID is cleared on the session exercise once it is deleted.
+func snapshotConfigs(snapshot *playlist.Playlist) map[snapshotPosition]*playlist.Config {
+	configs := make(map[snapshotPosition]*playlist.Config)
+	if snapshot == nil {
+		return configs
+	}
+	for _, block := range snapshot.Blocks {
+		for _, exercise := range block.Exercises {
+			configs[snapshotPosition{block.BlockOrder, exercise.ExerciseOrder}] = exercise.Config
+		}
+	}
+	return configs
+}
@@ -0,0 +1,198 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type CardioEntryRepo interface {
+	Create(ctx context.Context, entry CardioEntry) (CardioEntry, error)
+	GetByID(ctx context.Context, id int) (CardioEntry, error)
+	GetExerciseEntries(ctx context.Context, sessionExerciseID int) ([]CardioEntry, error)
+	GetSessionEntries(ctx context.Context, sessionID int) ([]CardioEntry, error)
+	Update(ctx context.Context, entry CardioEntry) (CardioEntry, error)
+}
+
+type cardioEntryRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewCardioEntryRepo(db *sql.DB) CardioEntryRepo {
+	return &cardioEntryRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const cardioEntryColumns = `id, session_exercise_id, entry_number, duration_seconds, distance, avg_heart_rate,
+	max_heart_rate, avg_pace, incline, laps, created_at, updated_at`
+
+func scanCardioEntry(row rowScanner) (CardioEntry, error) {
+	var entry CardioEntry
+	var laps []byte
+	err := row.Scan(
+		&entry.ID,
+		&entry.SessionExerciseID,
+		&entry.EntryNumber,
+		&entry.DurationSeconds,
+		&entry.Distance,
+		&entry.AvgHeartRate,
+		&entry.MaxHeartRate,
+		&entry.AvgPace,
+		&entry.Incline,
+		&laps,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return CardioEntry{}, err
+	}
+
+	if len(laps) > 0 {
+		if err := json.Unmarshal(laps, &entry.Laps); err != nil {
+			return CardioEntry{}, err
+		}
+	}
+	return entry, nil
+}
+
+func scanCardioEntries(rows *sql.Rows) ([]CardioEntry, error) {
+	defer rows.Close()
+
+	var entries []CardioEntry
+	for rows.Next() {
+		entry, err := scanCardioEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func marshalLaps(laps []CardioLap) ([]byte, error) {
+	if laps == nil {
+		laps = []CardioLap{}
+	}
+	return json.Marshal(laps)
+}
+
+const createCardioEntry = `
+	INSERT INTO cardio_entries (session_exercise_id, entry_number, duration_seconds, distance, avg_heart_rate,
+		max_heart_rate, avg_pace, incline, laps)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING ` + cardioEntryColumns
+
+func (r *cardioEntryRepo) Create(ctx context.Context, entry CardioEntry) (CardioEntry, error) {
+	laps, err := marshalLaps(entry.Laps)
+	if err != nil {
+		return CardioEntry{}, err
+	}
+
+	var newEntry CardioEntry
+	err = r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		newEntry, err = scanCardioEntry(tx.QueryRowContext(ctx, createCardioEntry,
+			entry.SessionExerciseID,
+			entry.EntryNumber,
+			entry.DurationSeconds,
+			entry.Distance,
+			entry.AvgHeartRate,
+			entry.MaxHeartRate,
+			entry.AvgPace,
+			entry.Incline,
+			laps,
+		))
+		return err
+	})
+	if err != nil {
+		log.Printf("Create cardio entry failed: %v", err)
+		return CardioEntry{}, err
+	}
+	return newEntry, nil
+}
+
+const getCardioEntryByID = `SELECT ` + cardioEntryColumns + ` FROM cardio_entries WHERE id = $1`
+
+func (r *cardioEntryRepo) GetByID(ctx context.Context, id int) (CardioEntry, error) {
+	entry, err := scanCardioEntry(r.tx.DB().QueryRowContext(ctx, getCardioEntryByID, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return CardioEntry{}, nil
+		}
+		return CardioEntry{}, err
+	}
+	return entry, nil
+}
+
+const getExerciseCardioEntries = `
+	SELECT ` + cardioEntryColumns + `
+	FROM cardio_entries
+	WHERE session_exercise_id = $1
+	ORDER BY entry_number ASC`
+
+func (r *cardioEntryRepo) GetExerciseEntries(ctx context.Context, sessionExerciseID int) ([]CardioEntry, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, getExerciseCardioEntries, sessionExerciseID)
+	if err != nil {
+		return nil, err
+	}
+	return scanCardioEntries(rows)
+}
+
+const getSessionCardioEntries = `
+	SELECT ` + cardioEntryColumns + `
+	FROM cardio_entries
+	WHERE session_exercise_id IN (SELECT id FROM session_exercises WHERE session_id = $1)
+	ORDER BY session_exercise_id, entry_number ASC`
+
+func (r *cardioEntryRepo) GetSessionEntries(ctx context.Context, sessionID int) ([]CardioEntry, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, getSessionCardioEntries, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return scanCardioEntries(rows)
+}
+
+const updateCardioEntry = `
+	UPDATE cardio_entries
+	SET entry_number = $2,
+		duration_seconds = $3,
+		distance = $4,
+		avg_heart_rate = $5,
+		max_heart_rate = $6,
+		avg_pace = $7,
+		incline = $8,
+		laps = $9
+	WHERE id = $1
+	RETURNING ` + cardioEntryColumns
+
+func (r *cardioEntryRepo) Update(ctx context.Context, entry CardioEntry) (CardioEntry, error) {
+	laps, err := marshalLaps(entry.Laps)
+	if err != nil {
+		return CardioEntry{}, err
+	}
+
+	var updatedEntry CardioEntry
+	err = r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		updatedEntry, err = scanCardioEntry(tx.QueryRowContext(ctx, updateCardioEntry,
+			entry.ID,
+			entry.EntryNumber,
+			entry.DurationSeconds,
+			entry.Distance,
+			entry.AvgHeartRate,
+			entry.MaxHeartRate,
+			entry.AvgPace,
+			entry.Incline,
+			laps,
+		))
+		return err
+	})
+	if err != nil {
+		log.Printf("Update cardio entry failed for ID %d: %v", entry.ID, err)
+		return CardioEntry{}, err
+	}
+	return updatedEntry, nil
+}
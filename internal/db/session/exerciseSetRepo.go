@@ -0,0 +1,236 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type ExerciseSetRepo interface {
+	Create(ctx context.Context, set ExerciseSet) (ExerciseSet, error)
+	GetByID(ctx context.Context, id int) (ExerciseSet, error)
+	GetExerciseSets(ctx context.Context, sessionExerciseID int) ([]ExerciseSet, error)
+	GetSessionSets(ctx context.Context, sessionID int) ([]ExerciseSet, error)
+	Update(ctx context.Context, set ExerciseSet) (ExerciseSet, error)
+
+	// Insert or update many sets in a single transaction, keyed by exercise and set number
+	Upsert(ctx context.Context, sets []ExerciseSet) ([]ExerciseSet, error)
+}
+
+type exerciseSetRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewExerciseSetRepo(db *sql.DB) ExerciseSetRepo {
+	return &exerciseSetRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const setColumns = `id, session_exercise_id, set_number, set_kind, status, target_reps_min, target_reps_max,
+	target_weight, reps, weight, rpe, rir, started_at, completed_at, created_at, updated_at`
+
+func scanSet(row rowScanner) (ExerciseSet, error) {
+	var set ExerciseSet
+	err := row.Scan(
+		&set.ID,
+		&set.SessionExerciseID,
+		&set.SetNumber,
+		&set.Kind,
+		&set.Status,
+		&set.TargetRepsMin,
+		&set.TargetRepsMax,
+		&set.TargetWeight,
+		&set.Reps,
+		&set.Weight,
+		&set.RPE,
+		&set.RIR,
+		&set.StartedAt,
+		&set.CompletedAt,
+		&set.CreatedAt,
+		&set.UpdatedAt,
+	)
+	if err != nil {
+		return ExerciseSet{}, err
+	}
+	return set, nil
+}
+
+func scanSets(rows *sql.Rows) ([]ExerciseSet, error) {
+	defer rows.Close()
+
+	var sets []ExerciseSet
+	for rows.Next() {
+		set, err := scanSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+
+	return sets, rows.Err()
+}
+
+const createSet = `
+	INSERT INTO exercise_sets (session_exercise_id, set_number, set_kind, status, target_reps_min, target_reps_max,
+		target_weight, reps, weight, rpe, rir, started_at, completed_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	RETURNING ` + setColumns
+
+func (r *exerciseSetRepo) Create(ctx context.Context, set ExerciseSet) (ExerciseSet, error) {
+	var newSet ExerciseSet
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var err error
+		newSet, err = scanSet(tx.QueryRowContext(ctx, createSet,
+			set.SessionExerciseID,
+			set.SetNumber,
+			set.Kind,
+			set.Status,
+			set.TargetRepsMin,
+			set.TargetRepsMax,
+			set.TargetWeight,
+			set.Reps,
+			set.Weight,
+			set.RPE,
+			set.RIR,
+			set.StartedAt,
+			set.CompletedAt,
+		))
+		return err
+	})
+	if err != nil {
+		log.Printf("Create exercise set failed: %v", err)
+		return ExerciseSet{}, err
+	}
+	return newSet, nil
+}
+
+const getSetByID = `SELECT ` + setColumns + ` FROM exercise_sets WHERE id = $1`
+
+func (r *exerciseSetRepo) GetByID(ctx context.Context, id int) (ExerciseSet, error) {
+	set, err := scanSet(r.tx.DB().QueryRowContext(ctx, getSetByID, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ExerciseSet{}, nil
+		}
+		return ExerciseSet{}, err
+	}
+	return set, nil
+}
+
+const getExerciseSets = `
+	SELECT ` + setColumns + `
+	FROM exercise_sets
+	WHERE session_exercise_id = $1
+	ORDER BY set_number ASC`
+
+func (r *exerciseSetRepo) GetExerciseSets(ctx context.Context, sessionExerciseID int) ([]ExerciseSet, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, getExerciseSets, sessionExerciseID)
+	if err != nil {
+		return nil, err
+	}
+	return scanSets(rows)
+}
+
+const getSessionSets = `
+	SELECT ` + setColumns + `
+	FROM exercise_sets
+	WHERE session_exercise_id IN (SELECT id FROM session_exercises WHERE session_id = $1)
+	ORDER BY session_exercise_id, set_number ASC`
+
+func (r *exerciseSetRepo) GetSessionSets(ctx context.Context, sessionID int) ([]ExerciseSet, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, getSessionSets, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return scanSets(rows)
+}
+
+const updateSet = `
+	UPDATE exercise_sets
+	SET set_number = $2,
+		set_kind = $3,
+		status = $4,
+		reps = $5,
+		weight = $6,
+		rpe = $7,
+		rir = $8,
+		started_at = $9,
+		completed_at = $10
+	WHERE id = $1
+	RETURNING ` + setColumns
+
+func (r *exerciseSetRepo) Update(ctx context.Context, set ExerciseSet) (ExerciseSet, error) {
+	var updatedSet ExerciseSet
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var err error
+		updatedSet, err = scanSet(tx.QueryRowContext(ctx, updateSet,
+			set.ID,
+			set.SetNumber,
+			set.Kind,
+			set.Status,
+			set.Reps,
+			set.Weight,
+			set.RPE,
+			set.RIR,
+			set.StartedAt,
+			set.CompletedAt,
+		))
+		return err
+	})
+	if err != nil {
+		log.Printf("Update exercise set failed for ID %d: %v", set.ID, err)
+		return ExerciseSet{}, err
+	}
+	return updatedSet, nil
+}
+
+const upsertSet = `
+	INSERT INTO exercise_sets (session_exercise_id, set_number, set_kind, status, target_reps_min, target_reps_max,
+		target_weight, reps, weight, rpe, rir, started_at, completed_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	ON CONFLICT (session_exercise_id, set_number) DO UPDATE
+	SET set_kind = EXCLUDED.set_kind,
+		status = EXCLUDED.status,
+		reps = EXCLUDED.reps,
+		weight = EXCLUDED.weight,
+		rpe = EXCLUDED.rpe,
+		rir = EXCLUDED.rir,
+		started_at = EXCLUDED.started_at,
+		completed_at = EXCLUDED.completed_at
+	RETURNING ` + setColumns
+
+func (r *exerciseSetRepo) Upsert(ctx context.Context, sets []ExerciseSet) ([]ExerciseSet, error) {
+	var savedSets []ExerciseSet
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		for _, set := range sets {
+			savedSet, err := scanSet(tx.QueryRowContext(ctx, upsertSet,
+				set.SessionExerciseID,
+				set.SetNumber,
+				set.Kind,
+				set.Status,
+				set.TargetRepsMin,
+				set.TargetRepsMax,
+				set.TargetWeight,
+				set.Reps,
+				set.Weight,
+				set.RPE,
+				set.RIR,
+				set.StartedAt,
+				set.CompletedAt,
+			))
+			if err != nil {
+				return err
+			}
+			savedSets = append(savedSets, savedSet)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Upsert exercise sets failed: %v", err)
+		return nil, err
+	}
+	return savedSets, nil
+}
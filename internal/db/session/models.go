@@ -0,0 +1,190 @@
+// Package session
+package session
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+
+	logs "github.com/cheezecakee/fitrkr/internal/db/log"
+)
+
+type Status string
+
+const (
+	StatusInProgress Status = "in_progress"
+	StatusPaused     Status = "paused"
+	StatusCompleted  Status = "completed"
+)
+
+type SetKind string
+
+const (
+	SetKindWarmup  SetKind = "warmup"
+	SetKindWorking SetKind = "working"
+	SetKindDrop    SetKind = "drop"
+	SetKindFailure SetKind = "failure"
+)
+
+type SetStatus string
+
+const (
+	SetStatusPending   SetStatus = "pending"
+	SetStatusCompleted SetStatus = "completed"
+	SetStatusSkipped   SetStatus = "skipped"
+)
+
+// Session represents a single workout performed from a playlist
+type Session struct {
+	ID            int        `json:"id" db:"id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	PlaylistID    *int       `json:"playlist_id" db:"playlist_id"` // nil once the source playlist is deleted
+	Title         string     `json:"title" db:"title"`
+	Status        Status     `json:"status" db:"status"` // 'in_progress', 'paused', 'completed'
+	StartedAt     time.Time  `json:"started_at" db:"started_at"`
+	PausedAt      *time.Time `json:"paused_at" db:"paused_at"`
+	PausedSeconds int        `json:"paused_seconds" db:"paused_seconds"`
+	FinishedAt    *time.Time `json:"finished_at" db:"finished_at"`
+	Notes         *string    `json:"notes" db:"notes"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Playlist tree as it was when the session started
+	Snapshot *playlist.Playlist `json:"snapshot,omitempty" db:"snapshot"`
+
+	// Computed/joined data (not in DB)
+	DurationSeconds int               `json:"duration_seconds"`
+	Exercises       []SessionExercise `json:"exercises,omitempty"`
+	PersonalRecords []logs.Log        `json:"personal_records,omitempty"` // set when the session is finished
+}
+
+// SessionExercise is a playlist exercise as performed in a session
+type SessionExercise struct {
+	ID                 int                `json:"id" db:"id"`
+	SessionID          int                `json:"session_id" db:"session_id"`
+	PlaylistExerciseID *int               `json:"playlist_exercise_id" db:"playlist_exercise_id"`
+	ConfigID           *int               `json:"config_id" db:"config_id"`
+	ExerciseID         int                `json:"exercise_id" db:"exercise_id"`
+	ExerciseName       string             `json:"exercise_name" db:"exercise_name"`
+	BlockName          string             `json:"block_name" db:"block_name"`
+	BlockType          playlist.BlockType `json:"block_type" db:"block_type"`
+	BlockOrder         int                `json:"block_order" db:"block_order"`
+	ExerciseOrder      int                `json:"exercise_order" db:"exercise_order"`
+	CreatedAt          time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at" db:"updated_at"`
+
+	// Joined data (not in DB)
+	Sets          []ExerciseSet  `json:"sets,omitempty"`
+	CardioEntries []CardioEntry  `json:"cardio_entries,omitempty"`
+	CardioSummary *CardioSummary `json:"cardio_summary,omitempty"`
+}
+
+// ExerciseSet is a single set of a session exercise
+type ExerciseSet struct {
+	ID                int        `json:"id" db:"id"`
+	SessionExerciseID int        `json:"session_exercise_id" db:"session_exercise_id"`
+	SetNumber         int        `json:"set_number" db:"set_number"`
+	Kind              SetKind    `json:"kind" db:"set_kind"` // 'warmup', 'working', 'drop', 'failure'
+	Status            SetStatus  `json:"status" db:"status"` // 'pending', 'completed', 'skipped'
+	TargetRepsMin     *int       `json:"target_reps_min" db:"target_reps_min"`
+	TargetRepsMax     *int       `json:"target_reps_max" db:"target_reps_max"`
+	TargetWeight      *float64   `json:"target_weight" db:"target_weight"`
+	Reps              *int       `json:"reps" db:"reps"`
+	Weight            *float64   `json:"weight" db:"weight"`
+	RPE               *float64   `json:"rpe" db:"rpe" example:"8.5"`
+	RIR               *int       `json:"rir" db:"rir" example:"2"`
+	StartedAt         *time.Time `json:"started_at" db:"started_at"`
+	CompletedAt       *time.Time `json:"completed_at" db:"completed_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CardioEntry is one logged interval of a cardio exercise
+type CardioEntry struct {
+	ID                int         `json:"id" db:"id"`
+	SessionExerciseID int         `json:"session_exercise_id" db:"session_exercise_id"`
+	EntryNumber       int         `json:"entry_number" db:"entry_number"`
+	DurationSeconds   *int        `json:"duration_seconds" db:"duration_seconds" example:"600"`
+	Distance          *float64    `json:"distance" db:"distance" example:"2.5"`
+	AvgHeartRate      *int        `json:"avg_heart_rate" db:"avg_heart_rate" example:"145"`
+	MaxHeartRate      *int        `json:"max_heart_rate" db:"max_heart_rate" example:"172"`
+	AvgPace           *float64    `json:"avg_pace" db:"avg_pace" example:"4.0"` // minutes per mile/km
+	Incline           *float64    `json:"incline" db:"incline" example:"1.5"`
+	Laps              []CardioLap `json:"laps" db:"laps"`
+	CreatedAt         time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// CardioLap is a split within a cardio entry
+type CardioLap struct {
+	LapNumber       int      `json:"lap_number" example:"1"`
+	DurationSeconds int      `json:"duration_seconds" example:"300"`
+	Distance        *float64 `json:"distance,omitempty" example:"1.0"`
+}
+
+// CardioMetrics holds the comparable cardio values of a target or result
+type CardioMetrics struct {
+	DurationSeconds *int     `json:"duration_seconds"`
+	Distance        *float64 `json:"distance"`
+	Pace            *float64 `json:"pace"`
+	HeartRate       *int     `json:"heart_rate"`
+	Incline         *float64 `json:"incline"`
+}
+
+// CardioSummary compares the logged entries of an exercise with its config.
+// Delta is actual minus target; a negative pace delta means faster than target.
+type CardioSummary struct {
+	Target       CardioMetrics `json:"target"`
+	Actual       CardioMetrics `json:"actual"`
+	Delta        CardioMetrics `json:"delta"`
+	MaxHeartRate *int          `json:"max_heart_rate"`
+}
+
+// StartSessionRequest Request/Response DTOs
+type StartSessionRequest struct {
+	PlaylistID int     `json:"playlist_id" validate:"required" example:"1"`
+	Notes      *string `json:"notes,omitempty"`
+}
+
+type FinishSessionRequest struct {
+	Notes *string `json:"notes,omitempty"`
+}
+
+// LogSetRequest carries the fields of a set to create or edit.
+// Nil fields are left unchanged.
+type LogSetRequest struct {
+	SetNumber   *int       `json:"set_number,omitempty" example:"1"`
+	Kind        *SetKind   `json:"kind,omitempty" example:"working"`
+	Status      *SetStatus `json:"status,omitempty" example:"completed"`
+	Reps        *int       `json:"reps,omitempty" example:"10"`
+	Weight      *float64   `json:"weight,omitempty" example:"60.0"`
+	RPE         *float64   `json:"rpe,omitempty" example:"8.5"`
+	RIR         *int       `json:"rir,omitempty" example:"2"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// BulkSetEntry identifies a set by exercise and set number
+type BulkSetEntry struct {
+	SessionExerciseID int `json:"session_exercise_id" validate:"required" example:"12"`
+	LogSetRequest
+}
+
+type SubmitSetsRequest struct {
+	Sets []BulkSetEntry `json:"sets" validate:"required"`
+}
+
+// LogCardioRequest carries the fields of a cardio entry to create or edit.
+// Nil fields are left unchanged.
+type LogCardioRequest struct {
+	EntryNumber     *int        `json:"entry_number,omitempty" example:"1"`
+	DurationSeconds *int        `json:"duration_seconds,omitempty" example:"600"`
+	Distance        *float64    `json:"distance,omitempty" example:"2.5"`
+	AvgHeartRate    *int        `json:"avg_heart_rate,omitempty" example:"145"`
+	MaxHeartRate    *int        `json:"max_heart_rate,omitempty" example:"172"`
+	AvgPace         *float64    `json:"avg_pace,omitempty" example:"4.0"`
+	Incline         *float64    `json:"incline,omitempty" example:"1.5"`
+	Laps            []CardioLap `json:"laps,omitempty"`
+}
@@ -0,0 +1,157 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+// PersonalBests holds a user's best results for one exercise
+type PersonalBests struct {
+	ExerciseID      int
+	HeaviestWeight  *float64
+	EstimatedOneRM  *float64
+	BestVolume      *float64        // heaviest total volume in a single session
+	RepsAtWeight    map[float64]int // most reps per weight, 0 for bodyweight
+	LongestDistance *float64        // longest total distance in a single session
+	FastestPace     *float64
+}
+
+type RecordRepo interface {
+	// Bests from the user's completed sessions, excluding one session
+	GetPersonalBests(ctx context.Context, userID uuid.UUID, excludeSessionID int, exerciseIDs []int) (map[int]PersonalBests, error)
+}
+
+type recordRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewRecordRepo(db *sql.DB) RecordRepo {
+	return &recordRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+// Working sets of the user's completed sessions
+const liftHistory = `
+	WITH history AS (
+		SELECT se.exercise_id, s.id AS session_id, COALESCE(es.weight, 0) AS weight, es.reps
+		FROM exercise_sets es
+		JOIN session_exercises se ON se.id = es.session_exercise_id
+		JOIN sessions s ON s.id = se.session_id
+		WHERE s.user_id = $1 AND s.status = 'completed' AND s.id <> $2
+		  AND se.exercise_id = ANY($3)
+		  AND es.status = 'completed' AND es.set_kind <> 'warmup'
+		  AND es.reps IS NOT NULL
+	)`
+
+const getLiftBests = liftHistory + `
+	SELECT exercise_id,
+		   MAX(weight),
+		   MAX(CASE WHEN reps = 1 THEN weight ELSE weight * (1 + reps / 30.0) END),
+		   MAX(volume)
+	FROM (
+		SELECT exercise_id, session_id, weight, reps,
+			   SUM(weight * reps) OVER (PARTITION BY exercise_id, session_id) AS volume
+		FROM history
+	) h
+	GROUP BY exercise_id`
+
+const getRepsAtWeight = liftHistory + `
+	SELECT exercise_id, weight, MAX(reps)
+	FROM history
+	GROUP BY exercise_id, weight`
+
+const getCardioBests = `
+	SELECT exercise_id, MAX(distance), MIN(pace)
+	FROM (
+		SELECT se.exercise_id,
+			   SUM(ce.distance) AS distance,
+			   CASE WHEN SUM(ce.distance) > 0 AND SUM(ce.duration_seconds) > 0
+					THEN SUM(ce.duration_seconds) / 60.0 / SUM(ce.distance)
+					ELSE AVG(ce.avg_pace)
+			   END AS pace
+		FROM cardio_entries ce
+		JOIN session_exercises se ON se.id = ce.session_exercise_id
+		JOIN sessions s ON s.id = se.session_id
+		WHERE s.user_id = $1 AND s.status = 'completed' AND s.id <> $2
+		  AND se.exercise_id = ANY($3)
+		GROUP BY se.exercise_id, s.id
+	) c
+	GROUP BY exercise_id`
+
+func (r *recordRepo) GetPersonalBests(ctx context.Context, userID uuid.UUID, excludeSessionID int, exerciseIDs []int) (map[int]PersonalBests, error) {
+	bests := make(map[int]PersonalBests)
+	entry := func(exerciseID int) PersonalBests {
+		best, exists := bests[exerciseID]
+		if !exists {
+			best = PersonalBests{ExerciseID: exerciseID, RepsAtWeight: make(map[float64]int)}
+		}
+		return best
+	}
+
+	args := []any{userID, excludeSessionID, pq.Array(exerciseIDs)}
+
+	rows, err := r.tx.DB().QueryContext(ctx, getLiftBests, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var exerciseID int
+		var heaviest, oneRM, volume *float64
+		if err := rows.Scan(&exerciseID, &heaviest, &oneRM, &volume); err != nil {
+			return nil, err
+		}
+		best := entry(exerciseID)
+		best.HeaviestWeight, best.EstimatedOneRM, best.BestVolume = heaviest, oneRM, volume
+		bests[exerciseID] = best
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	repRows, err := r.tx.DB().QueryContext(ctx, getRepsAtWeight, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer repRows.Close()
+
+	for repRows.Next() {
+		var exerciseID, reps int
+		var weight float64
+		if err := repRows.Scan(&exerciseID, &weight, &reps); err != nil {
+			return nil, err
+		}
+		best := entry(exerciseID)
+		best.RepsAtWeight[weight] = reps
+		bests[exerciseID] = best
+	}
+	if err := repRows.Err(); err != nil {
+		return nil, err
+	}
+
+	cardioRows, err := r.tx.DB().QueryContext(ctx, getCardioBests, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer cardioRows.Close()
+
+	for cardioRows.Next() {
+		var exerciseID int
+		var distance, pace *float64
+		if err := cardioRows.Scan(&exerciseID, &distance, &pace); err != nil {
+			return nil, err
+		}
+		best := entry(exerciseID)
+		best.LongestDistance, best.FastestPace = distance, pace
+		bests[exerciseID] = best
+	}
+
+	return bests, cardioRows.Err()
+}
@@ -0,0 +1,214 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+
+	logs "github.com/cheezecakee/fitrkr/internal/db/log"
+)
+
+// recordPriority ranks each kind of PR for the activity feed
+var recordPriority = map[logs.RecordKind]logs.Priority{
+	logs.RecordEstimatedOneRM:   logs.PriorityLegendary,
+	logs.RecordHeaviestWeight:   logs.PriorityRare,
+	logs.RecordLongestDistance:  logs.PriorityRare,
+	logs.RecordFastestPace:      logs.PriorityRare,
+	logs.RecordBestVolume:       logs.PriorityUncommon,
+	logs.RecordMostRepsAtWeight: logs.PriorityCommon,
+}
+
+// personalRecord is a result that beats the previous best
+type personalRecord struct {
+	kind     logs.RecordKind
+	exercise SessionExercise
+	set      *ExerciseSet
+	value    float64
+	previous float64
+}
+
+// estimatedOneRM uses the Epley formula
+func estimatedOneRM(weight float64, reps int) float64 {
+	if reps == 1 {
+		return weight
+	}
+	return weight * (1 + float64(reps)/30)
+}
+
+// countsForRecords reports whether a set takes part in PR detection
+func countsForRecords(set ExerciseSet) bool {
+	return set.Status == SetStatusCompleted && set.Kind != SetKindWarmup && set.Reps != nil
+}
+
+// detectRecords compares the finished session with the user's previous bests.
+// Exercises without history are skipped, a first attempt is not a record.
+func detectRecords(session Session, bests map[int]PersonalBests) []personalRecord {
+	// The same exercise may appear in several blocks
+	var order []int
+	grouped := make(map[int][]SessionExercise)
+	for _, exercise := range session.Exercises {
+		if _, exists := grouped[exercise.ExerciseID]; !exists {
+			order = append(order, exercise.ExerciseID)
+		}
+		grouped[exercise.ExerciseID] = append(grouped[exercise.ExerciseID], exercise)
+	}
+
+	var records []personalRecord
+	for _, exerciseID := range order {
+		best, exists := bests[exerciseID]
+		if !exists {
+			continue
+		}
+		exercises := grouped[exerciseID]
+		records = append(records, detectLiftRecords(exercises, best)...)
+		records = append(records, detectCardioRecords(exercises, best)...)
+	}
+	return records
+}
+
+func detectLiftRecords(exercises []SessionExercise, best PersonalBests) []personalRecord {
+	var (
+		heaviest, oneRM, reps *personalRecord
+		volume, repsWeight    float64
+		hasSets               bool
+	)
+
+	for _, exercise := range exercises {
+		for i := range exercise.Sets {
+			set := &exercise.Sets[i]
+			if !countsForRecords(*set) {
+				continue
+			}
+			hasSets = true
+
+			weight := 0.0
+			if set.Weight != nil {
+				weight = *set.Weight
+			}
+			volume += weight * float64(*set.Reps)
+
+			if weight > 0 && best.HeaviestWeight != nil && weight > *best.HeaviestWeight &&
+				(heaviest == nil || weight > heaviest.value) {
+				heaviest = &personalRecord{kind: logs.RecordHeaviestWeight, exercise: exercise, set: set, value: weight, previous: *best.HeaviestWeight}
+			}
+
+			estimate := *roundPtr(estimatedOneRM(weight, *set.Reps))
+			if weight > 0 && best.EstimatedOneRM != nil && estimate > *roundPtr(*best.EstimatedOneRM) &&
+				(oneRM == nil || estimate > oneRM.value) {
+				oneRM = &personalRecord{kind: logs.RecordEstimatedOneRM, exercise: exercise, set: set, value: estimate, previous: *roundPtr(*best.EstimatedOneRM)}
+			}
+
+			// Only the heaviest weight with a reps record is reported
+			previousReps, exists := best.RepsAtWeight[weight]
+			if exists && *set.Reps > previousReps && (reps == nil || weight > repsWeight) {
+				repsWeight = weight
+				reps = &personalRecord{kind: logs.RecordMostRepsAtWeight, exercise: exercise, set: set, value: float64(*set.Reps), previous: float64(previousReps)}
+			}
+		}
+	}
+
+	var records []personalRecord
+	for _, record := range []*personalRecord{oneRM, heaviest, reps} {
+		if record != nil {
+			records = append(records, *record)
+		}
+	}
+
+	if hasSets && volume > 0 && best.BestVolume != nil && volume > *best.BestVolume {
+		records = append(records, personalRecord{kind: logs.RecordBestVolume, exercise: exercises[0], value: *roundPtr(volume), previous: *roundPtr(*best.BestVolume)})
+	}
+	return records
+}
+
+func detectCardioRecords(exercises []SessionExercise, best PersonalBests) []personalRecord {
+	var entries []CardioEntry
+	for _, exercise := range exercises {
+		if exercise.BlockType == playlist.BlockTypeCardio {
+			entries = append(entries, exercise.CardioEntries...)
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	actual := summarizeCardio(nil, entries).Actual
+
+	var records []personalRecord
+	if actual.Distance != nil && best.LongestDistance != nil && *actual.Distance > *roundPtr(*best.LongestDistance) {
+		records = append(records, personalRecord{kind: logs.RecordLongestDistance, exercise: exercises[0], value: *actual.Distance, previous: *roundPtr(*best.LongestDistance)})
+	}
+	if actual.Pace != nil && *actual.Pace > 0 && best.FastestPace != nil && *actual.Pace < *roundPtr(*best.FastestPace) {
+		records = append(records, personalRecord{kind: logs.RecordFastestPace, exercise: exercises[0], value: *actual.Pace, previous: *roundPtr(*best.FastestPace)})
+	}
+	return records
+}
+
+// buildRecordLogs turns detected records into PR_Achieved log rows
+func buildRecordLogs(session Session, records []personalRecord) ([]logs.Log, error) {
+	entries := make([]logs.Log, 0, len(records))
+	for _, record := range records {
+		metadata := logs.PRMetadata{
+			Record:            record.kind,
+			ExerciseID:        record.exercise.ExerciseID,
+			ExerciseName:      record.exercise.ExerciseName,
+			SessionExerciseID: record.exercise.ID,
+			Value:             record.value,
+			Previous:          record.previous,
+		}
+		if record.set != nil {
+			metadata.SetID = &record.set.ID
+			metadata.Weight = record.set.Weight
+			metadata.Reps = record.set.Reps
+		}
+
+		raw, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, logs.Log{
+			UserID:     session.UserID,
+			PlaylistID: session.PlaylistID,
+			SessionID:  &session.ID,
+			Metadata:   raw,
+			Type:       logs.TypePRAchieved,
+			Priority:   recordPriority[record.kind],
+			Message:    recordMessage(record),
+			PR:         true,
+		})
+	}
+	return entries, nil
+}
+
+func recordMessage(record personalRecord) string {
+	name := record.exercise.ExerciseName
+	value := formatNumber(record.value)
+	previous := formatNumber(record.previous)
+
+	switch record.kind {
+	case logs.RecordHeaviestWeight:
+		return fmt.Sprintf("New heaviest %s: %s (previous best %s)", name, value, previous)
+	case logs.RecordEstimatedOneRM:
+		return fmt.Sprintf("New estimated 1RM on %s: %s (previous best %s)", name, value, previous)
+	case logs.RecordMostRepsAtWeight:
+		weight := "bodyweight"
+		if record.set.Weight != nil && *record.set.Weight > 0 {
+			weight = formatNumber(*record.set.Weight)
+		}
+		return fmt.Sprintf("New rep record on %s: %s reps at %s (previous best %s)", name, value, weight, previous)
+	case logs.RecordBestVolume:
+		return fmt.Sprintf("New volume record on %s: %s (previous best %s)", name, value, previous)
+	case logs.RecordLongestDistance:
+		return fmt.Sprintf("Longest %s yet: %s (previous best %s)", name, value, previous)
+	case logs.RecordFastestPace:
+		return fmt.Sprintf("Fastest %s pace yet: %s min (previous best %s)", name, value, previous)
+	default:
+		return fmt.Sprintf("New personal record on %s", name)
+	}
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// AddCardioEntry appends a cardio interval to a session exercise
+func (s *sessionService) AddCardioEntry(ctx context.Context, sessionID, sessionExerciseID int, userID uuid.UUID, req LogCardioRequest) (CardioEntry, error) {
+	if _, err := s.validateLoggableSession(ctx, sessionID, userID); err != nil {
+		return CardioEntry{}, err
+	}
+
+	exercise, err := s.sessionExerciseRepo.GetByID(ctx, sessionExerciseID)
+	if err != nil {
+		return CardioEntry{}, err
+	}
+	if exercise.ID == 0 || exercise.SessionID != sessionID {
+		return CardioEntry{}, ErrSessionExerciseNotFound
+	}
+
+	existingEntries, err := s.cardioEntryRepo.GetExerciseEntries(ctx, sessionExerciseID)
+	if err != nil {
+		return CardioEntry{}, err
+	}
+
+	// Next entry number unless one is given
+	newEntry := CardioEntry{SessionExerciseID: sessionExerciseID, EntryNumber: 1}
+	for _, existing := range existingEntries {
+		if existing.EntryNumber >= newEntry.EntryNumber {
+			newEntry.EntryNumber = existing.EntryNumber + 1
+		}
+	}
+	newEntry = applyCardioRequest(newEntry, req)
+
+	if err := validateCardioEntries(exercise.BlockType, append(existingEntries, newEntry)); err != nil {
+		return CardioEntry{}, err
+	}
+
+	createdEntry, err := s.cardioEntryRepo.Create(ctx, newEntry)
+	if err != nil {
+		return CardioEntry{}, fmt.Errorf("failed to create cardio entry: %w", err)
+	}
+	return createdEntry, nil
+}
+
+// UpdateCardioEntry edits the provided fields of a cardio entry
+func (s *sessionService) UpdateCardioEntry(ctx context.Context, entryID int, userID uuid.UUID, req LogCardioRequest) (CardioEntry, error) {
+	entry, err := s.cardioEntryRepo.GetByID(ctx, entryID)
+	if err != nil {
+		return CardioEntry{}, err
+	}
+	if entry.ID == 0 {
+		return CardioEntry{}, ErrCardioEntryNotFound
+	}
+
+	exercise, err := s.sessionExerciseRepo.GetByID(ctx, entry.SessionExerciseID)
+	if err != nil {
+		return CardioEntry{}, err
+	}
+
+	if _, err := s.validateLoggableSession(ctx, exercise.SessionID, userID); err != nil {
+		return CardioEntry{}, err
+	}
+
+	existingEntries, err := s.cardioEntryRepo.GetExerciseEntries(ctx, exercise.ID)
+	if err != nil {
+		return CardioEntry{}, err
+	}
+
+	updatedEntry := applyCardioRequest(entry, req)
+	for i, existing := range existingEntries {
+		if existing.ID == updatedEntry.ID {
+			existingEntries[i] = updatedEntry
+		}
+	}
+
+	if err := validateCardioEntries(exercise.BlockType, existingEntries); err != nil {
+		return CardioEntry{}, err
+	}
+
+	return s.cardioEntryRepo.Update(ctx, updatedEntry)
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type SessionExerciseRepo interface {
+	GetByID(ctx context.Context, id int) (SessionExercise, error)
+	GetSessionExercises(ctx context.Context, sessionID int) ([]SessionExercise, error)
+}
+
+type sessionExerciseRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewSessionExerciseRepo(db *sql.DB) SessionExerciseRepo {
+	return &sessionExerciseRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const getSessionExerciseByID = `
+	SELECT id, session_id, playlist_exercise_id, config_id, exercise_id, exercise_name,
+		   COALESCE(block_name, ''), block_type, block_order, exercise_order, created_at, updated_at
+	FROM session_exercises
+	WHERE id = $1`
+
+func (r *sessionExerciseRepo) GetByID(ctx context.Context, id int) (SessionExercise, error) {
+	var exercise SessionExercise
+	err := r.tx.DB().QueryRowContext(ctx, getSessionExerciseByID, id).Scan(
+		&exercise.ID,
+		&exercise.SessionID,
+		&exercise.PlaylistExerciseID,
+		&exercise.ConfigID,
+		&exercise.ExerciseID,
+		&exercise.ExerciseName,
+		&exercise.BlockName,
+		&exercise.BlockType,
+		&exercise.BlockOrder,
+		&exercise.ExerciseOrder,
+		&exercise.CreatedAt,
+		&exercise.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return SessionExercise{}, nil
+		}
+		return SessionExercise{}, err
+	}
+	return exercise, nil
+}
+
+const getSessionExercises = `
+	SELECT id, session_id, playlist_exercise_id, config_id, exercise_id, exercise_name,
+		   COALESCE(block_name, ''), block_type, block_order, exercise_order, created_at, updated_at
+	FROM session_exercises
+	WHERE session_id = $1
+	ORDER BY block_order ASC, exercise_order ASC`
+
+func (r *sessionExerciseRepo) GetSessionExercises(ctx context.Context, sessionID int) ([]SessionExercise, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, getSessionExercises, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exercises []SessionExercise
+	for rows.Next() {
+		var exercise SessionExercise
+		err := rows.Scan(
+			&exercise.ID,
+			&exercise.SessionID,
+			&exercise.PlaylistExerciseID,
+			&exercise.ConfigID,
+			&exercise.ExerciseID,
+			&exercise.ExerciseName,
+			&exercise.BlockName,
+			&exercise.BlockType,
+			&exercise.BlockOrder,
+			&exercise.ExerciseOrder,
+			&exercise.CreatedAt,
+			&exercise.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+
+	return exercises, rows.Err()
+}
@@ -0,0 +1,310 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+
+	logs "github.com/cheezecakee/fitrkr/internal/db/log"
+)
+
+type SessionRepo interface {
+	// Creates the session together with its exercises and sets
+	Create(ctx context.Context, session Session) (Session, error)
+	GetByID(ctx context.Context, id int) (Session, error)
+	GetActiveSession(ctx context.Context, userID uuid.UUID) (Session, error)
+	// GetUserSessions returns the page after the given session, newest first
+	GetUserSessions(ctx context.Context, userID uuid.UUID, after *Session, limit int) ([]Session, error)
+	CountUserSessions(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// State transitions
+	Pause(ctx context.Context, id int) (Session, error)
+	Resume(ctx context.Context, id int) (Session, error)
+	// Finish writes records, the PR logs of the session, in the same
+	// transaction and returns them as PersonalRecords
+	Finish(ctx context.Context, id int, notes *string, records []logs.Log) (Session, error)
+}
+
+type sessionRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewSessionRepo(db *sql.DB) SessionRepo {
+	return &sessionRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+const sessionColumns = `id, user_id, playlist_id, title, status, started_at, paused_at, paused_seconds, finished_at, notes, created_at, updated_at`
+
+func scanSession(row rowScanner, extra ...any) (Session, error) {
+	var session Session
+	dest := []any{
+		&session.ID,
+		&session.UserID,
+		&session.PlaylistID,
+		&session.Title,
+		&session.Status,
+		&session.StartedAt,
+		&session.PausedAt,
+		&session.PausedSeconds,
+		&session.FinishedAt,
+		&session.Notes,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return Session{}, err
+	}
+	return session, nil
+}
+
+const createSession = `
+	INSERT INTO sessions (user_id, playlist_id, title, status, snapshot, notes)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING ` + sessionColumns
+
+const createSessionExercise = `
+	INSERT INTO session_exercises (session_id, playlist_exercise_id, config_id, exercise_id, exercise_name,
+		block_name, block_type, block_order, exercise_order)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING id, created_at, updated_at`
+
+const createExerciseSet = `
+	INSERT INTO exercise_sets (session_exercise_id, set_number, target_reps_min, target_reps_max, target_weight)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, set_kind, status, created_at, updated_at`
+
+func (r *sessionRepo) Create(ctx context.Context, session Session) (Session, error) {
+	snapshot, err := json.Marshal(session.Snapshot)
+	if err != nil {
+		return Session{}, err
+	}
+
+	var newSession Session
+	err = r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, createSession,
+			session.UserID,
+			session.PlaylistID,
+			session.Title,
+			StatusInProgress,
+			snapshot,
+			session.Notes,
+		)
+		newSession, err = scanSession(row)
+		if err != nil {
+			return err
+		}
+
+		for _, exercise := range session.Exercises {
+			exercise.SessionID = newSession.ID
+			err := tx.QueryRowContext(ctx, createSessionExercise,
+				exercise.SessionID,
+				exercise.PlaylistExerciseID,
+				exercise.ConfigID,
+				exercise.ExerciseID,
+				exercise.ExerciseName,
+				exercise.BlockName,
+				exercise.BlockType,
+				exercise.BlockOrder,
+				exercise.ExerciseOrder,
+			).Scan(&exercise.ID, &exercise.CreatedAt, &exercise.UpdatedAt)
+			if err != nil {
+				return err
+			}
+
+			sets := exercise.Sets
+			exercise.Sets = nil
+			for _, set := range sets {
+				set.SessionExerciseID = exercise.ID
+				err := tx.QueryRowContext(ctx, createExerciseSet,
+					set.SessionExerciseID,
+					set.SetNumber,
+					set.TargetRepsMin,
+					set.TargetRepsMax,
+					set.TargetWeight,
+				).Scan(&set.ID, &set.Kind, &set.Status, &set.CreatedAt, &set.UpdatedAt)
+				if err != nil {
+					return err
+				}
+				exercise.Sets = append(exercise.Sets, set)
+			}
+
+			newSession.Exercises = append(newSession.Exercises, exercise)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Create session failed: %v", err)
+		return Session{}, err
+	}
+
+	newSession.Snapshot = session.Snapshot
+	return newSession, nil
+}
+
+const getSessionByID = `SELECT ` + sessionColumns + `, snapshot FROM sessions WHERE id = $1`
+
+func (r *sessionRepo) GetByID(ctx context.Context, id int) (Session, error) {
+	var snapshot []byte
+	session, err := scanSession(r.tx.DB().QueryRowContext(ctx, getSessionByID, id), &snapshot)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Session{}, nil
+		}
+		return Session{}, err
+	}
+
+	if len(snapshot) > 0 {
+		if err := json.Unmarshal(snapshot, &session.Snapshot); err != nil {
+			return Session{}, err
+		}
+	}
+
+	return session, nil
+}
+
+const getActiveSession = `
+	SELECT ` + sessionColumns + `
+	FROM sessions
+	WHERE user_id = $1 AND status <> 'completed'
+	ORDER BY started_at DESC
+	LIMIT 1`
+
+func (r *sessionRepo) GetActiveSession(ctx context.Context, userID uuid.UUID) (Session, error) {
+	session, err := scanSession(r.tx.DB().QueryRowContext(ctx, getActiveSession, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Session{}, nil
+		}
+		return Session{}, err
+	}
+	return session, nil
+}
+
+const getUserSessions = `
+	SELECT ` + sessionColumns + `
+	FROM sessions
+	WHERE user_id = $1
+		AND ($2::timestamp IS NULL OR (started_at, id) < ($2, $3))
+	ORDER BY started_at DESC, id DESC
+	LIMIT $4`
+
+func (r *sessionRepo) GetUserSessions(ctx context.Context, userID uuid.UUID, after *Session, limit int) ([]Session, error) {
+	var afterStartedAt *time.Time
+	var afterID int
+	if after != nil {
+		afterStartedAt, afterID = &after.StartedAt, after.ID
+	}
+
+	rows, err := r.tx.DB().QueryContext(ctx, getUserSessions, userID, afterStartedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+const countUserSessions = `SELECT COUNT(*) FROM sessions WHERE user_id = $1`
+
+func (r *sessionRepo) CountUserSessions(ctx context.Context, userID uuid.UUID) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countUserSessions, userID).Scan(&total)
+	return total, err
+}
+
+const pauseSession = `
+	UPDATE sessions
+	SET status = 'paused',
+		paused_at = NOW()
+	WHERE id = $1 AND status = 'in_progress'
+	RETURNING ` + sessionColumns
+
+func (r *sessionRepo) Pause(ctx context.Context, id int) (Session, error) {
+	return r.transition(ctx, pauseSession, id)
+}
+
+const resumeSession = `
+	UPDATE sessions
+	SET status = 'in_progress',
+		paused_seconds = paused_seconds + EXTRACT(EPOCH FROM (NOW() - paused_at))::INT,
+		paused_at = NULL
+	WHERE id = $1 AND status = 'paused'
+	RETURNING ` + sessionColumns
+
+func (r *sessionRepo) Resume(ctx context.Context, id int) (Session, error) {
+	return r.transition(ctx, resumeSession, id)
+}
+
+const finishSession = `
+	UPDATE sessions
+	SET status = 'completed',
+		paused_seconds = paused_seconds + COALESCE(EXTRACT(EPOCH FROM (NOW() - paused_at))::INT, 0),
+		paused_at = NULL,
+		finished_at = NOW(),
+		notes = COALESCE($2, notes)
+	WHERE id = $1 AND status <> 'completed'
+	RETURNING ` + sessionColumns
+
+func (r *sessionRepo) Finish(ctx context.Context, id int, notes *string, records []logs.Log) (Session, error) {
+	var finished Session
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var err error
+		finished, err = scanSession(tx.QueryRowContext(ctx, finishSession, id, notes))
+		if err != nil {
+			return err
+		}
+
+		if len(records) > 0 {
+			finished.PersonalRecords, err = logs.InsertLogs(ctx, tx, records)
+		}
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Session{}, nil
+		}
+		log.Printf("Finish session failed for ID %d: %v", id, err)
+		return Session{}, err
+	}
+	return finished, nil
+}
+
+// transition runs a guarded status update. A zero Session means the
+// session was not in a state that allows the transition.
+func (r *sessionRepo) transition(ctx context.Context, query string, id int, args ...any) (Session, error) {
+	var updatedSession Session
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var err error
+		updatedSession, err = scanSession(tx.QueryRowContext(ctx, query, append([]any{id}, args...)...))
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Session{}, nil
+		}
+		log.Printf("Session transition failed for ID %d: %v", id, err)
+		return Session{}, err
+	}
+	return updatedSession, nil
+}
@@ -0,0 +1,347 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+	"github.com/cheezecakee/fitrkr/internal/db/user"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
+
+	logs "github.com/cheezecakee/fitrkr/internal/db/log"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrUnauthorizedAccess  = errors.New("unauthorized access to session")
+	ErrActiveSessionExists = errors.New("an unfinished session already exists")
+	ErrInvalidTransition   = errors.New("session cannot change to the requested state")
+	ErrSessionCompleted    = errors.New("session is already completed")
+	ErrEmptyPlaylist       = errors.New("playlist has no exercises")
+
+	ErrSessionExerciseNotFound = errors.New("session exercise not found")
+	ErrSetNotFound             = errors.New("set not found")
+	ErrCardioEntryNotFound     = errors.New("cardio entry not found")
+)
+
+type SessionService interface {
+	// Lifecycle
+	StartSession(ctx context.Context, userID uuid.UUID, req StartSessionRequest) (Session, error)
+	PauseSession(ctx context.Context, id int, userID uuid.UUID) (Session, error)
+	ResumeSession(ctx context.Context, id int, userID uuid.UUID) (Session, error)
+	FinishSession(ctx context.Context, id int, userID uuid.UUID, req FinishSessionRequest) (Session, error)
+
+	// Set logging
+	AddSet(ctx context.Context, sessionID, sessionExerciseID int, userID uuid.UUID, req LogSetRequest) (ExerciseSet, error)
+	UpdateSet(ctx context.Context, setID int, userID uuid.UUID, req LogSetRequest) (ExerciseSet, error)
+	SubmitSets(ctx context.Context, sessionID int, userID uuid.UUID, req SubmitSetsRequest) ([]ExerciseSet, error)
+
+	// Cardio logging
+	AddCardioEntry(ctx context.Context, sessionID, sessionExerciseID int, userID uuid.UUID, req LogCardioRequest) (CardioEntry, error)
+	UpdateCardioEntry(ctx context.Context, entryID int, userID uuid.UUID, req LogCardioRequest) (CardioEntry, error)
+
+	// Queries
+	GetSession(ctx context.Context, id int, userID uuid.UUID) (Session, error)
+	GetActiveSession(ctx context.Context, userID uuid.UUID) (Session, error)
+	GetUserSessions(ctx context.Context, userID uuid.UUID, page pagination.Request) (pagination.Page[Session], error)
+
+	// Validation helpers
+	ValidateSessionAccess(ctx context.Context, sessionID int, userID uuid.UUID) (Session, error)
+}
+
+type sessionService struct {
+	sessionRepo         SessionRepo
+	sessionExerciseRepo SessionExerciseRepo
+	exerciseSetRepo     ExerciseSetRepo
+	cardioEntryRepo     CardioEntryRepo
+	recordRepo          RecordRepo
+	playlistSvc         playlist.PlaylistService
+	accountPolicy       user.AccountPolicy
+}
+
+func NewSessionService(
+	sessionRepo SessionRepo,
+	sessionExerciseRepo SessionExerciseRepo,
+	exerciseSetRepo ExerciseSetRepo,
+	cardioEntryRepo CardioEntryRepo,
+	recordRepo RecordRepo,
+	playlistSvc playlist.PlaylistService,
+	accountPolicy user.AccountPolicy,
+) SessionService {
+	return &sessionService{
+		sessionRepo:         sessionRepo,
+		sessionExerciseRepo: sessionExerciseRepo,
+		exerciseSetRepo:     exerciseSetRepo,
+		cardioEntryRepo:     cardioEntryRepo,
+		recordRepo:          recordRepo,
+		playlistSvc:         playlistSvc,
+		accountPolicy:       accountPolicy,
+	}
+}
+
+// StartSession snapshots the playlist and creates a session with one
+// exercise per playlist exercise and one set per configured set
+func (s *sessionService) StartSession(ctx context.Context, userID uuid.UUID, req StartSessionRequest) (Session, error) {
+	if err := s.accountPolicy.CheckVerified(ctx, userID); err != nil {
+		return Session{}, err
+	}
+
+	active, err := s.sessionRepo.GetActiveSession(ctx, userID)
+	if err != nil {
+		return Session{}, err
+	}
+	if active.ID != 0 {
+		return Session{}, ErrActiveSessionExists
+	}
+
+	// Full playlist tree, access is checked by the playlist service
+	snapshot, err := s.playlistSvc.GetPlaylistForSession(ctx, req.PlaylistID, userID)
+	if err != nil {
+		return Session{}, err
+	}
+
+	exercises := buildSessionExercises(snapshot)
+	if len(exercises) == 0 {
+		return Session{}, ErrEmptyPlaylist
+	}
+
+	newSession := Session{
+		UserID:     userID,
+		PlaylistID: &snapshot.ID,
+		Title:      snapshot.Title,
+		Notes:      req.Notes,
+		Snapshot:   &snapshot,
+		Exercises:  exercises,
+	}
+
+	createdSession, err := s.sessionRepo.Create(ctx, newSession)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return Session{}, ErrActiveSessionExists
+		}
+		return Session{}, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	createdSession.DurationSeconds = sessionDuration(createdSession, time.Now())
+	return createdSession, nil
+}
+
+// PauseSession pauses an in-progress session
+func (s *sessionService) PauseSession(ctx context.Context, id int, userID uuid.UUID) (Session, error) {
+	session, err := s.ValidateSessionAccess(ctx, id, userID)
+	if err != nil {
+		return Session{}, err
+	}
+
+	if session.Status != StatusInProgress {
+		return Session{}, ErrInvalidTransition
+	}
+
+	return s.applyTransition(s.sessionRepo.Pause(ctx, id))
+}
+
+// ResumeSession resumes a paused session
+func (s *sessionService) ResumeSession(ctx context.Context, id int, userID uuid.UUID) (Session, error) {
+	session, err := s.ValidateSessionAccess(ctx, id, userID)
+	if err != nil {
+		return Session{}, err
+	}
+
+	if session.Status != StatusPaused {
+		return Session{}, ErrInvalidTransition
+	}
+
+	return s.applyTransition(s.sessionRepo.Resume(ctx, id))
+}
+
+// FinishSession completes a session, records any personal records in the
+// activity log and returns the full session tree. Records are detected
+// first and written in the same transaction as the finish, so a finished
+// session never misses its PR logs.
+func (s *sessionService) FinishSession(ctx context.Context, id int, userID uuid.UUID, req FinishSessionRequest) (Session, error) {
+	session, err := s.GetSession(ctx, id, userID)
+	if err != nil {
+		return Session{}, err
+	}
+
+	if session.Status == StatusCompleted {
+		return Session{}, ErrSessionCompleted
+	}
+
+	records, err := s.personalRecordLogs(ctx, session)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to detect personal records: %w", err)
+	}
+
+	result, err := s.applyTransition(s.sessionRepo.Finish(ctx, id, req.Notes, records))
+	if err != nil {
+		return Session{}, err
+	}
+
+	finished, err := s.GetSession(ctx, id, userID)
+	if err != nil {
+		return Session{}, err
+	}
+	finished.PersonalRecords = result.PersonalRecords
+
+	return finished, nil
+}
+
+// GetSession returns a session with its exercises, sets and cardio entries.
+// Cardio exercises are summarized against the targets of the snapshot.
+func (s *sessionService) GetSession(ctx context.Context, id int, userID uuid.UUID) (Session, error) {
+	session, err := s.ValidateSessionAccess(ctx, id, userID)
+	if err != nil {
+		return Session{}, err
+	}
+
+	exercises, err := s.sessionExerciseRepo.GetSessionExercises(ctx, id)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to get session exercises: %w", err)
+	}
+
+	sets, err := s.exerciseSetRepo.GetSessionSets(ctx, id)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to get session sets: %w", err)
+	}
+
+	entries, err := s.cardioEntryRepo.GetSessionEntries(ctx, id)
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to get session cardio entries: %w", err)
+	}
+
+	// Group sets and entries by exercise
+	setMap := make(map[int][]ExerciseSet)
+	for _, set := range sets {
+		setMap[set.SessionExerciseID] = append(setMap[set.SessionExerciseID], set)
+	}
+
+	entryMap := make(map[int][]CardioEntry)
+	for _, entry := range entries {
+		entryMap[entry.SessionExerciseID] = append(entryMap[entry.SessionExerciseID], entry)
+	}
+
+	configs := snapshotConfigs(session.Snapshot)
+	for i, exercise := range exercises {
+		exercises[i].Sets = setMap[exercise.ID]
+		if exercise.BlockType != playlist.BlockTypeCardio {
+			continue
+		}
+
+		exercises[i].CardioEntries = entryMap[exercise.ID]
+		config := configs[snapshotPosition{exercise.BlockOrder, exercise.ExerciseOrder}]
+		exercises[i].CardioSummary = summarizeCardio(config, exercises[i].CardioEntries)
+	}
+
+	session.Exercises = exercises
+	return session, nil
+}
+
+// GetActiveSession returns the user's unfinished session, if any
+func (s *sessionService) GetActiveSession(ctx context.Context, userID uuid.UUID) (Session, error) {
+	active, err := s.sessionRepo.GetActiveSession(ctx, userID)
+	if err != nil {
+		return Session{}, err
+	}
+
+	if active.ID == 0 {
+		return Session{}, ErrSessionNotFound
+	}
+
+	return s.GetSession(ctx, active.ID, userID)
+}
+
+// GetUserSessions returns a page of the user's session history without
+// exercises, most recently started first
+func (s *sessionService) GetUserSessions(ctx context.Context, userID uuid.UUID, page pagination.Request) (pagination.Page[Session], error) {
+	var after *Session
+	if page.Cursor != "" {
+		after = &Session{}
+		if err := pagination.Decode(page.Cursor, &after.StartedAt, &after.ID); err != nil {
+			return pagination.Page[Session]{}, err
+		}
+	}
+
+	size := page.PageSize()
+	sessions, err := s.sessionRepo.GetUserSessions(ctx, userID, after, size+1)
+	if err != nil {
+		return pagination.Page[Session]{}, err
+	}
+	total, err := s.sessionRepo.CountUserSessions(ctx, userID)
+	if err != nil {
+		return pagination.Page[Session]{}, err
+	}
+
+	result := pagination.NewPage(sessions, size, total, func(session Session) string {
+		return pagination.Encode(session.StartedAt, session.ID)
+	})
+
+	now := time.Now()
+	for i := range result.Items {
+		result.Items[i].DurationSeconds = sessionDuration(result.Items[i], now)
+	}
+
+	return result, nil
+}
+
+// ValidateSessionAccess checks if user owns the session
+func (s *sessionService) ValidateSessionAccess(ctx context.Context, sessionID int, userID uuid.UUID) (Session, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return Session{}, err
+	}
+
+	if session.ID == 0 {
+		return Session{}, ErrSessionNotFound
+	}
+
+	if session.UserID != userID {
+		return Session{}, ErrUnauthorizedAccess
+	}
+
+	session.DurationSeconds = sessionDuration(session, time.Now())
+	return session, nil
+}
+
+// personalRecordLogs detects the PRs of a session that is being finished
+// and builds their log rows
+func (s *sessionService) personalRecordLogs(ctx context.Context, session Session) ([]logs.Log, error) {
+	var exerciseIDs []int
+	for _, exercise := range session.Exercises {
+		exerciseIDs = append(exerciseIDs, exercise.ExerciseID)
+	}
+	if len(exerciseIDs) == 0 {
+		return nil, nil
+	}
+
+	bests, err := s.recordRepo.GetPersonalBests(ctx, session.UserID, session.ID, exerciseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	records := detectRecords(session, bests)
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	return buildRecordLogs(session, records)
+}
+
+// applyTransition maps the result of a guarded status update
+func (s *sessionService) applyTransition(session Session, err error) (Session, error) {
+	if err != nil {
+		return Session{}, err
+	}
+
+	// Status changed between the check and the update
+	if session.ID == 0 {
+		return Session{}, ErrInvalidTransition
+	}
+
+	session.DurationSeconds = sessionDuration(session, time.Now())
+	return session, nil
+}
@@ -0,0 +1,222 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AddSet appends a set to a session exercise
+func (s *sessionService) AddSet(ctx context.Context, sessionID, sessionExerciseID int, userID uuid.UUID, req LogSetRequest) (ExerciseSet, error) {
+	if _, err := s.validateLoggableSession(ctx, sessionID, userID); err != nil {
+		return ExerciseSet{}, err
+	}
+
+	exercise, err := s.sessionExerciseRepo.GetByID(ctx, sessionExerciseID)
+	if err != nil {
+		return ExerciseSet{}, err
+	}
+	if exercise.ID == 0 || exercise.SessionID != sessionID {
+		return ExerciseSet{}, ErrSessionExerciseNotFound
+	}
+
+	existingSets, err := s.exerciseSetRepo.GetExerciseSets(ctx, sessionExerciseID)
+	if err != nil {
+		return ExerciseSet{}, err
+	}
+
+	newSet := newSetAfter(sessionExerciseID, existingSets)
+	newSet = stampCompletion(applySetRequest(newSet, req), time.Now())
+
+	if err := validateExerciseSets(exercise.BlockType, sortSets(append(existingSets, newSet))); err != nil {
+		return ExerciseSet{}, err
+	}
+
+	createdSet, err := s.exerciseSetRepo.Create(ctx, newSet)
+	if err != nil {
+		return ExerciseSet{}, fmt.Errorf("failed to create set: %w", err)
+	}
+	return createdSet, nil
+}
+
+// UpdateSet edits the provided fields of a set
+func (s *sessionService) UpdateSet(ctx context.Context, setID int, userID uuid.UUID, req LogSetRequest) (ExerciseSet, error) {
+	set, err := s.exerciseSetRepo.GetByID(ctx, setID)
+	if err != nil {
+		return ExerciseSet{}, err
+	}
+	if set.ID == 0 {
+		return ExerciseSet{}, ErrSetNotFound
+	}
+
+	exercise, err := s.sessionExerciseRepo.GetByID(ctx, set.SessionExerciseID)
+	if err != nil {
+		return ExerciseSet{}, err
+	}
+
+	if _, err := s.validateLoggableSession(ctx, exercise.SessionID, userID); err != nil {
+		return ExerciseSet{}, err
+	}
+
+	existingSets, err := s.exerciseSetRepo.GetExerciseSets(ctx, exercise.ID)
+	if err != nil {
+		return ExerciseSet{}, err
+	}
+
+	updatedSet := stampCompletion(applySetRequest(set, req), time.Now())
+	for i, existing := range existingSets {
+		if existing.ID == updatedSet.ID {
+			existingSets[i] = updatedSet
+		}
+	}
+
+	if err := validateExerciseSets(exercise.BlockType, sortSets(existingSets)); err != nil {
+		return ExerciseSet{}, err
+	}
+
+	return s.exerciseSetRepo.Update(ctx, updatedSet)
+}
+
+// SubmitSets creates or updates many sets of a session at once. Sets are
+// matched by exercise and set number and saved in a single transaction.
+func (s *sessionService) SubmitSets(ctx context.Context, sessionID int, userID uuid.UUID, req SubmitSetsRequest) ([]ExerciseSet, error) {
+	if _, err := s.validateLoggableSession(ctx, sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	if len(req.Sets) == 0 {
+		return nil, fmt.Errorf("%w: at least one set is required", ErrInvalidSet)
+	}
+
+	exercises, err := s.sessionExerciseRepo.GetSessionExercises(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sets, err := s.exerciseSetRepo.GetSessionSets(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	exerciseMap := make(map[int]SessionExercise)
+	for _, exercise := range exercises {
+		exerciseMap[exercise.ID] = exercise
+	}
+
+	setMap := make(map[int][]ExerciseSet)
+	for _, set := range sets {
+		setMap[set.SessionExerciseID] = append(setMap[set.SessionExerciseID], set)
+	}
+
+	// Merge submitted entries into the existing sets
+	now := time.Now()
+	var changed []ExerciseSet
+	submitted := make(map[[2]int]bool, len(req.Sets))
+	for _, entry := range req.Sets {
+		if _, exists := exerciseMap[entry.SessionExerciseID]; !exists {
+			return nil, ErrSessionExerciseNotFound
+		}
+		if entry.SetNumber == nil {
+			return nil, fmt.Errorf("%w: set number is required", ErrInvalidSet)
+		}
+
+		// A set may only appear once, or the later entry would overwrite
+		// the earlier one
+		key := [2]int{entry.SessionExerciseID, *entry.SetNumber}
+		if submitted[key] {
+			return nil, fmt.Errorf("%w: set %d of exercise %d is submitted more than once", ErrInvalidSet, *entry.SetNumber, entry.SessionExerciseID)
+		}
+		submitted[key] = true
+
+		exerciseSets := setMap[entry.SessionExerciseID]
+		index := slices.IndexFunc(exerciseSets, func(set ExerciseSet) bool {
+			return set.SetNumber == *entry.SetNumber
+		})
+
+		var set ExerciseSet
+		if index >= 0 {
+			set = exerciseSets[index]
+		} else {
+			set = newSetAfter(entry.SessionExerciseID, exerciseSets)
+		}
+		set = stampCompletion(applySetRequest(set, entry.LogSetRequest), now)
+
+		if index >= 0 {
+			exerciseSets[index] = set
+		} else {
+			exerciseSets = append(exerciseSets, set)
+		}
+		setMap[entry.SessionExerciseID] = exerciseSets
+
+		changed = append(changed, set)
+	}
+
+	// Validate every touched exercise as a whole
+	for exerciseID, exerciseSets := range setMap {
+		if !slices.ContainsFunc(changed, func(set ExerciseSet) bool { return set.SessionExerciseID == exerciseID }) {
+			continue
+		}
+		if err := validateExerciseSets(exerciseMap[exerciseID].BlockType, sortSets(exerciseSets)); err != nil {
+			return nil, fmt.Errorf("%w (exercise %s)", err, exerciseMap[exerciseID].ExerciseName)
+		}
+	}
+
+	savedSets, err := s.exerciseSetRepo.Upsert(ctx, changed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save sets: %w", err)
+	}
+	return savedSets, nil
+}
+
+// validateLoggableSession checks access and that sets can still be logged
+func (s *sessionService) validateLoggableSession(ctx context.Context, sessionID int, userID uuid.UUID) (Session, error) {
+	session, err := s.ValidateSessionAccess(ctx, sessionID, userID)
+	if err != nil {
+		return Session{}, err
+	}
+
+	if session.Status == StatusCompleted {
+		return Session{}, ErrSessionCompleted
+	}
+
+	if err := s.accountPolicy.CheckVerified(ctx, userID); err != nil {
+		return Session{}, err
+	}
+
+	return session, nil
+}
+
+// newSetAfter returns a set numbered after the existing ones that carries
+// over the targets of the last set
+func newSetAfter(sessionExerciseID int, existingSets []ExerciseSet) ExerciseSet {
+	set := ExerciseSet{SessionExerciseID: sessionExerciseID, SetNumber: 1}
+	for _, existing := range existingSets {
+		if existing.SetNumber >= set.SetNumber {
+			set.SetNumber = existing.SetNumber + 1
+			set.TargetRepsMin = existing.TargetRepsMin
+			set.TargetRepsMax = existing.TargetRepsMax
+			set.TargetWeight = existing.TargetWeight
+		}
+	}
+	return set
+}
+
+// stampCompletion keeps completed_at in line with the set status
+func stampCompletion(set ExerciseSet, now time.Time) ExerciseSet {
+	if set.Status != SetStatusCompleted {
+		set.CompletedAt = nil
+	} else if set.CompletedAt == nil {
+		set.CompletedAt = &now
+	}
+	return set
+}
+
+func sortSets(sets []ExerciseSet) []ExerciseSet {
+	slices.SortFunc(sets, func(a, b ExerciseSet) int {
+		return a.SetNumber - b.SetNumber
+	})
+	return sets
+}
@@ -0,0 +1,74 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+)
+
+func isUniqueConstraintError(err error) bool {
+	// For PostgreSQL with pgx driver
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505" // unique_violation
+	}
+	return false
+}
+
+// buildSessionExercises flattens the playlist tree into session exercises
+// with pre-created sets based on each exercise config, except in cardio
+// blocks
+func buildSessionExercises(snapshot playlist.Playlist) []SessionExercise {
+	var exercises []SessionExercise
+	for _, block := range snapshot.Blocks {
+		for _, playlistExercise := range block.Exercises {
+			exercise := SessionExercise{
+				PlaylistExerciseID: &playlistExercise.ID,
+				ConfigID:           &playlistExercise.ConfigID,
+				ExerciseID:         playlistExercise.ExerciseID,
+				ExerciseName:       playlistExercise.ExerciseName,
+				BlockName:          block.Name,
+				BlockType:          block.BlockType,
+				BlockOrder:         block.BlockOrder,
+				ExerciseOrder:      playlistExercise.ExerciseOrder,
+			}
+
+			// Cardio blocks are logged with cardio entries instead of sets
+			config := playlistExercise.Config
+			if config != nil && config.Sets != nil && block.BlockType != playlist.BlockTypeCardio {
+				for i := 1; i <= *config.Sets; i++ {
+					exercise.Sets = append(exercise.Sets, ExerciseSet{
+						SetNumber:     i,
+						TargetRepsMin: config.RepsMin,
+						TargetRepsMax: config.RepsMax,
+						TargetWeight:  config.Weight,
+					})
+				}
+			}
+
+			exercises = append(exercises, exercise)
+		}
+	}
+	return exercises
+}
+
+// sessionDuration returns the active time of a session in seconds,
+// excluding time spent paused
+func sessionDuration(session Session, now time.Time) int {
+	end := now
+	switch {
+	case session.FinishedAt != nil:
+		end = *session.FinishedAt
+	case session.PausedAt != nil:
+		end = *session.PausedAt
+	}
+
+	duration := int(end.Sub(session.StartedAt).Seconds()) - session.PausedSeconds
+	if duration < 0 {
+		return 0
+	}
+	return duration
+}
@@ -0,0 +1,133 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cheezecakee/fitrkr/internal/db/playlist"
+)
+
+var ErrInvalidSet = errors.New("invalid set")
+
+// Validation methods for custom types
+func (k SetKind) IsValid() bool {
+	switch k {
+	case SetKindWarmup, SetKindWorking, SetKindDrop, SetKindFailure:
+		return true
+	}
+	return false
+}
+
+func (s SetStatus) IsValid() bool {
+	switch s {
+	case SetStatusPending, SetStatusCompleted, SetStatusSkipped:
+		return true
+	}
+	return false
+}
+
+// applySetRequest copies the provided fields of req onto set
+func applySetRequest(set ExerciseSet, req LogSetRequest) ExerciseSet {
+	if req.SetNumber != nil {
+		set.SetNumber = *req.SetNumber
+	}
+	if req.Kind != nil {
+		set.Kind = *req.Kind
+	}
+	if req.Status != nil {
+		set.Status = *req.Status
+	}
+	if req.Reps != nil {
+		set.Reps = req.Reps
+	}
+	if req.Weight != nil {
+		set.Weight = req.Weight
+	}
+	if req.RPE != nil {
+		set.RPE = req.RPE
+	}
+	if req.RIR != nil {
+		set.RIR = req.RIR
+	}
+	if req.StartedAt != nil {
+		set.StartedAt = req.StartedAt
+	}
+	if req.CompletedAt != nil {
+		set.CompletedAt = req.CompletedAt
+	}
+
+	// Defaults
+	if set.Kind == "" {
+		set.Kind = SetKindWorking
+	}
+	if set.Status == "" {
+		set.Status = SetStatusPending
+	}
+	return set
+}
+
+// validateSet checks the fields of a single set
+func validateSet(set ExerciseSet) error {
+	if set.SetNumber < 1 {
+		return fmt.Errorf("%w: set number must be at least 1", ErrInvalidSet)
+	}
+	if !set.Kind.IsValid() {
+		return fmt.Errorf("%w: kind must be one of warmup, working, drop, failure", ErrInvalidSet)
+	}
+	if !set.Status.IsValid() {
+		return fmt.Errorf("%w: status must be one of pending, completed, skipped", ErrInvalidSet)
+	}
+	if set.Reps != nil && *set.Reps < 0 {
+		return fmt.Errorf("%w: set %d reps must not be negative", ErrInvalidSet, set.SetNumber)
+	}
+	if set.Weight != nil && *set.Weight < 0 {
+		return fmt.Errorf("%w: set %d weight must not be negative", ErrInvalidSet, set.SetNumber)
+	}
+	if set.RPE != nil && (*set.RPE < 1 || *set.RPE > 10) {
+		return fmt.Errorf("%w: set %d RPE must be between 1 and 10", ErrInvalidSet, set.SetNumber)
+	}
+	if set.RIR != nil && *set.RIR < 0 {
+		return fmt.Errorf("%w: set %d RIR must not be negative", ErrInvalidSet, set.SetNumber)
+	}
+	if set.Status == SetStatusCompleted && set.Reps == nil {
+		return fmt.Errorf("%w: set %d must record reps to be completed", ErrInvalidSet, set.SetNumber)
+	}
+	if set.StartedAt != nil && set.CompletedAt != nil && set.CompletedAt.Before(*set.StartedAt) {
+		return fmt.Errorf("%w: set %d completes before it starts", ErrInvalidSet, set.SetNumber)
+	}
+	return nil
+}
+
+// validateExerciseSets checks all sets of one exercise against the rules
+// of the block the exercise belongs to
+func validateExerciseSets(blockType playlist.BlockType, sets []ExerciseSet) error {
+	if blockType == playlist.BlockTypeCardio {
+		return fmt.Errorf("%w: cardio blocks are logged with cardio entries, not sets", ErrInvalidSet)
+	}
+
+	seen := make(map[int]bool)
+	var lastWeight *float64
+	for _, set := range sets {
+		if err := validateSet(set); err != nil {
+			return err
+		}
+
+		if seen[set.SetNumber] {
+			return fmt.Errorf("%w: duplicate set number %d", ErrInvalidSet, set.SetNumber)
+		}
+		seen[set.SetNumber] = true
+
+		if set.Kind == SetKindDrop && blockType != playlist.BlockTypeDropset {
+			return fmt.Errorf("%w: drop sets are only allowed in dropset blocks", ErrInvalidSet)
+		}
+
+		// Dropsets step the weight down from one working set to the next
+		if blockType == playlist.BlockTypeDropset && set.Kind != SetKindWarmup && set.Weight != nil {
+			if lastWeight != nil && *set.Weight > *lastWeight {
+				return fmt.Errorf("%w: set %d weight must not exceed the previous set in a dropset", ErrInvalidSet, set.SetNumber)
+			}
+			lastWeight = set.Weight
+		}
+	}
+	return nil
+}
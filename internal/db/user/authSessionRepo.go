@@ -0,0 +1,212 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type AuthSessionRepo interface {
+	Create(ctx context.Context, session AuthSession) (AuthSession, error)
+	GetByID(ctx context.Context, id uuid.UUID) (AuthSession, error)
+	ListActive(ctx context.Context, userID uuid.UUID) ([]AuthSession, error)
+	Touch(ctx context.Context, id uuid.UUID) error
+
+	// Revocation also revokes the refresh tokens of the session
+	Revoke(ctx context.Context, id uuid.UUID) error
+	RevokeAll(ctx context.Context, userID uuid.UUID) error
+
+	// Access token denylist
+	RevokeAccessToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string, sessionID *uuid.UUID) (bool, error)
+}
+
+type authSessionRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewAuthSessionRepo(db *sql.DB) AuthSessionRepo {
+	return &authSessionRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const authSessionColumns = `id, user_id, user_agent, ip_address, last_used_at, revoked_at, created_at, updated_at`
+
+func scanAuthSession(row interface{ Scan(dest ...any) error }, extra ...any) (AuthSession, error) {
+	var session AuthSession
+	dest := []any{
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.LastUsedAt,
+		&session.RevokedAt,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return session, err
+}
+
+const createAuthSession = `
+	INSERT INTO auth_sessions (id, user_id, user_agent, ip_address)
+	VALUES ($1, $2, $3, $4)
+	RETURNING ` + authSessionColumns
+
+func (r *authSessionRepo) Create(ctx context.Context, session AuthSession) (AuthSession, error) {
+	var newSession AuthSession
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var err error
+		newSession, err = scanAuthSession(tx.QueryRowContext(ctx, createAuthSession,
+			session.ID,
+			session.UserID,
+			session.UserAgent,
+			session.IPAddress,
+		))
+		return err
+	})
+	if err != nil {
+		log.Printf("Create auth session failed for user %s: %v", session.UserID, err)
+		return AuthSession{}, err
+	}
+	return newSession, nil
+}
+
+const getAuthSessionByID = `SELECT ` + authSessionColumns + ` FROM auth_sessions WHERE id = $1`
+
+func (r *authSessionRepo) GetByID(ctx context.Context, id uuid.UUID) (AuthSession, error) {
+	session, err := scanAuthSession(r.tx.DB().QueryRowContext(ctx, getAuthSessionByID, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return AuthSession{}, nil
+		}
+		return AuthSession{}, err
+	}
+	return session, nil
+}
+
+// Sessions that can still refresh
+const listActiveAuthSessions = `
+	SELECT ` + authSessionColumns + `, t.expires_at
+	FROM auth_sessions
+	JOIN LATERAL (
+		SELECT MAX(expires_at) AS expires_at
+		FROM refresh_tokens
+		WHERE family_id = auth_sessions.id AND NOT is_revoked AND expires_at > NOW()
+	) t ON t.expires_at IS NOT NULL
+	WHERE user_id = $1 AND revoked_at IS NULL
+	ORDER BY last_used_at DESC`
+
+func (r *authSessionRepo) ListActive(ctx context.Context, userID uuid.UUID) ([]AuthSession, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, listActiveAuthSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []AuthSession
+	for rows.Next() {
+		var expiresAt time.Time
+		session, err := scanAuthSession(rows, &expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		session.ExpiresAt = &expiresAt
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+const touchAuthSession = `UPDATE auth_sessions SET last_used_at = NOW() WHERE id = $1`
+
+func (r *authSessionRepo) Touch(ctx context.Context, id uuid.UUID) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, touchAuthSession, id)
+		return err
+	})
+}
+
+const revokeAuthSession = `
+	UPDATE auth_sessions
+	SET revoked_at = NOW()
+	WHERE id = $1 AND revoked_at IS NULL`
+
+const revokeSessionRefreshTokens = `
+	UPDATE refresh_tokens
+	SET is_revoked = TRUE,
+		revoked_at = NOW()
+	WHERE family_id = $1 AND NOT is_revoked`
+
+func (r *authSessionRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, revokeAuthSession, id); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, revokeSessionRefreshTokens, id)
+		return err
+	})
+	if err != nil {
+		log.Printf("Revoke auth session %s failed: %v", id, err)
+	}
+	return err
+}
+
+const revokeAllAuthSessions = `
+	UPDATE auth_sessions
+	SET revoked_at = NOW()
+	WHERE user_id = $1 AND revoked_at IS NULL`
+
+const revokeAllRefreshTokens = `
+	UPDATE refresh_tokens
+	SET is_revoked = TRUE,
+		revoked_at = NOW()
+	WHERE user_id = $1 AND NOT is_revoked`
+
+func (r *authSessionRepo) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, revokeAllAuthSessions, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, revokeAllRefreshTokens, userID)
+		return err
+	})
+	if err != nil {
+		log.Printf("Revoke all auth sessions failed for user %s: %v", userID, err)
+	}
+	return err
+}
+
+const revokeAccessToken = `
+	INSERT INTO revoked_access_tokens (jti, user_id, expires_at)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (jti) DO NOTHING`
+
+// Entries are only needed until the token would have expired anyway
+const pruneRevokedAccessTokens = `DELETE FROM revoked_access_tokens WHERE expires_at < NOW()`
+
+func (r *authSessionRepo) RevokeAccessToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, pruneRevokedAccessTokens); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, revokeAccessToken, jti, userID, expiresAt)
+		return err
+	})
+}
+
+const isAccessTokenRevoked = `
+	SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE jti = $1)
+		OR EXISTS (SELECT 1 FROM auth_sessions WHERE id = $2 AND revoked_at IS NOT NULL)`
+
+func (r *authSessionRepo) IsAccessTokenRevoked(ctx context.Context, jti string, sessionID *uuid.UUID) (bool, error) {
+	var revoked bool
+	err := r.tx.DB().QueryRowContext(ctx, isAccessTokenRevoked, jti, sessionID).Scan(&revoked)
+	return revoked, err
+}
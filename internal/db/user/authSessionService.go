@@ -0,0 +1,86 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/auth"
+)
+
+// Logout revokes the presented access token and the session it belongs to
+func (s *userService) Logout(ctx context.Context, claims auth.UserClaims) error {
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return err
+	}
+
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := s.authSessionRepo.RevokeAccessToken(ctx, claims.ID, userID, claims.ExpiresAt.Time); err != nil {
+			return err
+		}
+	}
+
+	if sessionID, err := uuid.Parse(claims.SessionID); err == nil {
+		return s.authSessionRepo.Revoke(ctx, sessionID)
+	}
+	return nil
+}
+
+// LogoutEverywhere revokes every session of the user
+func (s *userService) LogoutEverywhere(ctx context.Context, userID uuid.UUID) error {
+	return s.authSessionRepo.RevokeAll(ctx, userID)
+}
+
+// ListSessions returns the user's active sessions, marking the current one
+func (s *userService) ListSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]AuthSession, error) {
+	sessions, err := s.authSessionRepo.ListActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.String() == currentSessionID
+	}
+
+	if sessions == nil {
+		sessions = []AuthSession{}
+	}
+	return sessions, nil
+}
+
+// RevokeSession logs out one of the user's sessions
+func (s *userService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.authSessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// Other users' sessions are reported as missing
+	if session.ID == uuid.Nil || session.UserID != userID {
+		return ErrAuthSessionNotFound
+	}
+
+	return s.authSessionRepo.Revoke(ctx, sessionID)
+}
+
+// IsAccessTokenRevoked checks the token's jti and its session
+func (s *userService) IsAccessTokenRevoked(ctx context.Context, claims auth.UserClaims) (bool, error) {
+	var sessionID *uuid.UUID
+	if parsed, err := uuid.Parse(claims.SessionID); err == nil {
+		sessionID = &parsed
+	}
+
+	if claims.ID == "" && sessionID == nil {
+		return false, nil
+	}
+
+	return s.authSessionRepo.IsAccessTokenRevoked(ctx, claims.ID, sessionID)
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,96 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type EmailVerificationRepo interface {
+	// Stores a new token, invalidating earlier unused ones
+	Create(ctx context.Context, tokenHash string, userID uuid.UUID, email string, expiresAt time.Time) error
+
+	// Uses the token and marks the address as verified if it is still the
+	// user's email. Returns uuid.Nil if the token is unknown, used or expired.
+	Verify(ctx context.Context, tokenHash string) (uuid.UUID, error)
+}
+
+type emailVerificationRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewEmailVerificationRepo(db *sql.DB) EmailVerificationRepo {
+	return &emailVerificationRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const invalidateEmailVerificationTokens = `
+	UPDATE email_verification_tokens
+	SET used_at = NOW()
+	WHERE user_id = $1 AND used_at IS NULL`
+
+const createEmailVerificationToken = `
+	INSERT INTO email_verification_tokens (token, user_id, email, expires_at)
+	VALUES ($1, $2, $3, $4)`
+
+func (r *emailVerificationRepo) Create(ctx context.Context, tokenHash string, userID uuid.UUID, email string, expiresAt time.Time) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, invalidateEmailVerificationTokens, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, createEmailVerificationToken, tokenHash, userID, email, expiresAt)
+		return err
+	})
+	if err != nil {
+		log.Printf("Create email verification token failed for user %s: %v", userID, err)
+	}
+	return err
+}
+
+const useEmailVerificationToken = `
+	UPDATE email_verification_tokens
+	SET used_at = NOW()
+	WHERE token = $1 AND used_at IS NULL AND expires_at > NOW()
+	RETURNING user_id, email`
+
+const markEmailVerified = `
+	UPDATE users
+	SET email_verified_at = COALESCE(email_verified_at, NOW()),
+		updated_at = NOW()
+	WHERE id = $1 AND email = $2`
+
+func (r *emailVerificationRepo) Verify(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var email string
+		if err := tx.QueryRowContext(ctx, useEmailVerificationToken, tokenHash).Scan(&userID, &email); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, markEmailVerified, userID, email)
+		if err != nil {
+			return err
+		}
+
+		// The user changed their email after the token was sent
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			userID = uuid.Nil
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, nil
+		}
+		log.Printf("Email verification failed: %v", err)
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,88 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/helper"
+	"github.com/cheezecakee/fitrkr/internal/utils/mailer"
+)
+
+// AccountPolicy lets other domains restrict what unverified users can do
+type AccountPolicy interface {
+	CheckVerified(ctx context.Context, userID uuid.UUID) error
+}
+
+// VerifyEmail marks the user's email as verified with an emailed token
+func (s *userService) VerifyEmail(ctx context.Context, token string) error {
+	userID, err := s.emailVerificationRepo.Verify(ctx, hashRefreshToken(token))
+	if err != nil {
+		return err
+	}
+	if userID == uuid.Nil {
+		return ErrInvalidVerificationToken
+	}
+
+	log.Printf("Email verified for user %s", userID)
+	return nil
+}
+
+// ResendVerification emails a new verification link, replacing earlier ones
+func (s *userService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == uuid.Nil {
+		return ErrUserNotFound
+	}
+
+	if user.IsVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.sendVerification(ctx, user)
+}
+
+// CheckVerified returns ErrEmailNotVerified for unverified users when the
+// policy requires a verified email
+func (s *userService) CheckVerified(ctx context.Context, userID uuid.UUID) error {
+	if !s.config.RequireVerifiedEmail {
+		return nil
+	}
+
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsVerified() {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+func (s *userService) sendVerification(ctx context.Context, user User) error {
+	token, err := helper.MakeRefreshToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(s.config.EmailVerificationTTL)
+	if err := s.emailVerificationRepo.Create(ctx, hashRefreshToken(token), user.ID, user.Email, expiresAt); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your FitTrkr email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nConfirm your email address with the link below. It expires in %s.\n\n%s\n",
+			user.FirstName, s.config.EmailVerificationTTL, tokenLink(s.config.EmailVerificationURL, token),
+		),
+	})
+}
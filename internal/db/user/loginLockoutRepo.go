@@ -0,0 +1,104 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type LoginLockoutRepo interface {
+	// Returns how long the account stays locked, zero if it is not locked
+	LockedFor(ctx context.Context, userID uuid.UUID) (time.Duration, error)
+
+	// Counts a failed login and returns the failures so far. The count
+	// restarts when the previous failure is older than window.
+	RecordFailure(ctx context.Context, userID uuid.UUID, window time.Duration) (int, error)
+
+	Lock(ctx context.Context, userID uuid.UUID, duration time.Duration) error
+	Reset(ctx context.Context, userID uuid.UUID) error
+}
+
+type loginLockoutRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewLoginLockoutRepo(db *sql.DB) LoginLockoutRepo {
+	return &loginLockoutRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const getLoginLockout = `
+	SELECT EXTRACT(EPOCH FROM locked_until - NOW())
+	FROM login_lockouts
+	WHERE user_id = $1 AND locked_until > NOW()`
+
+func (r *loginLockoutRepo) LockedFor(ctx context.Context, userID uuid.UUID) (time.Duration, error) {
+	var seconds float64
+	err := r.tx.DB().QueryRowContext(ctx, getLoginLockout, userID).Scan(&seconds)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		log.Printf("Get login lockout failed for user %s: %v", userID, err)
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+const recordLoginFailure = `
+	INSERT INTO login_lockouts (user_id, failed_attempts, last_failed_at)
+	VALUES ($1, 1, NOW())
+	ON CONFLICT (user_id) DO UPDATE SET
+		failed_attempts = CASE
+			WHEN login_lockouts.last_failed_at < NOW() - make_interval(secs => $2) THEN 1
+			ELSE login_lockouts.failed_attempts + 1
+		END,
+		last_failed_at = NOW()
+	RETURNING failed_attempts`
+
+func (r *loginLockoutRepo) RecordFailure(ctx context.Context, userID uuid.UUID, window time.Duration) (int, error) {
+	var attempts int
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, recordLoginFailure, userID, window.Seconds()).Scan(&attempts)
+	})
+	if err != nil {
+		log.Printf("Record login failure failed for user %s: %v", userID, err)
+		return 0, err
+	}
+	return attempts, nil
+}
+
+const lockLogin = `
+	UPDATE login_lockouts
+	SET locked_until = NOW() + make_interval(secs => $2)
+	WHERE user_id = $1`
+
+func (r *loginLockoutRepo) Lock(ctx context.Context, userID uuid.UUID, duration time.Duration) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, lockLogin, userID, duration.Seconds())
+		return err
+	})
+	if err != nil {
+		log.Printf("Lock login failed for user %s: %v", userID, err)
+	}
+	return err
+}
+
+const resetLoginLockout = `DELETE FROM login_lockouts WHERE user_id = $1`
+
+func (r *loginLockoutRepo) Reset(ctx context.Context, userID uuid.UUID) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, resetLoginLockout, userID)
+		return err
+	})
+	if err != nil {
+		log.Printf("Reset login lockout failed for user %s: %v", userID, err)
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountLockedError is returned by Login while too many failed attempts
+// keep the account locked
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account locked, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// LockoutConfig controls the progressive lockout after failed logins. The
+// first lock lasts Duration and every further failure doubles it, up to
+// MaxDuration. A zero Threshold disables the lockout.
+type LockoutConfig struct {
+	Threshold   int
+	Duration    time.Duration
+	MaxDuration time.Duration
+	Window      time.Duration // failures older than this are forgotten
+}
+
+// lockDuration is how long the account locks after attempts failures
+func (c LockoutConfig) lockDuration(attempts int) time.Duration {
+	duration := c.Duration
+	for i := c.Threshold; i < attempts && duration < c.MaxDuration; i++ {
+		duration *= 2
+	}
+	return min(duration, c.MaxDuration)
+}
+
+// checkLockout returns an AccountLockedError while the account is locked
+func (s *userService) checkLockout(ctx context.Context, userID uuid.UUID) error {
+	if s.config.Lockout.Threshold <= 0 {
+		return nil
+	}
+
+	lockedFor, err := s.loginLockoutRepo.LockedFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if lockedFor > 0 {
+		return &AccountLockedError{RetryAfter: lockedFor}
+	}
+	return nil
+}
+
+// recordFailedLogin counts a wrong password and locks the account once the
+// threshold is reached. It returns the error Login should report.
+func (s *userService) recordFailedLogin(ctx context.Context, userID uuid.UUID) error {
+	if s.config.Lockout.Threshold <= 0 {
+		return ErrInvalidCredentials
+	}
+
+	attempts, err := s.loginLockoutRepo.RecordFailure(ctx, userID, s.config.Lockout.Window)
+	if err != nil {
+		return err
+	}
+	if attempts < s.config.Lockout.Threshold {
+		return ErrInvalidCredentials
+	}
+
+	duration := s.config.Lockout.lockDuration(attempts)
+	if err := s.loginLockoutRepo.Lock(ctx, userID, duration); err != nil {
+		return err
+	}
+
+	log.Printf("Locked login for user %s for %s after %d failed attempts", userID, duration, attempts)
+	return &AccountLockedError{RetryAfter: duration}
+}
+
+// clearFailedLogins forgets the failed attempts after a successful login
+func (s *userService) clearFailedLogins(ctx context.Context, userID uuid.UUID) {
+	if s.config.Lockout.Threshold <= 0 {
+		return
+	}
+
+	if err := s.loginLockoutRepo.Reset(ctx, userID); err != nil {
+		// Continue - the count restarts after the failure window anyway
+		log.Printf("Failed to clear failed logins for user %s: %v", userID, err)
+	}
+}
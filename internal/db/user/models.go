@@ -17,6 +17,13 @@ type User struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 	IsPremium    bool      `json:"is_premium"`
 	Roles        []string  `json:"roles"` // User roles, e.g., ["admin", "user"]
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// IsVerified reports whether the user confirmed their email address
+func (u User) IsVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 type UserResponse struct {
@@ -29,6 +36,8 @@ type UserResponse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	IsPremium bool      `json:"is_premium"`
 	Roles     []string  `json:"roles"`
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
 }
 
 type UserRequest struct {
@@ -52,3 +61,79 @@ type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// RefreshToken is an opaque refresh token. Only the hash of the token is stored.
+type RefreshToken struct {
+	TokenHash  string     `json:"-"`
+	UserID     uuid.UUID  `json:"user_id"`
+	FamilyID   uuid.UUID  `json:"family_id"`
+	IsRevoked  bool       `json:"is_revoked"`
+	ReplacedBy *string    `json:"-"` // hash of the token issued when this one was rotated
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TokenPair is returned on login and refresh
+type TokenPair struct {
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// RefreshTokenRequest carries a refresh token for /auth/refresh and /auth/revoke
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// AuthSession is a login on one device
+type AuthSession struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	UserAgent  *string    `json:"user_agent"`
+	IPAddress  *string    `json:"ip_address"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // expiry of the current refresh token
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Computed data (not in DB)
+	Current bool `json:"current"` // session of the requesting token
+}
+
+// ClientInfo describes the device a login comes from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// ForgotPasswordRequest starts a password reset
+type ForgotPasswordRequest struct {
+	Email string `json:"email" example:"jane@example.com"`
+}
+
+// ResetPasswordRequest completes a password reset with the emailed token
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// VerifyEmailRequest confirms an email address with the emailed token
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// Role is a named set of permissions assigned to users
+type Role struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// GrantRoleRequest assigns a role to a user
+type GrantRoleRequest struct {
+	Role string `json:"role" example:"catalog_editor"`
+}
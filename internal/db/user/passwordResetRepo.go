@@ -0,0 +1,95 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type PasswordResetRepo interface {
+	// Stores a new token, invalidating earlier unused ones
+	Create(ctx context.Context, tokenHash string, userID uuid.UUID, expiresAt time.Time) error
+
+	// Uses the token, sets the password, logs out every session and clears
+	// the login lockout in one transaction. Returns uuid.Nil if the token is
+	// unknown, used or expired.
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error)
+}
+
+type passwordResetRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewPasswordResetRepo(db *sql.DB) PasswordResetRepo {
+	return &passwordResetRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const invalidatePasswordResetTokens = `
+	UPDATE password_reset_tokens
+	SET used_at = NOW()
+	WHERE user_id = $1 AND used_at IS NULL`
+
+const createPasswordResetToken = `
+	INSERT INTO password_reset_tokens (token, user_id, expires_at)
+	VALUES ($1, $2, $3)`
+
+func (r *passwordResetRepo) Create(ctx context.Context, tokenHash string, userID uuid.UUID, expiresAt time.Time) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, invalidatePasswordResetTokens, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, createPasswordResetToken, tokenHash, userID, expiresAt)
+		return err
+	})
+	if err != nil {
+		log.Printf("Create password reset token failed for user %s: %v", userID, err)
+	}
+	return err
+}
+
+const usePasswordResetToken = `
+	UPDATE password_reset_tokens
+	SET used_at = NOW()
+	WHERE token = $1 AND used_at IS NULL AND expires_at > NOW()
+	RETURNING user_id`
+
+const updatePasswordHash = `UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1`
+
+func (r *passwordResetRepo) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, usePasswordResetToken, tokenHash).Scan(&userID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, updatePasswordHash, userID, passwordHash); err != nil {
+			return err
+		}
+
+		// A reset must lock out whoever knew the old password
+		if _, err := tx.ExecContext(ctx, revokeAllAuthSessions, userID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, revokeAllRefreshTokens, userID); err != nil {
+			return err
+		}
+
+		// The new password has to work right away
+		_, err := tx.ExecContext(ctx, resetLoginLockout, userID)
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, nil
+		}
+		log.Printf("Password reset failed: %v", err)
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/helper"
+	"github.com/cheezecakee/fitrkr/internal/utils/mailer"
+)
+
+const minPasswordLength = 8
+
+// sendResetTimeout bounds the work ForgotPassword does after answering
+const sendResetTimeout = 30 * time.Second
+
+// ForgotPassword emails a reset link if the address belongs to a user.
+// Unknown addresses are not reported so accounts cannot be enumerated, and
+// the link is created and sent in the background so the response takes as
+// long for both.
+func (s *userService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil || user.ID == uuid.Nil {
+		log.Printf("Password reset requested for unknown email")
+		return nil
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendResetTimeout)
+		defer cancel()
+
+		if err := s.sendPasswordReset(ctx, user); err != nil {
+			log.Printf("Failed to send password reset email to user %s: %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+// sendPasswordReset creates a reset token for the user and emails the link
+func (s *userService) sendPasswordReset(ctx context.Context, user User) error {
+	token, err := helper.MakeRefreshToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(s.config.PasswordResetTTL)
+	if err := s.passwordResetRepo.Create(ctx, hashRefreshToken(token), user.ID, expiresAt); err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your FitTrkr password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to choose a new password. It expires in %s and can only be used once.\n\n%s\n\nIf you did not ask for a reset you can ignore this email.\n",
+			user.FirstName, s.config.PasswordResetTTL, tokenLink(s.config.PasswordResetURL, token),
+		),
+	}
+	return s.mailer.Send(ctx, msg)
+}
+
+// ResetPassword sets a new password with a reset token, logs the user out
+// of every session and lifts a login lockout
+func (s *userService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if len(newPassword) < minPasswordLength {
+		return ErrWeakPassword
+	}
+
+	hashedPassword, err := helper.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := s.passwordResetRepo.ResetPassword(ctx, hashRefreshToken(token), hashedPassword)
+	if err != nil {
+		return err
+	}
+	if userID == uuid.Nil {
+		return ErrInvalidResetToken
+	}
+
+	log.Printf("Password reset for user %s", userID)
+	return nil
+}
+
+// tokenLink appends the token to a frontend URL as a query parameter
+func tokenLink(base, token string) string {
+	link, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
@@ -0,0 +1,34 @@
+package user
+
+import "slices"
+
+// Permission names an action that roles can be allowed to perform
+type Permission string
+
+const (
+	PermExerciseWrite Permission = "exercise:write" // create, edit and delete exercises
+	PermCatalogManage Permission = "catalog:manage" // manage equipment, categories, muscle groups and training types
+	PermUserModerate  Permission = "user:moderate"  // view and moderate other accounts
+	PermRoleManage    Permission = "role:manage"    // grant and revoke roles
+	PermCoachClients  Permission = "client:coach"   // invite clients and manage their playlists
+)
+
+// Permissions is the registry of every known permission. The
+// role_permissions table may only reference these.
+var Permissions = []Permission{
+	PermExerciseWrite,
+	PermCatalogManage,
+	PermUserModerate,
+	PermRoleManage,
+	PermCoachClients,
+}
+
+func (p Permission) IsValid() bool {
+	return slices.Contains(Permissions, p)
+}
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+	RoleCoach = "coach"
+)
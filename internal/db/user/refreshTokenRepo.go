@@ -0,0 +1,131 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type RefreshTokenRepo interface {
+	Create(ctx context.Context, token RefreshToken) (RefreshToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+
+	// Revokes the old token and stores its replacement in one transaction.
+	// Returns false if the old token was no longer active.
+	Rotate(ctx context.Context, oldHash string, next RefreshToken) (bool, error)
+	Revoke(ctx context.Context, tokenHash string) error
+}
+
+type refreshTokenRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewRefreshTokenRepo(db *sql.DB) RefreshTokenRepo {
+	return &refreshTokenRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const refreshTokenColumns = `token, user_id, family_id, is_revoked, replaced_by, expires_at, revoked_at, created_at, updated_at`
+
+func scanRefreshToken(row interface{ Scan(dest ...any) error }) (RefreshToken, error) {
+	var token RefreshToken
+	err := row.Scan(
+		&token.TokenHash,
+		&token.UserID,
+		&token.FamilyID,
+		&token.IsRevoked,
+		&token.ReplacedBy,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+		&token.UpdatedAt,
+	)
+	return token, err
+}
+
+const createRefreshToken = `
+	INSERT INTO refresh_tokens (token, user_id, family_id, expires_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING ` + refreshTokenColumns
+
+func (r *refreshTokenRepo) Create(ctx context.Context, token RefreshToken) (RefreshToken, error) {
+	var newToken RefreshToken
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		var err error
+		newToken, err = scanRefreshToken(tx.QueryRowContext(ctx, createRefreshToken,
+			token.TokenHash,
+			token.UserID,
+			token.FamilyID,
+			token.ExpiresAt,
+		))
+		return err
+	})
+	if err != nil {
+		log.Printf("Create refresh token failed for user %s: %v", token.UserID, err)
+		return RefreshToken{}, err
+	}
+	return newToken, nil
+}
+
+const getRefreshTokenByHash = `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token = $1`
+
+func (r *refreshTokenRepo) GetByHash(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	token, err := scanRefreshToken(r.tx.DB().QueryRowContext(ctx, getRefreshTokenByHash, tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return RefreshToken{}, nil
+		}
+		return RefreshToken{}, err
+	}
+	return token, nil
+}
+
+const rotateRefreshToken = `
+	UPDATE refresh_tokens
+	SET is_revoked = TRUE,
+		revoked_at = NOW(),
+		replaced_by = $2
+	WHERE token = $1 AND NOT is_revoked`
+
+func (r *refreshTokenRepo) Rotate(ctx context.Context, oldHash string, next RefreshToken) (bool, error) {
+	rotated := false
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, rotateRefreshToken, oldHash, next.TokenHash)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil || affected == 0 {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, createRefreshToken, next.TokenHash, next.UserID, next.FamilyID, next.ExpiresAt)
+		if err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	if err != nil {
+		log.Printf("Rotate refresh token failed for family %s: %v", next.FamilyID, err)
+		return false, err
+	}
+	return rotated, nil
+}
+
+const revokeRefreshToken = `
+	UPDATE refresh_tokens
+	SET is_revoked = TRUE,
+		revoked_at = NOW()
+	WHERE token = $1 AND NOT is_revoked`
+
+func (r *refreshTokenRepo) Revoke(ctx context.Context, tokenHash string) error {
+	return r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, revokeRefreshToken, tokenHash)
+		return err
+	})
+}
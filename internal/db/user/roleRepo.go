@@ -0,0 +1,146 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+type RoleRepo interface {
+	List(ctx context.Context) ([]Role, error)
+	Exists(ctx context.Context, name string) (bool, error)
+
+	// Returns the distinct permissions granted by any of the roles
+	PermissionsFor(ctx context.Context, roles []string) ([]Permission, error)
+
+	// User role assignment, stored in users.roles
+	AddToUser(ctx context.Context, userID uuid.UUID, role string) error
+	RemoveFromUser(ctx context.Context, userID uuid.UUID, role string) error
+	CountUsers(ctx context.Context, role string) (int, error)
+}
+
+type roleRepo struct {
+	tx transaction.BaseRepository
+}
+
+func NewRoleRepo(db *sql.DB) RoleRepo {
+	return &roleRepo{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const listRoles = `
+	SELECT r.name, r.description,
+		COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+	FROM roles r
+	LEFT JOIN role_permissions rp ON rp.role = r.name
+	GROUP BY r.name, r.description
+	ORDER BY r.name`
+
+func (r *roleRepo) List(ctx context.Context) ([]Role, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, listRoles)
+	if err != nil {
+		log.Printf("List roles failed: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []Role
+	for rows.Next() {
+		var role Role
+		var permissions []string
+		if err := rows.Scan(&role.Name, &role.Description, pq.Array(&permissions)); err != nil {
+			return nil, err
+		}
+		for _, permission := range permissions {
+			role.Permissions = append(role.Permissions, Permission(permission))
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+const roleExists = `SELECT EXISTS (SELECT 1 FROM roles WHERE name = $1)`
+
+func (r *roleRepo) Exists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	if err := r.tx.DB().QueryRowContext(ctx, roleExists, name).Scan(&exists); err != nil {
+		log.Printf("Role exists check failed for %s: %v", name, err)
+		return false, err
+	}
+	return exists, nil
+}
+
+const permissionsForRoles = `
+	SELECT DISTINCT permission
+	FROM role_permissions
+	WHERE role = ANY($1)`
+
+func (r *roleRepo) PermissionsFor(ctx context.Context, roles []string) ([]Permission, error) {
+	rows, err := r.tx.DB().QueryContext(ctx, permissionsForRoles, pq.Array(roles))
+	if err != nil {
+		log.Printf("Get permissions failed for roles %v: %v", roles, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []Permission
+	for rows.Next() {
+		var permission Permission
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+const addRoleToUser = `
+	UPDATE users
+	SET roles = array_append(roles, $2), updated_at = NOW()
+	WHERE id = $1 AND NOT ($2 = ANY(roles))`
+
+func (r *roleRepo) AddToUser(ctx context.Context, userID uuid.UUID, role string) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, addRoleToUser, userID, role)
+		return err
+	})
+	if err != nil {
+		log.Printf("Add role %s failed for user %s: %v", role, userID, err)
+	}
+	return err
+}
+
+const removeRoleFromUser = `
+	UPDATE users
+	SET roles = array_remove(roles, $2), updated_at = NOW()
+	WHERE id = $1 AND $2 = ANY(roles)`
+
+func (r *roleRepo) RemoveFromUser(ctx context.Context, userID uuid.UUID, role string) error {
+	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, removeRoleFromUser, userID, role)
+		return err
+	})
+	if err != nil {
+		log.Printf("Remove role %s failed for user %s: %v", role, userID, err)
+	}
+	return err
+}
+
+const countUsersWithRole = `SELECT COUNT(*) FROM users WHERE $1 = ANY(roles)`
+
+func (r *roleRepo) CountUsers(ctx context.Context, role string) (int, error) {
+	var count int
+	if err := r.tx.DB().QueryRowContext(ctx, countUsersWithRole, role).Scan(&count); err != nil {
+		log.Printf("Count users failed for role %s: %v", role, err)
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,95 @@
+package user
+
+import (
+	"context"
+	"log"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// ListRoles returns every role with its permissions
+func (s *userService) ListRoles(ctx context.Context) ([]Role, error) {
+	return s.roleRepo.List(ctx)
+}
+
+// HasPermissions reports whether the user's roles grant every permission
+func (s *userService) HasPermissions(ctx context.Context, user User, permissions ...Permission) (bool, error) {
+	if len(user.Roles) == 0 {
+		return len(permissions) == 0, nil
+	}
+
+	granted, err := s.roleRepo.PermissionsFor(ctx, user.Roles)
+	if err != nil {
+		return false, err
+	}
+
+	for _, permission := range permissions {
+		if !slices.Contains(granted, permission) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// GrantRole adds a role to a user. Granting a role the user has is a no-op.
+func (s *userService) GrantRole(ctx context.Context, userID uuid.UUID, role string) (User, error) {
+	if err := s.checkRoleExists(ctx, role); err != nil {
+		return User{}, err
+	}
+
+	if _, err := s.GetUserByID(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	if err := s.roleRepo.AddToUser(ctx, userID, role); err != nil {
+		return User{}, err
+	}
+
+	log.Printf("Granted role %s to user %s", role, userID)
+	return s.GetUserByID(ctx, userID)
+}
+
+// RevokeRole removes a role from a user. The last admin cannot lose the
+// admin role, so someone can always manage roles.
+func (s *userService) RevokeRole(ctx context.Context, userID uuid.UUID, role string) (User, error) {
+	if err := s.checkRoleExists(ctx, role); err != nil {
+		return User{}, err
+	}
+
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return User{}, err
+	}
+	if !slices.Contains(user.Roles, role) {
+		return user, nil
+	}
+
+	if role == RoleAdmin {
+		admins, err := s.roleRepo.CountUsers(ctx, RoleAdmin)
+		if err != nil {
+			return User{}, err
+		}
+		if admins <= 1 {
+			return User{}, ErrLastAdmin
+		}
+	}
+
+	if err := s.roleRepo.RemoveFromUser(ctx, userID, role); err != nil {
+		return User{}, err
+	}
+
+	log.Printf("Revoked role %s from user %s", role, userID)
+	return s.GetUserByID(ctx, userID)
+}
+
+func (s *userService) checkRoleExists(ctx context.Context, role string) error {
+	exists, err := s.roleRepo.Exists(ctx, role)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrRoleNotFound
+	}
+	return nil
+}
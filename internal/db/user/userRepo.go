@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -18,7 +19,9 @@ type UserRepo interface {
 	GetByUsername(ctx context.Context, username string) (User, error)
 	Update(ctx context.Context, user User) (User, error)
 	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, offset, limit int) ([]User, error)
+	// List returns the page of users after the given one, oldest first
+	List(ctx context.Context, after *User, limit int) ([]User, error)
+	Count(ctx context.Context) (int, error)
 }
 
 type userRepo struct {
@@ -34,7 +37,7 @@ func NewUserRepo(db *sql.DB) UserRepo {
 const createUser = `
     INSERT INTO users (username, first_name, last_name, password_hash, email, roles)
     VALUES ($1, $2, $3, $4, $5, $6)
-    RETURNING id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles`
+    RETURNING id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles, email_verified_at`
 
 func (r *userRepo) Create(ctx context.Context, user User) (User, error) {
 	if user.ID == uuid.Nil {
@@ -42,7 +45,7 @@ func (r *userRepo) Create(ctx context.Context, user User) (User, error) {
 	}
 	var newUser User
 	err := r.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
-		return tx.QueryRowContext(ctx, createUser, user.Username, user.FirstName, user.LastName, user.PasswordHash, user.Email, pq.Array(user.Roles)).Scan(&newUser.ID, &newUser.Username, &newUser.FirstName, &newUser.LastName, &newUser.PasswordHash, &newUser.Email, &newUser.CreatedAt, &newUser.UpdatedAt, &newUser.IsPremium, pq.Array(&newUser.Roles))
+		return tx.QueryRowContext(ctx, createUser, user.Username, user.FirstName, user.LastName, user.PasswordHash, user.Email, pq.Array(user.Roles)).Scan(&newUser.ID, &newUser.Username, &newUser.FirstName, &newUser.LastName, &newUser.PasswordHash, &newUser.Email, &newUser.CreatedAt, &newUser.UpdatedAt, &newUser.IsPremium, pq.Array(&newUser.Roles), &newUser.EmailVerifiedAt)
 	})
 	if err != nil {
 		return User{}, err
@@ -50,7 +53,7 @@ func (r *userRepo) Create(ctx context.Context, user User) (User, error) {
 	return newUser, nil
 }
 
-const getUserByID = `SELECT id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles FROM users WHERE id = $1 LIMIT 1`
+const getUserByID = `SELECT id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles, email_verified_at FROM users WHERE id = $1 LIMIT 1`
 
 func (r *userRepo) GetByID(ctx context.Context, id uuid.UUID) (User, error) {
 	user := User{}
@@ -65,6 +68,7 @@ func (r *userRepo) GetByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&user.UpdatedAt,
 		&user.IsPremium,
 		pq.Array(&user.Roles),
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
 		log.Printf("GetByID failed for %s: %v", id, err) // debug
@@ -78,7 +82,7 @@ func (r *userRepo) GetByID(ctx context.Context, id uuid.UUID) (User, error) {
 	return user, nil
 }
 
-const getUserByEmail = `SELECT id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles FROM users WHERE email = $1 LIMIT 1`
+const getUserByEmail = `SELECT id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles, email_verified_at FROM users WHERE email = $1 LIMIT 1`
 
 func (r *userRepo) GetByEmail(ctx context.Context, email string) (User, error) {
 	user := User{}
@@ -93,6 +97,7 @@ func (r *userRepo) GetByEmail(ctx context.Context, email string) (User, error) {
 		&user.UpdatedAt,
 		&user.IsPremium,
 		pq.Array(&user.Roles),
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
 		log.Printf("GetByEmail failed for %s: %v", email, err) // debug
@@ -102,7 +107,7 @@ func (r *userRepo) GetByEmail(ctx context.Context, email string) (User, error) {
 	return user, nil
 }
 
-const getUserByUsername = `SELECT id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles FROM users WHERE username = $1 LIMIT 1`
+const getUserByUsername = `SELECT id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles, email_verified_at FROM users WHERE username = $1 LIMIT 1`
 
 func (r *userRepo) GetByUsername(ctx context.Context, username string) (User, error) {
 	user := User{}
@@ -117,6 +122,7 @@ func (r *userRepo) GetByUsername(ctx context.Context, username string) (User, er
 		&user.UpdatedAt,
 		&user.IsPremium,
 		pq.Array(&user.Roles),
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
 		log.Printf("GetByUsername failed for %s: %v", username, err) // debug
@@ -136,11 +142,12 @@ const updateUser = `
         last_name = COALESCE(NULLIF($3, ''), last_name),
         password_hash = COALESCE(NULLIF($4, ''), password_hash),
         email = COALESCE(NULLIF($5, ''), email),
+        email_verified_at = CASE WHEN NULLIF($5, '') IS NOT NULL AND $5 <> email THEN NULL ELSE email_verified_at END,
         updated_at = NOW(),
         is_premium = COALESCE($6, is_premium),
         roles = COALESCE($7, roles)
     WHERE id = $1
-    RETURNING id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles`
+    RETURNING id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles, email_verified_at`
 
 func (r *userRepo) Update(ctx context.Context, user User) (User, error) {
 	var updatedUser User
@@ -156,6 +163,7 @@ func (r *userRepo) Update(ctx context.Context, user User) (User, error) {
 			&updatedUser.UpdatedAt,
 			&updatedUser.IsPremium,
 			pq.Array(&updatedUser.Roles),
+			&updatedUser.EmailVerifiedAt,
 		)
 	})
 	if err != nil {
@@ -178,10 +186,21 @@ func (r *userRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-const listUsers = `SELECT id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles FROM users OFFSET $1 LIMIT $2`
+const listUsers = `
+	SELECT id, username, first_name, last_name, password_hash, email, created_at, updated_at, is_premium, roles, email_verified_at
+	FROM users
+	WHERE $1::timestamp IS NULL OR (created_at, id) > ($1, $2)
+	ORDER BY created_at, id
+	LIMIT $3`
+
+func (r *userRepo) List(ctx context.Context, after *User, limit int) ([]User, error) {
+	var afterCreatedAt *time.Time
+	var afterID uuid.UUID
+	if after != nil {
+		afterCreatedAt, afterID = &after.CreatedAt, after.ID
+	}
 
-func (r *userRepo) List(ctx context.Context, offset, limit int) ([]User, error) {
-	rows, err := r.tx.DB().QueryContext(ctx, listUsers, offset, limit)
+	rows, err := r.tx.DB().QueryContext(ctx, listUsers, afterCreatedAt, afterID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -200,6 +219,8 @@ func (r *userRepo) List(ctx context.Context, offset, limit int) ([]User, error)
 			&user.CreatedAt,
 			&user.UpdatedAt,
 			&user.IsPremium,
+			pq.Array(&user.Roles),
+			&user.EmailVerifiedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -209,3 +230,11 @@ func (r *userRepo) List(ctx context.Context, offset, limit int) ([]User, error)
 
 	return users, rows.Err()
 }
+
+const countUsers = `SELECT COUNT(*) FROM users`
+
+func (r *userRepo) Count(ctx context.Context) (int, error) {
+	var total int
+	err := r.tx.DB().QueryRowContext(ctx, countUsers).Scan(&total)
+	return total, err
+}
@@ -3,14 +3,19 @@ package user
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/cheezecakee/fitrkr/internal/utils/auth"
 	"github.com/cheezecakee/fitrkr/internal/utils/helper"
+	"github.com/cheezecakee/fitrkr/internal/utils/mailer"
+	"github.com/cheezecakee/fitrkr/internal/utils/pagination"
 )
 
 var (
@@ -18,32 +23,114 @@ var (
 	ErrDuplicateUsername  = errors.New("username already exists")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidCredentials = errors.New("invalid email or password")
+
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrAuthSessionNotFound = errors.New("auth session not found")
+
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	ErrWeakPassword      = errors.New("password must be at least 8 characters")
+
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrEmailNotVerified         = errors.New("email verification required")
+
+	ErrRoleNotFound = errors.New("role not found")
+	ErrLastAdmin    = errors.New("cannot remove the last admin")
 )
 
+// AuthConfig holds the token lifetimes and links used by the user service
+type AuthConfig struct {
+	RefreshTokenTTL  time.Duration
+	PasswordResetTTL time.Duration
+	PasswordResetURL string
+
+	EmailVerificationTTL time.Duration
+	EmailVerificationURL string
+	RequireVerifiedEmail bool // restrict unverified users, see CheckVerified
+
+	Lockout LockoutConfig
+}
+
 type UserService interface {
 	Register(ctx context.Context, user User) (User, error)
-	Login(ctx context.Context, email, password string) (string, error)
+	Login(ctx context.Context, email, password string, client ClientInfo) (TokenPair, error)
+	RefreshToken(ctx context.Context, refreshToken string) (TokenPair, error)
+	RevokeToken(ctx context.Context, refreshToken string) error
+
+	// Login sessions
+	Logout(ctx context.Context, claims auth.UserClaims) error
+	LogoutEverywhere(ctx context.Context, userID uuid.UUID) error
+	ListSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]AuthSession, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	IsAccessTokenRevoked(ctx context.Context, claims auth.UserClaims) (bool, error)
+
+	// Password reset
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+
+	// Email verification
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID uuid.UUID) error
+	AccountPolicy
+
+	// Roles and permissions
+	ListRoles(ctx context.Context) ([]Role, error)
+	HasPermissions(ctx context.Context, user User, permissions ...Permission) (bool, error)
+	GrantRole(ctx context.Context, userID uuid.UUID, role string) (User, error)
+	RevokeRole(ctx context.Context, userID uuid.UUID, role string) (User, error)
+
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
 	Update(ctx context.Context, user User) (User, error)
 	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, offset, limit int) ([]User, error)
+	List(ctx context.Context, page pagination.Request) (pagination.Page[User], error)
 }
 
 type userService struct {
-	repo       UserRepo
-	jwtManager auth.JWT
+	repo                  UserRepo
+	refreshTokenRepo      RefreshTokenRepo
+	authSessionRepo       AuthSessionRepo
+	passwordResetRepo     PasswordResetRepo
+	emailVerificationRepo EmailVerificationRepo
+	loginLockoutRepo      LoginLockoutRepo
+	roleRepo              RoleRepo
+	jwtManager            auth.JWT
+	mailer                mailer.Mailer
+	config                AuthConfig
 }
 
-func NewUserService(repo UserRepo, jwtMgr auth.JWT) UserService {
-	return &userService{repo: repo, jwtManager: jwtMgr}
+func NewUserService(
+	repo UserRepo,
+	refreshTokenRepo RefreshTokenRepo,
+	authSessionRepo AuthSessionRepo,
+	passwordResetRepo PasswordResetRepo,
+	emailVerificationRepo EmailVerificationRepo,
+	loginLockoutRepo LoginLockoutRepo,
+	roleRepo RoleRepo,
+	jwtMgr auth.JWT,
+	mail mailer.Mailer,
+	config AuthConfig,
+) UserService {
+	return &userService{
+		repo:                  repo,
+		refreshTokenRepo:      refreshTokenRepo,
+		authSessionRepo:       authSessionRepo,
+		passwordResetRepo:     passwordResetRepo,
+		emailVerificationRepo: emailVerificationRepo,
+		loginLockoutRepo:      loginLockoutRepo,
+		roleRepo:              roleRepo,
+		jwtManager:            jwtMgr,
+		mailer:                mail,
+		config:                config,
+	}
 }
 
 func (s *userService) Register(ctx context.Context, user User) (User, error) {
 	// Default roles to ["user"] if not provided
 	if len(user.Roles) == 0 {
-		user.Roles = []string{"user"}
+		user.Roles = []string{RoleUser}
 	}
 	// Check for existing Email
 	u, err := s.repo.GetByEmail(ctx, user.Email)
@@ -69,29 +156,157 @@ func (s *userService) Register(ctx context.Context, user User) (User, error) {
 	}
 	user.PasswordHash = hashedPassword
 
-	return s.repo.Create(ctx, user)
+	newUser, err := s.repo.Create(ctx, user)
+	if err != nil {
+		return User{}, err
+	}
+
+	if err := s.sendVerification(ctx, newUser); err != nil {
+		// Continue - user can ask for a new verification email
+		log.Printf("Failed to send verification email to user %s: %v", newUser.ID, err)
+	}
+
+	return newUser, nil
 }
 
-func (s *userService) Login(ctx context.Context, email, password string) (string, error) {
+func (s *userService) Login(ctx context.Context, email, password string, client ClientInfo) (TokenPair, error) {
 	user, err := s.GetUserByEmail(ctx, email)
 	if err != nil {
 		log.Println("email err:", err)
-		return "", ErrInvalidCredentials // No logging for security
+		return TokenPair{}, ErrInvalidCredentials // No logging for security
 	}
 	log.Printf("email: %s, username: %s", user.Email, user.Username)
 
+	// Locked accounts are rejected before the password is checked
+	if err := s.checkLockout(ctx, user.ID); err != nil {
+		return TokenPair{}, err
+	}
+
 	if err := helper.ComparePassword(user.PasswordHash, password); err != nil {
 		log.Println("password compare err:", err)
-		return "", ErrInvalidCredentials
+		return TokenPair{}, s.recordFailedLogin(ctx, user.ID)
+	}
+	s.clearFailedLogins(ctx, user.ID)
+
+	// Every login starts a new session, which is also the refresh token family
+	session, err := s.authSessionRepo.Create(ctx, AuthSession{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		UserAgent: optionalString(client.UserAgent),
+		IPAddress: optionalString(client.IPAddress),
+	})
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	pair, next, err := s.issueTokens(user, session.ID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if _, err := s.refreshTokenRepo.Create(ctx, next); err != nil {
+		return TokenPair{}, err
 	}
 
-	token, err := s.jwtManager.MakeJWT(user.ID, user.Roles)
+	return pair, nil
+}
+
+// RefreshToken rotates a refresh token. Presenting a token that was already
+// rotated revokes its whole family, since either copy may be stolen.
+func (s *userService) RefreshToken(ctx context.Context, refreshToken string) (TokenPair, error) {
+	tokenHash := hashRefreshToken(refreshToken)
+	current, err := s.refreshTokenRepo.GetByHash(ctx, tokenHash)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if current.TokenHash == "" {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	if current.IsRevoked {
+		if current.ReplacedBy != nil {
+			log.Printf("Refresh token reuse detected for user %s, revoking session %s", current.UserID, current.FamilyID)
+			if err := s.authSessionRepo.Revoke(ctx, current.FamilyID); err != nil {
+				return TokenPair{}, err
+			}
+			return TokenPair{}, ErrRefreshTokenReused
+		}
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.GetByID(ctx, current.UserID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if user.ID == uuid.Nil {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	pair, next, err := s.issueTokens(user, current.FamilyID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	rotated, err := s.refreshTokenRepo.Rotate(ctx, tokenHash, next)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if !rotated {
+		// Another request rotated the token first
+		if err := s.authSessionRepo.Revoke(ctx, current.FamilyID); err != nil {
+			return TokenPair{}, err
+		}
+		return TokenPair{}, ErrRefreshTokenReused
+	}
+
+	if err := s.authSessionRepo.Touch(ctx, current.FamilyID); err != nil {
+		log.Printf("Failed to update last use of session %s: %v", current.FamilyID, err)
+	}
+
+	return pair, nil
+}
+
+// RevokeToken revokes a refresh token. Unknown tokens are ignored.
+func (s *userService) RevokeToken(ctx context.Context, refreshToken string) error {
+	return s.refreshTokenRepo.Revoke(ctx, hashRefreshToken(refreshToken))
+}
+
+// issueTokens creates an access token and a refresh token of the given session
+func (s *userService) issueTokens(user User, familyID uuid.UUID) (TokenPair, RefreshToken, error) {
+	now := time.Now()
+	accessToken, err := s.jwtManager.MakeJWT(user.ID, user.Roles, familyID)
 	if err != nil {
 		log.Println("jwt err:", err)
-		return "", err
+		return TokenPair{}, RefreshToken{}, err
 	}
 
-	return token, nil
+	refreshToken, err := helper.MakeRefreshToken()
+	if err != nil {
+		return TokenPair{}, RefreshToken{}, err
+	}
+
+	pair := TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  now.Add(s.jwtManager.AccessTTL()),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: now.Add(s.config.RefreshTokenTTL),
+	}
+	stored := RefreshToken{
+		TokenHash: hashRefreshToken(refreshToken),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		ExpiresAt: pair.RefreshTokenExpiresAt,
+	}
+	return pair, stored, nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
 func (s *userService) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
@@ -155,14 +370,28 @@ func (s *userService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.repo.Delete(ctx, id)
 }
 
-func (s *userService) List(ctx context.Context, offset, limit int) ([]User, error) {
-	if offset < 0 {
-		offset = 0
+// List returns a page of every user, oldest account first
+func (s *userService) List(ctx context.Context, page pagination.Request) (pagination.Page[User], error) {
+	var after *User
+	if page.Cursor != "" {
+		after = &User{}
+		if err := pagination.Decode(page.Cursor, &after.CreatedAt, &after.ID); err != nil {
+			return pagination.Page[User]{}, err
+		}
+	}
+
+	// One extra user tells whether another page exists
+	size := page.PageSize()
+	users, err := s.repo.List(ctx, after, size+1)
+	if err != nil {
+		return pagination.Page[User]{}, err
 	}
-	limit = helper.Clamp(limit, 10, 100)
-	users, err := s.repo.List(ctx, offset, limit)
+	total, err := s.repo.Count(ctx)
 	if err != nil {
-		return nil, err
+		return pagination.Page[User]{}, err
 	}
-	return users, nil
+
+	return pagination.NewPage(users, size, total, func(user User) string {
+		return pagination.Encode(user.CreatedAt, user.ID)
+	}), nil
 }
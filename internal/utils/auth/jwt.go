@@ -9,12 +9,17 @@ import (
 )
 
 type JWT interface {
-	MakeJWT(userID uuid.UUID, roles []string) (string, error)
+	MakeJWT(userID uuid.UUID, roles []string, sessionID uuid.UUID) (string, error)
 	ValidateJWT(tokenString string) (uuid.UUID, error)
+	ParseJWT(tokenString string) (UserClaims, error)
+	AccessTTL() time.Duration
 }
 
+// UserClaims carries the token ID in the standard jti claim and the login
+// session it was issued for in sid
 type UserClaims struct {
-	Roles []string `json:"roles"`
+	Roles     []string `json:"roles"`
+	SessionID string   `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -28,10 +33,17 @@ func NewJWTManager(secretKey []byte, expiresIn time.Duration) JWT {
 	return &JWTManager{SecretKey: secretKey, ExpiresIn: expiresIn}
 }
 
-func (j *JWTManager) MakeJWT(userID uuid.UUID, roles []string) (string, error) {
+// AccessTTL returns how long issued access tokens stay valid
+func (j *JWTManager) AccessTTL() time.Duration {
+	return j.ExpiresIn
+}
+
+func (j *JWTManager) MakeJWT(userID uuid.UUID, roles []string, sessionID uuid.UUID) (string, error) {
 	claims := &UserClaims{
-		Roles: roles,
+		Roles:     roles,
+		SessionID: sessionID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    "fitrkr",
 			Subject:   userID.String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.ExpiresIn)),
@@ -49,19 +61,11 @@ func (j *JWTManager) MakeJWT(userID uuid.UUID, roles []string) (string, error) {
 }
 
 func (j *JWTManager) ValidateJWT(tokenString string) (uuid.UUID, error) {
-	var userClaims UserClaims
-
-	token, err := jwt.ParseWithClaims(tokenString, &userClaims, func(token *jwt.Token) (any, error) {
-		return []byte(j.SecretKey), nil
-	})
+	userClaims, err := j.ParseJWT(tokenString)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	if !token.Valid {
-		return uuid.Nil, nil
-	}
-
 	userID, err := userClaims.GetSubject()
 	if err != nil {
 		// Add custom logger and err later
@@ -69,5 +73,23 @@ func (j *JWTManager) ValidateJWT(tokenString string) (uuid.UUID, error) {
 	}
 
 	// Add custom logger and err later
-	return uuid.MustParse(userID), err
+	return uuid.Parse(userID)
+}
+
+// ParseJWT validates the token and returns all of its claims
+func (j *JWTManager) ParseJWT(tokenString string) (UserClaims, error) {
+	var userClaims UserClaims
+
+	token, err := jwt.ParseWithClaims(tokenString, &userClaims, func(token *jwt.Token) (any, error) {
+		return []byte(j.SecretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return UserClaims{}, err
+	}
+
+	if !token.Valid {
+		return UserClaims{}, jwt.ErrTokenInvalidClaims
+	}
+
+	return userClaims, nil
 }
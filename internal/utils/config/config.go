@@ -3,16 +3,44 @@ package config
 
 import (
 	"log"
+	"net/netip"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cheezecakee/fitrkr/internal/utils/auth"
+	"github.com/cheezecakee/fitrkr/internal/utils/mailer"
+	"github.com/cheezecakee/fitrkr/internal/utils/ratelimit"
 )
 
 type Config struct {
-	DBConnString string
-	Port         string
-	JWTManager   auth.JWT
+	DBConnString    string
+	Port            string
+	JWTManager      auth.JWT
+	RefreshTokenTTL time.Duration
+
+	// Password reset
+	Mailer           mailer.Mailer
+	PasswordResetURL string // the token is appended as a query parameter
+	PasswordResetTTL time.Duration
+
+	// Email verification
+	EmailVerificationURL string
+	EmailVerificationTTL time.Duration
+	RequireVerifiedEmail bool // unverified users cannot publish playlists or log sessions
+
+	// Brute-force protection
+	RateLimitStore        string          // "memory" or "postgres"
+	AuthRateLimit         ratelimit.Limit // per client IP
+	AuthIdentityRateLimit ratelimit.Limit // per email or username
+	LoginLockoutThreshold int             // failed logins before locking, 0 disables
+	LoginLockoutDuration  time.Duration   // first lock, doubled on each further failure
+	LoginLockoutMax       time.Duration
+	LoginFailureWindow    time.Duration // failures older than this are forgotten
+
+	// Proxies whose X-Forwarded-For is believed, none by default
+	TrustedProxies []netip.Prefix
 }
 
 func LoadConfig() Config {
@@ -32,12 +60,149 @@ func LoadConfig() Config {
 		log.Fatal("JWT_SECRET must be set")
 	}
 
-	// 15 day expiration
-	jwtManager := auth.NewJWTManager(jwtSecret, time.Hour*360)
+	// Short-lived access tokens, renewed with refresh tokens
+	accessTTL := durationEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	refreshTTL := durationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	jwtManager := auth.NewJWTManager(jwtSecret, accessTTL)
+
+	resetURL := os.Getenv("PASSWORD_RESET_URL")
+	if resetURL == "" {
+		resetURL = "http://localhost:5173/reset-password"
+	}
+
+	verifyURL := os.Getenv("EMAIL_VERIFICATION_URL")
+	if verifyURL == "" {
+		verifyURL = "http://localhost:5173/verify-email"
+	}
+
+	rateLimitStore := os.Getenv("RATE_LIMIT_STORE")
+	switch rateLimitStore {
+	case "":
+		rateLimitStore = "memory"
+	case "memory", "postgres":
+	default:
+		log.Fatalf("Unknown RATE_LIMIT_STORE %q", rateLimitStore)
+	}
 
 	return Config{
-		DBConnString: dbConn,
-		Port:         port,
-		JWTManager:   jwtManager,
+		DBConnString:     dbConn,
+		Port:             port,
+		JWTManager:       jwtManager,
+		RefreshTokenTTL:  refreshTTL,
+		Mailer:           loadMailer(),
+		PasswordResetURL: resetURL,
+		PasswordResetTTL: durationEnv("PASSWORD_RESET_TTL", time.Hour),
+
+		EmailVerificationURL: verifyURL,
+		EmailVerificationTTL: durationEnv("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+		RequireVerifiedEmail: boolEnv("REQUIRE_VERIFIED_EMAIL", false),
+
+		RateLimitStore:        rateLimitStore,
+		AuthRateLimit:         ratelimit.PerMinute(intEnv("AUTH_RATE_LIMIT_PER_MINUTE", 20)),
+		AuthIdentityRateLimit: ratelimit.PerMinute(intEnv("AUTH_IDENTITY_RATE_LIMIT_PER_MINUTE", 5)),
+		LoginLockoutThreshold: intEnv("LOGIN_LOCKOUT_THRESHOLD", 5),
+		LoginLockoutDuration:  durationEnv("LOGIN_LOCKOUT_DURATION", time.Minute),
+		LoginLockoutMax:       durationEnv("LOGIN_LOCKOUT_MAX", time.Hour),
+		LoginFailureWindow:    durationEnv("LOGIN_FAILURE_WINDOW", 24*time.Hour),
+
+		TrustedProxies: prefixesEnv("TRUSTED_PROXIES"),
+	}
+}
+
+// prefixesEnv reads comma separated addresses or CIDR ranges such as
+// "10.0.0.0/8,192.0.2.1" from the environment
+func prefixesEnv(key string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(value)
+			if addrErr != nil {
+				log.Fatalf("%s must list addresses or CIDR ranges, got %q", key, value)
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes
+}
+
+// intEnv reads a non-negative integer from the environment
+func intEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		log.Fatalf("%s must be a non-negative integer, got %q", key, value)
+	}
+	return parsed
+}
+
+// boolEnv reads a boolean such as "true" or "0" from the environment
+func boolEnv(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("%s must be a boolean, got %q", key, value)
+	}
+	return parsed
+}
+
+// loadMailer picks the mailer from MAIL_DRIVER: "smtp", "file" or "log" (default)
+func loadMailer() mailer.Mailer {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "FitTrkr <no-reply@fitrkr.local>"
+	}
+
+	switch driver := os.Getenv("MAIL_DRIVER"); driver {
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			log.Fatal("SMTP_HOST must be set when MAIL_DRIVER is smtp")
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return mailer.NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+	case "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "tmp/mail"
+		}
+		return mailer.NewFileMailer(dir, from)
+	case "", "log":
+		return mailer.NewLogMailer()
+	default:
+		log.Fatalf("Unknown MAIL_DRIVER %q", driver)
+		return nil
+	}
+}
+
+// durationEnv reads a duration such as "15m" or "720h" from the environment
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatalf("%s must be a positive duration, got %q", key, value)
 	}
+	return duration
 }
@@ -0,0 +1,83 @@
+// Package pagination provides opaque keyset cursors and the envelope list
+// endpoints respond with.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/helper"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Request asks for the page after Cursor. An empty cursor is the first page.
+type Request struct {
+	Cursor string
+	Limit  int
+}
+
+// PageSize returns Limit clamped to 1..MaxLimit, or DefaultLimit when unset
+func (r Request) PageSize() int {
+	if r.Limit <= 0 {
+		return DefaultLimit
+	}
+	return helper.Clamp(r.Limit, 1, MaxLimit)
+}
+
+// Page is one page of a list
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"` // nil on the last page
+	Total      int     `json:"total"`       // items in the whole list
+}
+
+// NewPage builds a page out of items fetched with a limit of size+1. The
+// extra item only tells that another page exists; the next cursor points at
+// the last item kept, as returned by cursor.
+func NewPage[T any](items []T, size, total int, cursor func(T) string) Page[T] {
+	page := Page[T]{Items: items, Total: total}
+	if len(items) > size {
+		page.Items = items[:size]
+		next := cursor(page.Items[size-1])
+		page.NextCursor = &next
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page
+}
+
+// Encode packs the sort key values of the last item of a page into an
+// opaque cursor
+func Encode(values ...any) string {
+	// Sort keys are strings, numbers and times, which always marshal
+	raw, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode unpacks a cursor made by Encode into values, which must be
+// pointers to the same types in the same order
+func Decode(cursor string, values ...any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(raw, &parts); err != nil || len(parts) != len(values) {
+		return ErrInvalidCursor
+	}
+	for i, part := range parts {
+		if err := json.Unmarshal(part, values[i]); err != nil {
+			return ErrInvalidCursor
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/cheezecakee/fitrkr/internal/utils/transaction"
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every
+// instance shares them
+type PostgresStore struct {
+	tx    transaction.BaseRepository
+	takes atomic.Int64
+}
+
+func NewPostgresStore(db *sql.DB) Store {
+	return &PostgresStore{
+		tx: transaction.NewBaseRepository(db),
+	}
+}
+
+const createBucket = `
+	INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+	VALUES ($1, $2, NOW())
+	ON CONFLICT (key) DO NOTHING`
+
+// The database clock is used so instances agree on elapsed time
+const lockBucket = `
+	SELECT tokens, EXTRACT(EPOCH FROM NOW() - updated_at)
+	FROM rate_limit_buckets
+	WHERE key = $1
+	FOR UPDATE`
+
+const updateBucket = `
+	UPDATE rate_limit_buckets
+	SET tokens = $2, updated_at = NOW()
+	WHERE key = $1`
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	if s.takes.Add(1)%sweepEvery == 0 {
+		s.sweep(ctx)
+	}
+
+	var result Result
+	err := s.tx.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, createBucket, key, limit.Burst); err != nil {
+			return err
+		}
+
+		var tokens, elapsed float64
+		if err := tx.QueryRowContext(ctx, lockBucket, key).Scan(&tokens, &elapsed); err != nil {
+			return err
+		}
+
+		tokens, result = take(tokens, time.Duration(elapsed*float64(time.Second)), limit)
+		_, err := tx.ExecContext(ctx, updateBucket, key, tokens)
+		return err
+	})
+	if err != nil {
+		log.Printf("Rate limit take failed for %s: %v", key, err)
+		return Result{}, err
+	}
+	return result, nil
+}
+
+const deleteIdleBuckets = `
+	DELETE FROM rate_limit_buckets
+	WHERE updated_at < NOW() - make_interval(secs => $1)`
+
+// sweep drops buckets untouched for idleBucketAge. Every instance sweeps on
+// its own count, which is harmless since the delete is idempotent.
+func (s *PostgresStore) sweep(ctx context.Context) {
+	_, err := s.tx.DB().ExecContext(ctx, deleteIdleBuckets, idleBucketAge.Seconds())
+	if err != nil {
+		// Continue - the rows are removed on a later sweep
+		log.Printf("Rate limit sweep failed: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+// Package ratelimit provides token bucket rate limiting for FitTrkr.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit allows Burst requests at once, refilled evenly over Period
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// PerMinute allows n requests a minute with a burst of n
+func PerMinute(n int) Limit {
+	return Limit{Burst: n, Period: time.Minute}
+}
+
+// rate is the number of tokens added per second
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// Result of taking a token. RetryAfter is set when the request is denied.
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+type Store interface {
+	// Takes a token from the bucket of key, creating a full bucket if needed
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// take refills a bucket holding tokens after elapsed and takes one token
+// if there is one. It returns the tokens left.
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.rate())
+	if tokens >= 1 {
+		return tokens - 1, Result{Allowed: true}
+	}
+
+	wait := math.Ceil((1 - tokens) / limit.rate())
+	return tokens, Result{RetryAfter: time.Duration(wait) * time.Second}
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryStore keeps buckets in process. Use PostgresStore when running more
+// than one instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+}
+
+func NewMemoryStore() Store {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+// sweepEvery is how many takes pass between removing idle buckets
+const sweepEvery = 1000
+
+// idleBucketAge is how long a bucket stays untouched before it is removed.
+// Buckets that old are full again for any limit with a period up to it.
+const idleBucketAge = time.Hour
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.takes++
+	if s.takes%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	var result Result
+	b.tokens, result = take(b.tokens, now.Sub(b.updatedAt), limit)
+	b.updatedAt = now
+	return result, nil
+}
+
+// sweep drops buckets untouched for idleBucketAge
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.updatedAt) > idleBucketAge {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+-- +goose Up
+CREATE TABLE sessions (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    playlist_id INT REFERENCES playlists(id) ON DELETE SET NULL, -- Keep history if the playlist is deleted
+    title VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress', -- 'in_progress', 'paused', 'completed'
+    snapshot JSONB NOT NULL DEFAULT '{}', -- Playlist tree as it was when the session started
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    paused_at TIMESTAMP,
+    paused_seconds INT NOT NULL DEFAULT 0, -- Total time spent paused
+    finished_at TIMESTAMP,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE session_exercises (
+    id SERIAL PRIMARY KEY,
+    session_id INT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    playlist_exercise_id INT REFERENCES playlist_exercises(id) ON DELETE SET NULL,
+    config_id INT REFERENCES exercise_configs(id) ON DELETE SET NULL,
+    exercise_id INT NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    exercise_name VARCHAR(100) NOT NULL,
+    block_name VARCHAR(100),
+    block_type VARCHAR(20) NOT NULL,
+    block_order INT NOT NULL,
+    exercise_order INT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE exercise_sets (
+    id SERIAL PRIMARY KEY,
+    session_exercise_id INT NOT NULL REFERENCES session_exercises(id) ON DELETE CASCADE,
+    set_number INT NOT NULL,
+
+    -- Targets copied from the config at start time
+    target_reps_min INT,
+    target_reps_max INT,
+    target_weight NUMERIC(6,2),
+
+    -- What was actually done
+    reps INT,
+    weight NUMERIC(6,2),
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT unique_session_exercise_set UNIQUE (session_exercise_id, set_number)
+);
+
+-- Link the logs table to sessions
+ALTER TABLE logs
+    ADD CONSTRAINT fk_logs_session FOREIGN KEY (session) REFERENCES sessions(id) ON DELETE SET NULL;
+
+-- Indexes
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_playlist_id ON sessions(playlist_id);
+CREATE INDEX idx_sessions_status ON sessions(status);
+CREATE INDEX idx_session_exercises_session_id ON session_exercises(session_id);
+CREATE INDEX idx_session_exercises_exercise_id ON session_exercises(exercise_id);
+CREATE INDEX idx_exercise_sets_session_exercise_id ON exercise_sets(session_exercise_id);
+
+-- Only one unfinished session per user at a time
+CREATE UNIQUE INDEX idx_sessions_one_active_per_user ON sessions(user_id) WHERE status <> 'completed';
+
+-- Triggers
+CREATE TRIGGER update_sessions_timestamp
+    BEFORE UPDATE ON sessions
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+
+CREATE TRIGGER update_session_exercises_timestamp
+    BEFORE UPDATE ON session_exercises
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+
+CREATE TRIGGER update_exercise_sets_timestamp
+    BEFORE UPDATE ON exercise_sets
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+
+-- +goose Down
+ALTER TABLE logs DROP CONSTRAINT IF EXISTS fk_logs_session;
+DROP TABLE IF EXISTS exercise_sets;
+DROP TABLE IF EXISTS session_exercises;
+DROP TABLE IF EXISTS sessions;
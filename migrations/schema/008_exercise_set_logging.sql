@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE exercise_sets
+    ADD COLUMN set_kind VARCHAR(20) NOT NULL DEFAULT 'working', -- 'warmup', 'working', 'drop', 'failure'
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending',   -- 'pending', 'completed', 'skipped'
+    ADD COLUMN rpe NUMERIC(3,1) CHECK (rpe BETWEEN 1 AND 10),   -- Rate of perceived exertion
+    ADD COLUMN rir INT CHECK (rir >= 0),                        -- Reps in reserve
+    ADD COLUMN started_at TIMESTAMP,
+    ADD COLUMN completed_at TIMESTAMP;
+
+UPDATE exercise_sets SET status = 'completed' WHERE completed;
+
+ALTER TABLE exercise_sets DROP COLUMN completed;
+
+-- +goose Down
+ALTER TABLE exercise_sets ADD COLUMN completed BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE exercise_sets SET completed = TRUE WHERE status = 'completed';
+
+ALTER TABLE exercise_sets
+    DROP COLUMN completed_at,
+    DROP COLUMN started_at,
+    DROP COLUMN rir,
+    DROP COLUMN rpe,
+    DROP COLUMN status,
+    DROP COLUMN set_kind;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE cardio_entries (
+    id SERIAL PRIMARY KEY,
+    session_exercise_id INT NOT NULL REFERENCES session_exercises(id) ON DELETE CASCADE,
+    entry_number INT NOT NULL, -- Interval number within the exercise
+
+    duration_seconds INT CHECK (duration_seconds >= 0),
+    distance NUMERIC(6,2) CHECK (distance >= 0),
+    avg_heart_rate INT CHECK (avg_heart_rate > 0),
+    max_heart_rate INT CHECK (max_heart_rate > 0),
+    avg_pace NUMERIC(5,2) CHECK (avg_pace >= 0), -- minutes per mile/km
+    incline NUMERIC(4,1),
+    laps JSONB NOT NULL DEFAULT '[]', -- [{"lap_number": 1, "duration_seconds": 300, "distance": 1.0}]
+
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT unique_session_exercise_entry UNIQUE (session_exercise_id, entry_number)
+);
+
+CREATE INDEX idx_cardio_entries_session_exercise_id ON cardio_entries(session_exercise_id);
+
+CREATE TRIGGER update_cardio_entries_timestamp
+    BEFORE UPDATE ON cardio_entries
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+
+-- +goose Down
+DROP TABLE IF EXISTS cardio_entries;
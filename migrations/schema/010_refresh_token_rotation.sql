@@ -0,0 +1,17 @@
+-- +goose Up
+-- Tokens are stored as SHA-256 hashes. Every login starts a family, each
+-- refresh replaces the presented token with a new one of the same family.
+DELETE FROM refresh_tokens;
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id UUID NOT NULL,
+    ADD COLUMN replaced_by VARCHAR(64);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+
+-- +goose Down
+DROP INDEX idx_refresh_tokens_family_id;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN replaced_by,
+    DROP COLUMN family_id;
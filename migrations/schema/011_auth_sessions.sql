@@ -0,0 +1,43 @@
+-- +goose Up
+-- A login on one device. Refresh token families and the sid claim of
+-- access tokens point at it, revoking it logs that device out.
+CREATE TABLE auth_sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_sessions_user_id ON auth_sessions(user_id);
+
+CREATE TRIGGER update_auth_sessions_timestamp
+    BEFORE UPDATE ON auth_sessions
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
+
+-- Families created before sessions existed cannot be listed, log them out
+DELETE FROM refresh_tokens;
+
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT fk_refresh_tokens_family
+    FOREIGN KEY (family_id) REFERENCES auth_sessions(id) ON DELETE CASCADE;
+
+-- Access tokens logged out before they expire, by jti
+CREATE TABLE revoked_access_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
+
+-- +goose Down
+DROP TABLE revoked_access_tokens;
+
+ALTER TABLE refresh_tokens DROP CONSTRAINT fk_refresh_tokens_family;
+
+DROP TABLE auth_sessions;
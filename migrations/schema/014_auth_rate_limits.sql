@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE rate_limit_buckets (
+    key VARCHAR(320) PRIMARY KEY, -- e.g. login:ip:203.0.113.7, identities are stored as SHA-256 hex
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_rate_limit_buckets_updated_at ON rate_limit_buckets(updated_at);
+
+CREATE TABLE login_lockouts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE login_lockouts;
+
+DROP TABLE rate_limit_buckets;
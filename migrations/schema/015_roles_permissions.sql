@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Permission names are defined in code, see internal/db/user/permissions.go
+CREATE TABLE role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('user', 'Default role of every account'),
+    ('admin', 'Full access'),
+    ('catalog_editor', 'Edits the exercise catalog'),
+    ('moderator', 'Moderates user accounts');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'exercise:write'),
+    ('admin', 'catalog:manage'),
+    ('admin', 'user:moderate'),
+    ('admin', 'role:manage'),
+    ('catalog_editor', 'exercise:write'),
+    ('catalog_editor', 'catalog:manage'),
+    ('moderator', 'user:moderate');
+
+-- +goose Down
+DROP TABLE role_permissions;
+
+DROP TABLE roles;
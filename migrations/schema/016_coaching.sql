@@ -0,0 +1,36 @@
+-- +goose Up
+INSERT INTO roles (name, description) VALUES
+    ('coach', 'Manages playlists of accepted clients');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'client:coach'),
+    ('coach', 'client:coach');
+
+CREATE TABLE coach_clients (
+    id SERIAL PRIMARY KEY,
+    coach_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'declined', 'revoked')),
+    responded_at TIMESTAMP,
+    ended_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (coach_id <> client_id)
+);
+
+-- One open invitation or active relationship per pair
+CREATE UNIQUE INDEX idx_coach_clients_open ON coach_clients(coach_id, client_id)
+    WHERE status IN ('pending', 'accepted');
+CREATE INDEX idx_coach_clients_client_id ON coach_clients(client_id);
+
+-- Coach that created the playlist in a client's account
+ALTER TABLE playlists ADD COLUMN assigned_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE playlists DROP COLUMN assigned_by;
+
+DROP TABLE coach_clients;
+
+DELETE FROM roles WHERE name = 'coach';
+DELETE FROM role_permissions WHERE permission = 'client:coach';
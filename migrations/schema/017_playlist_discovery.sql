@@ -0,0 +1,12 @@
+-- +goose Up
+-- Incremented when a playlist is copied
+ALTER TABLE playlists ADD COLUMN copy_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_playlists_public_created_at ON playlists(created_at DESC, id DESC) WHERE visibility = 'public';
+CREATE INDEX idx_playlists_public_copy_count ON playlists(copy_count DESC) WHERE visibility = 'public';
+
+-- +goose Down
+DROP INDEX idx_playlists_public_copy_count;
+DROP INDEX idx_playlists_public_created_at;
+
+ALTER TABLE playlists DROP COLUMN copy_count;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE playlist_share_tokens (
+    id SERIAL PRIMARY KEY,
+    playlist_id INT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP, -- NULL never expires
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_playlist_share_tokens_playlist_id ON playlist_share_tokens(playlist_id);
+
+-- +goose Down
+DROP TABLE playlist_share_tokens;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Set on copies, kept NULL when the original is deleted
+ALTER TABLE playlists ADD COLUMN source_playlist_id INT REFERENCES playlists(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_playlists_source_playlist_id ON playlists(source_playlist_id);
+
+-- +goose Down
+DROP INDEX idx_playlists_source_playlist_id;
+
+ALTER TABLE playlists DROP COLUMN source_playlist_id;
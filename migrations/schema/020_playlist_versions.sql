@@ -0,0 +1,15 @@
+-- +goose Up
+-- Immutable snapshots of the playlist tree, one per change
+CREATE TABLE playlist_versions (
+    id SERIAL PRIMARY KEY,
+    playlist_id INT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    change VARCHAR(30) NOT NULL, -- what produced this version, e.g. 'config_updated'
+    snapshot JSONB NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_playlist_version UNIQUE (playlist_id, version)
+);
+
+-- +goose Down
+DROP TABLE playlist_versions;
@@ -0,0 +1,74 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Name weighs most, then description, then category and equipment names
+ALTER TABLE exercises ADD COLUMN search_vector tsvector NOT NULL DEFAULT ''::tsvector;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION exercise_search_vector(ex_name TEXT, ex_description TEXT, ex_category_id INT, ex_equipment_id INT) RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('english', coalesce(ex_name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(ex_description, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce((SELECT name FROM exercise_categories WHERE id = ex_category_id), '')), 'C') ||
+        setweight(to_tsvector('english', coalesce((SELECT name FROM equipment WHERE id = ex_equipment_id), '')), 'C');
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_exercise_search_vector() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector = exercise_search_vector(NEW.name, NEW.description, NEW.category_id, NEW.equipment_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER update_exercises_search_vector
+    BEFORE INSERT OR UPDATE OF name, description, category_id, equipment_id ON exercises
+    FOR EACH ROW EXECUTE FUNCTION update_exercise_search_vector();
+
+-- Renaming a category or equipment changes the vectors of its exercises
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_exercise_search_vectors() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'exercise_categories' THEN
+        UPDATE exercises
+        SET search_vector = exercise_search_vector(name, description, category_id, equipment_id)
+        WHERE category_id = NEW.id;
+    ELSE
+        UPDATE exercises
+        SET search_vector = exercise_search_vector(name, description, category_id, equipment_id)
+        WHERE equipment_id = NEW.id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER refresh_category_exercise_search_vectors
+    AFTER UPDATE OF name ON exercise_categories
+    FOR EACH ROW EXECUTE FUNCTION refresh_exercise_search_vectors();
+
+CREATE TRIGGER refresh_equipment_exercise_search_vectors
+    AFTER UPDATE OF name ON equipment
+    FOR EACH ROW EXECUTE FUNCTION refresh_exercise_search_vectors();
+
+UPDATE exercises
+SET search_vector = exercise_search_vector(name, description, category_id, equipment_id);
+
+CREATE INDEX idx_exercises_search_vector ON exercises USING GIN (search_vector);
+-- Typo tolerant name matching
+CREATE INDEX idx_exercises_name_trgm ON exercises USING GIN (name gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX idx_exercises_name_trgm;
+DROP INDEX idx_exercises_search_vector;
+
+DROP TRIGGER refresh_equipment_exercise_search_vectors ON equipment;
+DROP TRIGGER refresh_category_exercise_search_vectors ON exercise_categories;
+DROP TRIGGER update_exercises_search_vector ON exercises;
+
+DROP FUNCTION refresh_exercise_search_vectors();
+DROP FUNCTION update_exercise_search_vector();
+DROP FUNCTION exercise_search_vector(TEXT, TEXT, INT, INT);
+
+ALTER TABLE exercises DROP COLUMN search_vector;